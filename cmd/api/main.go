@@ -7,6 +7,7 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // CALENDAR_TIMEZONE не зависит от zoneinfo в образе
 
 	"restapi/internal/app"
 	"restapi/internal/config"
@@ -35,6 +36,15 @@ func main() {
 	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	// subcommand: api migrate ...
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(ctx, cfg, os.Args[2:]); err != nil {
+			applog.Error("migrate failed", "err", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// init app
 	a, err := app.NewApp(cfg, ctx)
 	if err != nil {
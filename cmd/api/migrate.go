@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"restapi/internal/config"
+	"restapi/internal/infrastructure/postgres"
+	"restapi/migrations"
+)
+
+const migrateUsage = `usage: api migrate <command>
+  up           применить все миграции
+  down [N]     откатить N последних миграций (по умолчанию 1)
+  to N         привести схему к версии N (0 — откатить всё)
+  version      показать текущую версию
+  force N      записать версию N без выполнения миграций (снимает dirty)`
+
+// runMigrate выполняет подкоманду migrate с тем же конфигом Postgres, что и сервер.
+func runMigrate(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	pool, err := postgres.NewPgPool(ctx, &cfg.Postgres)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	m, err := postgres.NewMigrator(pool, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	switch cmd, rest := args[0], args[1:]; cmd {
+	case "up":
+		return m.Up(ctx)
+	case "down":
+		steps := 1
+		if len(rest) > 0 {
+			if steps, err = strconv.Atoi(rest[0]); err != nil || steps <= 0 {
+				return fmt.Errorf("down: bad step count %q", rest[0])
+			}
+		}
+		return m.Down(ctx, steps)
+	case "to":
+		v, err := versionArg(cmd, rest)
+		if err != nil {
+			return err
+		}
+		return m.To(ctx, v)
+	case "force":
+		v, err := versionArg(cmd, rest)
+		if err != nil {
+			return err
+		}
+		return m.Force(ctx, v)
+	case "version":
+		v, dirty, err := m.Version(ctx)
+		if err != nil {
+			return err
+		}
+		if v == postgres.NilVersion {
+			fmt.Println("no migrations applied")
+			return nil
+		}
+		fmt.Printf("%d (dirty=%t)\n", v, dirty)
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", cmd, migrateUsage)
+	}
+}
+
+// versionArg разбирает N для to/force; 0 означает «без миграций».
+func versionArg(cmd string, args []string) (int64, error) {
+	if len(args) != 1 {
+		return 0, fmt.Errorf("%s: version is required\n%s", cmd, migrateUsage)
+	}
+	v, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("%s: bad version %q", cmd, args[0])
+	}
+	if v == 0 {
+		return postgres.NilVersion, nil
+	}
+	return v, nil
+}
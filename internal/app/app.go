@@ -2,20 +2,31 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"time"
 
 	"restapi/internal/config"
 	"restapi/internal/infrastructure/postgres"
+	"restapi/internal/infrastructure/redis"
 	log "restapi/internal/logger"
+	"restapi/internal/service"
 	httptransport "restapi/internal/transport/http"
+	"restapi/migrations"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	goredis "github.com/redis/go-redis/v9"
 )
 
+// sessionPurgeInterval — как часто чистить протухшие сессии.
+const sessionPurgeInterval = 10 * time.Minute
+
 type App struct {
 	server *httptransport.Server
 	pgPool *pgxpool.Pool
+	redis  *goredis.Client // nil, если Redis не используется
+
+	stopBackground context.CancelFunc
 }
 
 func NewApp(cfg *config.Config, ctx context.Context) (*App, error) {
@@ -25,12 +36,100 @@ func NewApp(cfg *config.Config, ctx context.Context) (*App, error) {
 		return nil, err
 	}
 
+	if cfg.App.MigrateOnStart {
+		m, err := postgres.NewMigrator(pgPool, migrations.FS)
+		if err != nil {
+			pgPool.Close()
+			return nil, err
+		}
+		if err := m.Up(ctx); err != nil {
+			pgPool.Close()
+			return nil, fmt.Errorf("migrate on start: %w", err)
+		}
+	}
+
+	execRepo := postgres.NewExecRepo(pgPool)
+	sessionRepo := postgres.NewSessionRepo(pgPool)
+
+	execs := service.NewExecService(execRepo, sessionRepo)
+	created, err := execs.Bootstrap(ctx, cfg.Auth.BootstrapUsername, cfg.Auth.BootstrapPassword, cfg.Auth.BootstrapEmail)
+	if err != nil {
+		pgPool.Close()
+		return nil, err
+	}
+	if created {
+		log.Info("bootstrap exec created", "username", cfg.Auth.BootstrapUsername)
+	}
+
+	// Генерация расписания идёт в памяти процесса: после перезапуска незавершённые задачи не продолжатся.
+	if n, err := postgres.NewTimetableDraftRepo(pgPool).FailInterrupted(ctx); err != nil {
+		log.Warn("fail interrupted timetable drafts", "err", err)
+	} else if n > 0 {
+		log.Info("interrupted timetable drafts marked failed", "count", n)
+	}
+
+	// Один AuthService на API и фоновую чистку сессий.
+	authSvc, err := service.NewAuthService(execRepo, sessionRepo, postgres.NewCalendarTokenRepo(pgPool), cfg.Auth.SessionTTL, cfg.Auth.SessionMaxLifetime)
+	if err != nil {
+		pgPool.Close()
+		return nil, err
+	}
+
+	var rdb *goredis.Client
+	if cfg.RateLimit.Backend == "redis" {
+		rdb = redis.NewClient(&cfg.Redis)
+		if err := redis.Ping(ctx, rdb, &cfg.Redis); err != nil {
+			if !cfg.RateLimit.FailOpen {
+				_ = rdb.Close()
+				pgPool.Close()
+				return nil, err
+			}
+			// Fail-open и при старте: клиент переподключится сам, а пока лимитер пропускает запросы.
+			log.Warn("redis unavailable on start, rate limits fail open", "addr", cfg.Redis.Addr, "err", err)
+		}
+	}
+
+	server, err := httptransport.NewServer(cfg, pgPool, rdb, authSvc)
+	if err != nil {
+		if rdb != nil {
+			_ = rdb.Close()
+		}
+		pgPool.Close()
+		return nil, err
+	}
+
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	go purgeSessionsLoop(bgCtx, authSvc)
+
 	return &App{
-		server: httptransport.NewServer(cfg),
-		pgPool: pgPool,
+		server:         server,
+		pgPool:         pgPool,
+		redis:          rdb,
+		stopBackground: stopBackground,
 	}, nil
 }
 
+func purgeSessionsLoop(ctx context.Context, auth *service.AuthService) {
+	ticker := time.NewTicker(sessionPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := auth.PurgeExpired(ctx)
+			if err != nil {
+				log.Warn("purge expired sessions", "err", err)
+				continue
+			}
+			if n > 0 {
+				log.Debug("expired sessions purged", "count", n)
+			}
+		}
+	}
+}
+
 // Run запускает сервер (блокирующий вызов). Для graceful shutdown используй RunWithContext.
 func (a *App) Run() error {
 	return a.server.Run()
@@ -38,6 +137,10 @@ func (a *App) Run() error {
 
 // Shutdown останавливает сервер и закрывает ресурсы (graceful). Передай context с таймаутом.
 func (a *App) Shutdown(ctx context.Context) error {
+	if a.stopBackground != nil {
+		a.stopBackground()
+	}
+
 	var srvErr error
 	if a.server != nil {
 		srvErr = a.server.Shutdown(ctx)
@@ -46,6 +149,11 @@ func (a *App) Shutdown(ctx context.Context) error {
 	if a.pgPool != nil {
 		a.pgPool.Close()
 	}
+	if a.redis != nil {
+		if err := a.redis.Close(); err != nil {
+			log.Warn("close redis client", "err", err)
+		}
+	}
 
 	return srvErr
 }
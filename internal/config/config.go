@@ -3,6 +3,10 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/netip"
+	"os"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
@@ -12,7 +16,15 @@ type Config struct {
 	App      App
 	Log      Log
 	Postgres Postgres
-	// Redis    Redis    `env-prefix:""`
+	Auth     Auth
+	Calendar Calendar
+	School   School
+	Storage  Storage
+
+	Middlewares Middlewares
+	Cors        Cors
+	RateLimit   RateLimit
+	Redis       Redis `env-prefix:""`
 }
 
 type Log struct {
@@ -21,16 +33,152 @@ type Log struct {
 }
 
 type App struct {
-	Env  string `env:"APP_ENV"` // local|stage|prod
-	HTTP struct {
+	Env            string `env:"APP_ENV"`                              // local|stage|prod
+	MigrateOnStart bool   `env:"MIGRATE_ON_START" env-default:"false"` // применить миграции до старта сервера
+	HTTP           struct {
 		Addr              string        `env:"HTTP_ADDR" env-default:":8080"`
 		ReadHeaderTimeout time.Duration `env:"HTTP_READ_HEADER_TIMEOUT" env-default:"5s"`
 		ReadTimeout       time.Duration `env:"HTTP_READ_TIMEOUT" env-default:"15s"`
 		WriteTimeout      time.Duration `env:"HTTP_WRITE_TIMEOUT" env-default:"15s"`
 		IdleTimeout       time.Duration `env:"HTTP_IDLE_TIMEOUT" env-default:"60s"`
+
+		// Сети обратных прокси (nginx, балансировщик), от которых принимаются X-Forwarded-For,
+		// X-Real-IP и Forwarded: CIDR или адреса через запятую. Пусто — адрес клиента из соединения.
+		TrustedProxies []string `env:"HTTP_TRUSTED_PROXIES"`
 	} `env-prefix:""`
 }
 
+type Auth struct {
+	// Сессия продлевается при активности (sliding), но не дольше SessionMaxLifetime от входа.
+	SessionTTL         time.Duration `env:"AUTH_SESSION_TTL" env-default:"30m"`
+	SessionMaxLifetime time.Duration `env:"AUTH_SESSION_MAX_LIFETIME" env-default:"12h"`
+	CookieName         string        `env:"AUTH_COOKIE_NAME" env-default:"session"`
+	CookieSecure       bool          `env:"AUTH_COOKIE_SECURE" env-default:"true"`
+
+	// Сколько действует код приглашения представителя (родителя).
+	GuardianInviteTTL time.Duration `env:"AUTH_GUARDIAN_INVITE_TTL" env-default:"168h"`
+
+	// Первый exec создаётся при старте, если таблица пуста (иначе войти будет некому).
+	BootstrapUsername string `env:"AUTH_BOOTSTRAP_USERNAME"`
+	BootstrapPassword string `env:"AUTH_BOOTSTRAP_PASSWORD"`
+	BootstrapEmail    string `env:"AUTH_BOOTSTRAP_EMAIL" env-default:"admin@localhost"`
+}
+
+// Calendar — параметры iCal-лент расписания: часовой пояс школы и звонки.
+type Calendar struct {
+	Timezone       string        `env:"CALENDAR_TIMEZONE" env-default:"Europe/Moscow"`
+	FirstLesson    string        `env:"CALENDAR_FIRST_LESSON" env-default:"08:30"` // HH:MM
+	LessonDuration time.Duration `env:"CALENDAR_LESSON_DURATION" env-default:"45m"`
+	BreakDuration  time.Duration `env:"CALENDAR_BREAK_DURATION" env-default:"10m"`
+}
+
+// Location — часовой пояс школы.
+func (c Calendar) Location() (*time.Location, error) {
+	return time.LoadLocation(c.Timezone)
+}
+
+// FirstLessonOffset — начало первого урока от полуночи.
+func (c Calendar) FirstLessonOffset() (time.Duration, error) {
+	t, err := time.Parse("15:04", c.FirstLesson)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// School — оформление печатных документов (табели, выписки): название, адрес, подпись директора, логотип.
+type School struct {
+	Name        string `env:"SCHOOL_NAME" env-default:"School"`
+	Address     string `env:"SCHOOL_ADDRESS"`
+	Principal   string `env:"SCHOOL_PRINCIPAL"`                          // ФИО директора в блоке подписей
+	AccentColor string `env:"SCHOOL_ACCENT_COLOR" env-default:"#1F4E79"` // #RRGGBB
+	LogoPath    string `env:"SCHOOL_LOGO_PATH"`                          // JPEG, необязательно
+	PublicURL   string `env:"SCHOOL_PUBLIC_URL"`                         // адрес API в ссылках проверки выписки и скачивания файлов
+}
+
+// Logo — содержимое файла логотипа (nil, если не задан).
+func (s School) Logo() ([]byte, error) {
+	if s.LogoPath == "" {
+		return nil, nil
+	}
+	return os.ReadFile(s.LogoPath)
+}
+
+// Storage — хранилище загруженных файлов (фотографии учеников, справки к объяснительным, домашние задания)
+// и ограничения на загрузку. Файлы отдаются только по подписанным ссылкам со сроком действия URLTTL.
+type Storage struct {
+	Backend    string        `env:"STORAGE_BACKEND" env-default:"local"`          // local | s3
+	LocalDir   string        `env:"STORAGE_LOCAL_DIR" env-default:"./data/files"` // каталог локального хранилища
+	SigningKey string        `env:"STORAGE_SIGNING_KEY"`                          // ключ подписи ссылок local; пусто — случайный до перезапуска
+	URLTTL     time.Duration `env:"STORAGE_URL_TTL" env-default:"15m"`
+
+	MaxRequestSize int64    `env:"STORAGE_MAX_REQUEST_SIZE" env-default:"67108864"` // тело multipart-запроса, 64 MiB
+	MaxFileSize    int64    `env:"STORAGE_MAX_FILE_SIZE" env-default:"10485760"`    // документ, 10 MiB
+	MaxPhotoSize   int64    `env:"STORAGE_MAX_PHOTO_SIZE" env-default:"2097152"`    // фотография, 2 MiB
+	DocumentTypes  []string `env:"STORAGE_DOCUMENT_TYPES" env-default:"application/pdf,image/jpeg,image/png,image/webp,text/plain,application/vnd.openxmlformats-officedocument.wordprocessingml.document,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/vnd.openxmlformats-officedocument.presentationml.presentation,application/vnd.oasis.opendocument.text,application/vnd.oasis.opendocument.spreadsheet"`
+	PhotoTypes     []string `env:"STORAGE_PHOTO_TYPES" env-default:"image/jpeg,image/png,image/webp"`
+
+	S3 S3 `env-prefix:""`
+}
+
+// S3 — S3-совместимое хранилище (AWS S3, MinIO) для STORAGE_BACKEND=s3.
+type S3 struct {
+	Endpoint  string `env:"S3_ENDPOINT"` // https://s3.eu-central-1.amazonaws.com, http://localhost:9000
+	Region    string `env:"S3_REGION" env-default:"us-east-1"`
+	Bucket    string `env:"S3_BUCKET"`
+	AccessKey string `env:"S3_ACCESS_KEY"`
+	SecretKey string `env:"S3_SECRET_KEY"`
+	PathStyle bool   `env:"S3_PATH_STYLE" env-default:"false"` // true для MinIO
+}
+
+// Middlewares — глобальная цепочка middleware. Order задаёт порядок (первый — самый внешний),
+// флаги *Enabled позволяют выключить отдельное звено без правки порядка.
+type Middlewares struct {
+	Order []string `env:"MW_ORDER" env-default:"request_id,response_time,security_headers,cors,rate_limit,compression"`
+
+	RequestIDEnabled       bool `env:"MW_REQUEST_ID_ENABLED" env-default:"true"`
+	ResponseTimeEnabled    bool `env:"MW_RESPONSE_TIME_ENABLED" env-default:"true"`
+	SecurityHeadersEnabled bool `env:"MW_SECURITY_HEADERS_ENABLED" env-default:"true"`
+	CorsEnabled            bool `env:"MW_CORS_ENABLED" env-default:"true"`
+	RateLimitEnabled       bool `env:"MW_RATE_LIMIT_ENABLED" env-default:"true"`
+	CompressionEnabled     bool `env:"MW_COMPRESSION_ENABLED" env-default:"true"`
+}
+
+// Cors — политика CORS для браузерного фронтенда. Запросы без Origin (curl, сервер-сервер) она не затрагивает.
+// Origins — точные origin или шаблоны поддоменов вида https://*.example.com; "*" — любой, но без credentials.
+type Cors struct {
+	Origins          []string      `env:"CORS_ALLOWED_ORIGINS" env-default:"http://localhost:3000"`
+	Methods          []string      `env:"CORS_ALLOWED_METHODS" env-default:"GET,POST,PUT,PATCH,DELETE"`
+	Headers          []string      `env:"CORS_ALLOWED_HEADERS" env-default:"Content-Type,Authorization,X-Request-ID"`
+	ExposedHeaders   []string      `env:"CORS_EXPOSED_HEADERS" env-default:"X-Request-ID,Content-Disposition,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,Retry-After"`
+	AllowCredentials bool          `env:"CORS_ALLOW_CREDENTIALS" env-default:"true"` // cookie сессии
+	MaxAge           time.Duration `env:"CORS_MAX_AGE" env-default:"1h"`             // кеш preflight в браузере
+}
+
+// RateLimit — лимиты запросов. Backend memory считает квоты в каждой реплике отдельно,
+// redis — одни на все реплики; FailOpen решает, пропускать ли запросы, когда Redis недоступен
+// (в том числе при старте: с fail-open приложение поднимается без Redis).
+//
+// Политики: общая на IP (звено rate_limit глобальной цепочки), на учётку для чтения и для изменений
+// (маршруты с сессией), вход — на IP и отдельно на имя пользователя (подбор пароля).
+// Квоты на ключ API появятся вместе с выдачей ключей: непроверенный заголовок клиент меняет на каждый запрос.
+type RateLimit struct {
+	Backend  string        `env:"RATE_LIMIT_BACKEND" env-default:"memory"` // memory | redis
+	TTL      time.Duration `env:"RATE_LIMIT_TTL" env-default:"10m"`        // сколько помнить неактивного клиента (memory)
+	FailOpen bool          `env:"RATE_LIMIT_FAIL_OPEN" env-default:"true"`
+
+	RPS   float64 `env:"RATE_LIMIT_RPS" env-default:"10"`
+	Burst int     `env:"RATE_LIMIT_BURST" env-default:"20"`
+
+	ReadRPS    float64 `env:"RATE_LIMIT_READ_RPS" env-default:"10"`
+	ReadBurst  int     `env:"RATE_LIMIT_READ_BURST" env-default:"50"`
+	WriteRPS   float64 `env:"RATE_LIMIT_WRITE_RPS" env-default:"2"`
+	WriteBurst int     `env:"RATE_LIMIT_WRITE_BURST" env-default:"20"`
+
+	LoginRPS   float64 `env:"RATE_LIMIT_LOGIN_RPS" env-default:"0.1"` // попытка в 10 с
+	LoginBurst int     `env:"RATE_LIMIT_LOGIN_BURST" env-default:"5"`
+}
+
 type Postgres struct {
 	Host     string `env:"POSTGRES_HOST" env-required:"true"`
 	Port     int    `env:"POSTGRES_PORT" env-required:"true"`
@@ -47,16 +195,17 @@ type Postgres struct {
 	MaxConnIdleTime time.Duration `env:"PG_MAX_CONN_IDLE_TIME" env-default:"5m"`
 }
 
-// type Redis struct {
-// 	Addr          string        `env:"REDIS_ADDR" env-required:"true"` // host:port
-// 	Password      string        `env:"REDIS_PASSWORD" env-default:""`
-// 	DB            int           `env:"REDIS_DB" env-default:"0"`
-// 	DialTimeout   time.Duration `env:"REDIS_DIAL_TIMEOUT" env-default:"2s"`
-// 	ReadTimeout   time.Duration `env:"REDIS_READ_TIMEOUT" env-default:"2s"`
-// 	WriteTimeout  time.Duration `env:"REDIS_WRITE_TIMEOUT" env-default:"2s"`
-// 	HealthTimeout time.Duration `env:"REDIS_HEALTH_TIMEOUT" env-default:"2s"`
-// 	KeyPrefix     string        `env:"REDIS_KEY_PREFIX" env-default:"app"`
-// }
+// Redis нужен только при RATE_LIMIT_BACKEND=redis.
+type Redis struct {
+	Addr          string        `env:"REDIS_ADDR"` // host:port
+	Password      string        `env:"REDIS_PASSWORD" env-default:""`
+	DB            int           `env:"REDIS_DB" env-default:"0"`
+	DialTimeout   time.Duration `env:"REDIS_DIAL_TIMEOUT" env-default:"2s"`
+	ReadTimeout   time.Duration `env:"REDIS_READ_TIMEOUT" env-default:"2s"`
+	WriteTimeout  time.Duration `env:"REDIS_WRITE_TIMEOUT" env-default:"2s"`
+	HealthTimeout time.Duration `env:"REDIS_HEALTH_TIMEOUT" env-default:"2s"`
+	KeyPrefix     string        `env:"REDIS_KEY_PREFIX" env-default:"app"`
+}
 
 func Load() (*Config, error) {
 	var cfg Config
@@ -71,15 +220,92 @@ func Load() (*Config, error) {
 	return &cfg, nil
 }
 
+var accentColorRe = regexp.MustCompile(`^#?[0-9A-Fa-f]{6}$`)
+
 func (c *Config) Validate() error {
 	var errs []error
 
+	for _, p := range c.App.HTTP.TrustedProxies {
+		p = strings.TrimSpace(p)
+		if _, err := netip.ParsePrefix(p); err != nil {
+			if _, err := netip.ParseAddr(p); err != nil && p != "" {
+				errs = append(errs, fmt.Errorf("HTTP_TRUSTED_PROXIES: invalid CIDR or address %q", p))
+			}
+		}
+	}
+	if c.Middlewares.CorsEnabled && len(c.Cors.Origins) == 0 {
+		errs = append(errs, errors.New("CORS_ALLOWED_ORIGINS is required when MW_CORS_ENABLED=true"))
+	}
+	if c.Cors.MaxAge < 0 {
+		errs = append(errs, errors.New("CORS_MAX_AGE must be >= 0"))
+	}
 	if c.Postgres.Port <= 0 || c.Postgres.Port > 65535 {
 		errs = append(errs, errors.New("POSTGRES_PORT out of range"))
 	}
-	// if c.Redis.Addr == "" {
-	// 	errs = append(errs, errors.New("REDIS_ADDR is required"))
-	// }
+	if c.Middlewares.RateLimitEnabled && (c.RateLimit.RPS <= 0 || c.RateLimit.Burst <= 0) {
+		errs = append(errs, errors.New("RATE_LIMIT_RPS and RATE_LIMIT_BURST must be > 0"))
+	}
+	if c.RateLimit.ReadRPS <= 0 || c.RateLimit.ReadBurst <= 0 || c.RateLimit.WriteRPS <= 0 || c.RateLimit.WriteBurst <= 0 ||
+		c.RateLimit.LoginRPS <= 0 || c.RateLimit.LoginBurst <= 0 {
+		errs = append(errs, errors.New("RATE_LIMIT_{READ,WRITE,LOGIN}_{RPS,BURST} must be > 0"))
+	}
+	if c.RateLimit.TTL <= 0 {
+		errs = append(errs, errors.New("RATE_LIMIT_TTL must be > 0"))
+	}
+	if c.Auth.SessionTTL <= 0 || c.Auth.SessionMaxLifetime < c.Auth.SessionTTL {
+		errs = append(errs, errors.New("AUTH_SESSION_TTL must be > 0 and <= AUTH_SESSION_MAX_LIFETIME"))
+	}
+	if c.Auth.GuardianInviteTTL <= 0 {
+		errs = append(errs, errors.New("AUTH_GUARDIAN_INVITE_TTL must be > 0"))
+	}
+	if _, err := c.Calendar.Location(); err != nil {
+		errs = append(errs, fmt.Errorf("CALENDAR_TIMEZONE: %w", err))
+	}
+	if _, err := c.Calendar.FirstLessonOffset(); err != nil {
+		errs = append(errs, errors.New("CALENDAR_FIRST_LESSON must be HH:MM"))
+	}
+	if c.Calendar.LessonDuration <= 0 || c.Calendar.BreakDuration < 0 {
+		errs = append(errs, errors.New("CALENDAR_LESSON_DURATION must be > 0 and CALENDAR_BREAK_DURATION >= 0"))
+	}
+	if !accentColorRe.MatchString(c.School.AccentColor) {
+		errs = append(errs, errors.New("SCHOOL_ACCENT_COLOR must be #RRGGBB"))
+	}
+	if _, err := c.School.Logo(); err != nil {
+		errs = append(errs, fmt.Errorf("SCHOOL_LOGO_PATH: %w", err))
+	}
+	switch c.Storage.Backend {
+	case "local":
+		if c.Storage.LocalDir == "" {
+			errs = append(errs, errors.New("STORAGE_LOCAL_DIR is required"))
+		}
+	case "s3":
+		if c.Storage.S3.Endpoint == "" || c.Storage.S3.Bucket == "" || c.Storage.S3.AccessKey == "" || c.Storage.S3.SecretKey == "" {
+			errs = append(errs, errors.New("S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY are required for STORAGE_BACKEND=s3"))
+		}
+		if c.Storage.URLTTL > 7*24*time.Hour {
+			errs = append(errs, errors.New("STORAGE_URL_TTL must be <= 168h for STORAGE_BACKEND=s3"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("STORAGE_BACKEND must be local or s3, got %q", c.Storage.Backend))
+	}
+	if c.Storage.URLTTL <= 0 {
+		errs = append(errs, errors.New("STORAGE_URL_TTL must be > 0"))
+	}
+	if c.Storage.MaxRequestSize <= 0 || c.Storage.MaxFileSize <= 0 || c.Storage.MaxPhotoSize <= 0 {
+		errs = append(errs, errors.New("STORAGE_MAX_REQUEST_SIZE, STORAGE_MAX_FILE_SIZE and STORAGE_MAX_PHOTO_SIZE must be > 0"))
+	}
+	if len(c.Storage.DocumentTypes) == 0 || len(c.Storage.PhotoTypes) == 0 {
+		errs = append(errs, errors.New("STORAGE_DOCUMENT_TYPES and STORAGE_PHOTO_TYPES must not be empty"))
+	}
+	switch c.RateLimit.Backend {
+	case "memory":
+	case "redis":
+		if c.Redis.Addr == "" {
+			errs = append(errs, errors.New("REDIS_ADDR is required for RATE_LIMIT_BACKEND=redis"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("RATE_LIMIT_BACKEND must be memory or redis, got %q", c.RateLimit.Backend))
+	}
 
 	return errors.Join(errs...)
 }
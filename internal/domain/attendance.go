@@ -0,0 +1,147 @@
+package domain
+
+import "time"
+
+// AttendanceStatus — отметка ученика на перекличке.
+type AttendanceStatus string
+
+const (
+	AttendancePresent AttendanceStatus = "present"
+	AttendanceAbsent  AttendanceStatus = "absent"
+	AttendanceLate    AttendanceStatus = "late"
+	AttendanceExcused AttendanceStatus = "excused"
+)
+
+func (s AttendanceStatus) Valid() bool {
+	switch s {
+	case AttendancePresent, AttendanceAbsent, AttendanceLate, AttendanceExcused:
+		return true
+	}
+	return false
+}
+
+// MaxLesson — последний номер урока в дне; Lesson = 0 означает перекличку за весь день.
+const MaxLesson = 12
+
+// AbsenceReason — причина отсутствия. Excused — причина уважительная.
+type AbsenceReason struct {
+	ID      int64  `json:"id"`
+	Code    string `json:"code"`
+	Name    string `json:"name"`
+	Excused bool   `json:"excused"`
+}
+
+// RollCall — перекличка класса за день (Lesson = 0) или на конкретном уроке.
+type RollCall struct {
+	ID        int64     `json:"id"`
+	ClassID   int64     `json:"class_id"`
+	Date      time.Time `json:"date"`
+	Lesson    int       `json:"lesson"`
+	SubjectID *int64    `json:"subject_id,omitempty"`
+	TakenBy   *int64    `json:"taken_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AttendanceMark — отметка ученика на перекличке.
+type AttendanceMark struct {
+	ID          int64            `json:"id"`
+	RollCallID  int64            `json:"roll_call_id"`
+	StudentID   int64            `json:"student_id"`
+	Status      AttendanceStatus `json:"status"`
+	ReasonID    *int64           `json:"reason_id,omitempty"`
+	MinutesLate int              `json:"minutes_late,omitempty"`
+	Note        string           `json:"note,omitempty"`
+
+	// Поля переклички для чтения.
+	Date   time.Time `json:"date"`
+	Lesson int       `json:"lesson"`
+}
+
+// AttendanceEntry — отметка одного ученика в пакетной перекличке.
+type AttendanceEntry struct {
+	StudentID   int64            `json:"student_id"`
+	Status      AttendanceStatus `json:"status"`
+	ReasonID    *int64           `json:"reason_id"`
+	MinutesLate int              `json:"minutes_late"`
+	Note        string           `json:"note"`
+}
+
+// RollCallInput — перекличка класса. Повторная отправка за тот же день/урок
+// отклоняется, если не выставлен Overwrite.
+type RollCallInput struct {
+	Date      time.Time         `json:"date"`
+	Lesson    int               `json:"lesson"`
+	SubjectID *int64            `json:"subject_id"`
+	Overwrite bool              `json:"overwrite"`
+	Marks     []AttendanceEntry `json:"marks"`
+}
+
+// RollCallResult — перекличка и её отметки.
+type RollCallResult struct {
+	RollCall RollCall         `json:"roll_call"`
+	Marks    []AttendanceMark `json:"marks"`
+}
+
+// DateRange — период выборки; нулевые границы не ограничивают.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// AttendanceSummary — счётчики отметок и доля присутствия (present + late) в процентах.
+type AttendanceSummary struct {
+	StudentID   int64   `json:"student_id,omitempty"`
+	StudentName string  `json:"student_name,omitempty"`
+	Present     int     `json:"present"`
+	Absent      int     `json:"absent"`
+	Late        int     `json:"late"`
+	Excused     int     `json:"excused"`
+	Total       int     `json:"total"`
+	Rate        float64 `json:"rate"`
+}
+
+// StudentAttendance — ответ GET /students/{id}/attendance.
+type StudentAttendance struct {
+	StudentID int64             `json:"student_id"`
+	Marks     []AttendanceMark  `json:"marks"`
+	Summary   AttendanceSummary `json:"summary"`
+}
+
+// ClassAttendance — сводка по классу за период: итог и по каждому ученику.
+type ClassAttendance struct {
+	ClassID  int64               `json:"class_id"`
+	Summary  AttendanceSummary   `json:"summary"`
+	Students []AttendanceSummary `json:"students"`
+}
+
+// ExcuseStatus — состояние объяснительной.
+type ExcuseStatus string
+
+const (
+	ExcusePending  ExcuseStatus = "pending"
+	ExcuseApproved ExcuseStatus = "approved"
+	ExcuseRejected ExcuseStatus = "rejected"
+)
+
+// ExcuseNote — объяснительная за период отсутствия (обычно от родителя).
+// После одобрения пропуски за период становятся excused.
+type ExcuseNote struct {
+	ID          int64        `json:"id"`
+	StudentID   int64        `json:"student_id"`
+	DateFrom    time.Time    `json:"date_from"`
+	DateTo      time.Time    `json:"date_to"`
+	ReasonID    int64        `json:"reason_id"`
+	Text        string       `json:"text"`
+	Status      ExcuseStatus `json:"status"`
+	SubmittedBy *int64       `json:"submitted_by,omitempty"`
+	ReviewedBy  *int64       `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time   `json:"reviewed_at,omitempty"`
+	Files       []File       `json:"files"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// ExcuseReview — решение по объяснительной.
+type ExcuseReview struct {
+	Approve bool `json:"approve"`
+}
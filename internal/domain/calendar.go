@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// HolidayKind — вид нерабочих дней.
+type HolidayKind string
+
+const (
+	HolidayVacation HolidayKind = "holiday" // каникулы и праздники по календарю
+	HolidayClosure  HolidayKind = "closure" // внеплановое закрытие школы (карантин, погода)
+)
+
+func (k HolidayKind) Valid() bool {
+	return k == HolidayVacation || k == HolidayClosure
+}
+
+// Holiday — каникулы или нерабочий день школы, StartDate..EndDate включительно.
+type Holiday struct {
+	ID        int64       `json:"id"`
+	Name      string      `json:"name"`
+	Kind      HolidayKind `json:"kind"`
+	StartDate time.Time   `json:"start_date"`
+	EndDate   time.Time   `json:"end_date"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// Covers сообщает, приходится ли дата d на каникулы.
+func (h Holiday) Covers(d time.Time) bool {
+	day := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	return !day.Before(h.StartDate) && !day.After(h.EndDate)
+}
+
+// BellSchedule — расписание звонков: начало первого урока (от полуночи), длительность урока и перемены.
+type BellSchedule struct {
+	FirstLesson time.Duration
+	Lesson      time.Duration
+	Break       time.Duration
+}
+
+// PeriodStart — начало урока period (с 1) от полуночи.
+func (b BellSchedule) PeriodStart(period int) time.Duration {
+	return b.FirstLesson + time.Duration(period-1)*(b.Lesson+b.Break)
+}
+
+// CalendarEvent — повторяющееся еженедельно событие календаря (урок из расписания).
+// Start/End — первое занятие; Until — последний день повторения (nil — бессрочно);
+// ExDates — начала отменённых занятий (каникулы).
+type CalendarEvent struct {
+	UID         string
+	Summary     string
+	Location    string
+	Description string
+	Start       time.Time
+	End         time.Time
+	Until       *time.Time
+	ExDates     []time.Time
+	Updated     time.Time
+}
+
+// CalendarToken — секрет персональной ссылки на календарь и готовые пути лент.
+type CalendarToken struct {
+	Token string   `json:"token"`
+	Feeds []string `json:"feeds"`
+}
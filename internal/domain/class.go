@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+// Class — учебный класс в конкретном учебном году, например "7B" в 2025/26.
+// AcademicYear — год начала учебного года (2025 для 2025/26).
+type Class struct {
+	ID                int64     `json:"id"`
+	Name              string    `json:"name"`
+	Grade             int       `json:"grade"`
+	AcademicYear      int       `json:"academic_year"`
+	HomeroomTeacherID *int64    `json:"homeroom_teacher_id,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// ClassFilter — параметры выборки списка классов.
+type ClassFilter struct {
+	AcademicYear int
+	Grade        int
+	Limit        int
+	Offset       int
+}
+
+// Subject — учебный предмет. Credits — зачётные единицы за год, с ними предмет входит в GPA.
+type Subject struct {
+	ID        int64     `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Credits   float64   `json:"credits"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// DefaultCredits — зачётные единицы предмета, если они не указаны.
+const DefaultCredits = 1
+
+// Assignment — кто ведёт предмет в классе (class_subject_teacher) и сколько часов в неделю.
+type Assignment struct {
+	ID           int64     `json:"id"`
+	ClassID      int64     `json:"class_id"`
+	SubjectID    int64     `json:"subject_id"`
+	TeacherID    int64     `json:"teacher_id"`
+	HoursPerWeek int       `json:"hours_per_week"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Денормализованные поля для чтения (заполняются при выборке списков).
+	ClassName   string `json:"class_name,omitempty"`
+	SubjectName string `json:"subject_name,omitempty"`
+	TeacherName string `json:"teacher_name,omitempty"`
+}
+
+// TeacherLoad — нагрузка учителя: все его назначения и суммарные часы в неделю.
+type TeacherLoad struct {
+	TeacherID    int64        `json:"teacher_id"`
+	AcademicYear int          `json:"academic_year"`
+	TotalHours   int          `json:"total_hours"`
+	Assignments  []Assignment `json:"assignments"`
+}
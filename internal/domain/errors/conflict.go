@@ -0,0 +1,16 @@
+package errors
+
+// ConflictError — конфликт с уже существующей записью, которую полезно вернуть клиенту
+// (например, занятый урок в расписании). errors.Is(err, ErrConflict) == true.
+type ConflictError struct {
+	Message string
+	With    any // конфликтующая запись, попадает в ответ как есть
+}
+
+func (e *ConflictError) Error() string {
+	return ErrConflict.Error() + ": " + e.Message
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
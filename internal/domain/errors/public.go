@@ -0,0 +1,24 @@
+package errors
+
+import "fmt"
+
+// PublicError — доменная ошибка с сообщением для клиента: оно попадает в detail ответа.
+// Остальной текст цепочки (префиксы операций, имена ограничений БД) остаётся только в логе.
+// errors.Is(err, Kind) == true.
+type PublicError struct {
+	Kind    error
+	Message string
+}
+
+// Newf — ошибка вида kind (ErrNotFound, ErrConflict, ...) с сообщением для клиента.
+func Newf(kind error, format string, args ...any) error {
+	return &PublicError{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *PublicError) Error() string {
+	return e.Kind.Error() + ": " + e.Message
+}
+
+func (e *PublicError) Unwrap() error {
+	return e.Kind
+}
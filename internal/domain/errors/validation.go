@@ -0,0 +1,38 @@
+package errors
+
+import "strings"
+
+// FieldError — ошибка валидации конкретного поля.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError накапливает ошибки по полям. errors.Is(err, ErrBadInput) == true.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// Err возвращает nil, если ошибок нет, — удобно в конце валидатора: return v.Err().
+func (e *ValidationError) Err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return ErrBadInput.Error() + ": " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrBadInput
+}
@@ -0,0 +1,73 @@
+package domain
+
+import "time"
+
+// Exec — учётная запись для входа. Изначально только администрация школы (директор, завуч, секретарь);
+// с ролями teacher/student/guardian запись привязывается к профилю учителя/ученика/представителя
+// через TeacherID/StudentID/GuardianID.
+type Exec struct {
+	ID           int64      `json:"id"`
+	FirstName    string     `json:"first_name"`
+	LastName     string     `json:"last_name"`
+	Email        string     `json:"email"`
+	Username     string     `json:"username"`
+	PasswordHash string     `json:"-"`
+	Role         Role       `json:"role"`
+	TeacherID    *int64     `json:"teacher_id,omitempty"`
+	StudentID    *int64     `json:"student_id,omitempty"`
+	GuardianID   *int64     `json:"guardian_id,omitempty"`
+	Active       bool       `json:"active"`
+	LastLoginAt  *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// ExecInput — данные для создания/замены exec; пароль приходит открытым текстом и сразу хешируется.
+type ExecInput struct {
+	FirstName  string `json:"first_name"`
+	LastName   string `json:"last_name"`
+	Email      string `json:"email"`
+	Username   string `json:"username"`
+	Password   string `json:"password"`
+	Role       Role   `json:"role"`
+	TeacherID  *int64 `json:"teacher_id"`
+	StudentID  *int64 `json:"student_id"`
+	GuardianID *int64 `json:"guardian_id"`
+	Active     *bool  `json:"active"`
+}
+
+// ExecPatch — частичное обновление (PATCH): nil-поля не меняются.
+type ExecPatch struct {
+	FirstName  *string `json:"first_name"`
+	LastName   *string `json:"last_name"`
+	Email      *string `json:"email"`
+	Username   *string `json:"username"`
+	Password   *string `json:"password"`
+	Role       *Role   `json:"role"`
+	TeacherID  *int64  `json:"teacher_id"`
+	StudentID  *int64  `json:"student_id"`
+	GuardianID *int64  `json:"guardian_id"`
+	Active     *bool   `json:"active"`
+}
+
+// Session — серверная сессия. В БД хранится только хеш токена, сам токен знает лишь клиент.
+type Session struct {
+	ID         int64     `json:"-"`
+	TokenHash  []byte    `json:"-"`
+	ExecID     int64     `json:"exec_id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Principal — аутентифицированный субъект запроса.
+type Principal struct {
+	ExecID     int64
+	SessionID  int64
+	Role       Role
+	TeacherID  int64 // 0, если учётка не привязана к учителю
+	StudentID  int64 // 0, если учётка не привязана к ученику
+	GuardianID int64 // 0, если учётка не привязана к представителю
+}
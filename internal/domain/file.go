@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// File — загруженный файл. Содержимое лежит в файловом хранилище под ключом Key
+// и отдаётся только по подписанной ссылке.
+type File struct {
+	ID          int64     `json:"id"`
+	Key         string    `json:"-"`
+	Name        string    `json:"name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	UploadedBy  *int64    `json:"uploaded_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// StudentPhoto — фотография ученика (одна на ученика).
+type StudentPhoto struct {
+	StudentID   int64     `json:"student_id"`
+	Key         string    `json:"-"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	UploadedBy  *int64    `json:"uploaded_by,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
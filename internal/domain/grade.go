@@ -0,0 +1,109 @@
+package domain
+
+import "time"
+
+// Число четвертей (учебных периодов) в году.
+const (
+	MinTerm = 1
+	MaxTerm = 4
+)
+
+// GradeCategory — категория оценивания (домашняя работа, самостоятельная, экзамен) с весом в среднем.
+type GradeCategory struct {
+	ID     int64   `json:"id"`
+	Code   string  `json:"code"`
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight"`
+}
+
+// Assessment — контрольная точка: работа по предмету в классе, за которую ставятся оценки.
+type Assessment struct {
+	ID         int64     `json:"id"`
+	ClassID    int64     `json:"class_id"`
+	SubjectID  int64     `json:"subject_id"`
+	CategoryID int64     `json:"category_id"`
+	Term       int       `json:"term"`
+	Title      string    `json:"title"`
+	Date       time.Time `json:"date"`
+	MaxScore   float64   `json:"max_score"`
+	TeacherID  *int64    `json:"teacher_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Grade — оценка ученика за работу.
+type Grade struct {
+	ID           int64     `json:"id"`
+	AssessmentID int64     `json:"assessment_id"`
+	StudentID    int64     `json:"student_id"`
+	Score        float64   `json:"score"`
+	Comment      string    `json:"comment,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Поля работы для чтения.
+	SubjectID    int64     `json:"subject_id,omitempty"`
+	SubjectName  string    `json:"subject_name,omitempty"`
+	CategoryCode string    `json:"category,omitempty"`
+	Title        string    `json:"title,omitempty"`
+	Date         time.Time `json:"date"`
+	MaxScore     float64   `json:"max_score,omitempty"`
+	Term         int       `json:"term,omitempty"`
+}
+
+// GradeEntry — одна оценка в пакетном вводе.
+type GradeEntry struct {
+	StudentID int64   `json:"student_id"`
+	Score     float64 `json:"score"`
+	Comment   string  `json:"comment"`
+}
+
+// GradeBatch — пакетный ввод оценок по классу: либо новая работа (поля Assessment),
+// либо правка оценок существующей (AssessmentID).
+type GradeBatch struct {
+	AssessmentID int64        `json:"assessment_id"`
+	SubjectID    int64        `json:"subject_id"`
+	CategoryID   int64        `json:"category_id"`
+	Term         int          `json:"term"`
+	Title        string       `json:"title"`
+	Date         time.Time    `json:"date"`
+	MaxScore     float64      `json:"max_score"`
+	Grades       []GradeEntry `json:"grades"`
+}
+
+// GradeBatchResult — работа и сохранённые оценки.
+type GradeBatchResult struct {
+	Assessment Assessment `json:"assessment"`
+	Grades     []Grade    `json:"grades"`
+}
+
+// GradeFilter — выборка оценок. Нулевые поля не фильтруют.
+// TeacherID оставляет только работы по предметам, которые этот учитель ведёт в классе работы.
+type GradeFilter struct {
+	AcademicYear int
+	Term         int
+	SubjectID    int64
+	TeacherID    int64
+}
+
+// SubjectAverage — средневзвешенный процент ученика по предмету за период.
+type SubjectAverage struct {
+	StudentID   int64   `json:"student_id"`
+	SubjectID   int64   `json:"subject_id"`
+	SubjectName string  `json:"subject_name"`
+	Average     float64 `json:"average"` // 0..100
+	GradesCount int     `json:"grades_count"`
+}
+
+// StudentRanking — место ученика в классе по среднему баллу.
+type StudentRanking struct {
+	StudentID   int64   `json:"student_id"`
+	StudentName string  `json:"student_name"`
+	Average     float64 `json:"average"`
+	Rank        int     `json:"rank"`
+}
+
+// StudentGrades — ответ GET /students/{id}/grades.
+type StudentGrades struct {
+	StudentID int64            `json:"student_id"`
+	Grades    []Grade          `json:"grades"`
+	Averages  []SubjectAverage `json:"averages"`
+}
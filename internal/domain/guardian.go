@@ -0,0 +1,85 @@
+package domain
+
+import "time"
+
+// Relationship — кем представитель приходится ученику.
+type Relationship string
+
+const (
+	RelationshipMother        Relationship = "mother"
+	RelationshipFather        Relationship = "father"
+	RelationshipStepparent    Relationship = "stepparent"
+	RelationshipGrandparent   Relationship = "grandparent"
+	RelationshipSibling       Relationship = "sibling"
+	RelationshipLegalGuardian Relationship = "legal_guardian"
+	RelationshipOther         Relationship = "other"
+)
+
+func (r Relationship) Valid() bool {
+	switch r {
+	case RelationshipMother, RelationshipFather, RelationshipStepparent, RelationshipGrandparent,
+		RelationshipSibling, RelationshipLegalGuardian, RelationshipOther:
+		return true
+	}
+	return false
+}
+
+// Guardian — родитель или законный представитель. Нужен хотя бы один контакт: email или телефон.
+// HasAccount только для чтения: заведена ли учётка (по приглашению).
+type Guardian struct {
+	ID         int64     `json:"id"`
+	FirstName  string    `json:"first_name"`
+	LastName   string    `json:"last_name"`
+	Email      string    `json:"email,omitempty"`
+	Phone      string    `json:"phone,omitempty"`
+	Address    string    `json:"address,omitempty"`
+	HasAccount bool      `json:"has_account"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// GuardianLink — связь ученика с представителем. Primary — основной контакт по ученику.
+type GuardianLink struct {
+	Relationship Relationship `json:"relationship"`
+	Primary      bool         `json:"primary"`
+}
+
+// StudentGuardian — представитель ученика вместе с параметрами связи.
+type StudentGuardian struct {
+	Guardian
+	GuardianLink
+}
+
+// Ward — ребёнок представителя вместе с параметрами связи.
+type Ward struct {
+	Student
+	GuardianLink
+}
+
+// GuardianInvite — одноразовый код, по которому представитель заводит учётку.
+// Code отдаётся только при создании — в БД хранится его хеш.
+type GuardianInvite struct {
+	ID         int64      `json:"id"`
+	GuardianID int64      `json:"guardian_id"`
+	Code       string     `json:"code,omitempty"`
+	CreatedBy  *int64     `json:"created_by,omitempty"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	UsedAt     *time.Time `json:"used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// InviteRedemption — погашение приглашения: учётные данные новой учётки представителя.
+// Email по умолчанию берётся из карточки представителя.
+type InviteRedemption struct {
+	Code     string `json:"code"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Email    string `json:"email"`
+}
+
+// GuardianFilter — параметры выборки списка представителей.
+type GuardianFilter struct {
+	Search string // подстрока в имени/фамилии/email/телефоне
+	Limit  int
+	Offset int
+}
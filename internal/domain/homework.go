@@ -0,0 +1,86 @@
+package domain
+
+import "time"
+
+// Homework — домашнее задание классу по предмету со сроком сдачи. Вместе с заданием в журнале
+// заводится работа (AssessmentID), в неё попадают оценки за проверенные сдачи.
+type Homework struct {
+	ID           int64     `json:"id"`
+	ClassID      int64     `json:"class_id"`
+	SubjectID    int64     `json:"subject_id"`
+	SubjectName  string    `json:"subject_name,omitempty"`
+	TeacherID    *int64    `json:"teacher_id,omitempty"`
+	AssessmentID int64     `json:"assessment_id"`
+	CategoryID   int64     `json:"category_id"`
+	Term         int       `json:"term"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	DueAt        time.Time `json:"due_at"`
+	MaxScore     float64   `json:"max_score"`
+	AllowLate    bool      `json:"allow_late"`
+	Attachments  []File    `json:"attachments,omitempty"`
+	CreatedBy    *int64    `json:"created_by,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// HomeworkInput — создание и правка задания. Категория по умолчанию — homework,
+// период — тот, на который приходится срок сдачи; AllowLate по умолчанию true.
+type HomeworkInput struct {
+	SubjectID   int64     `json:"subject_id"`
+	CategoryID  int64     `json:"category_id"`
+	Term        int       `json:"term"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	DueAt       time.Time `json:"due_at"`
+	MaxScore    float64   `json:"max_score"`
+	AllowLate   *bool     `json:"allow_late"`
+}
+
+// HomeworkFilter — выборка заданий; нулевые поля не фильтруют, DueFrom/DueTo — по сроку сдачи.
+type HomeworkFilter struct {
+	SubjectID int64
+	DueFrom   time.Time
+	DueTo     time.Time
+	Limit     int
+	Offset    int
+}
+
+// Submission — сдача задания учеником. Score == nil — ещё не проверена.
+type Submission struct {
+	ID          int64      `json:"id"`
+	HomeworkID  int64      `json:"homework_id"`
+	StudentID   int64      `json:"student_id"`
+	StudentName string     `json:"student_name,omitempty"`
+	Text        string     `json:"text"`
+	Late        bool       `json:"late"`
+	SubmittedAt time.Time  `json:"submitted_at"`
+	Score       *float64   `json:"score,omitempty"`
+	Feedback    string     `json:"feedback,omitempty"`
+	GradedBy    *int64     `json:"graded_by,omitempty"`
+	GradedAt    *time.Time `json:"graded_at,omitempty"`
+	Files       []File     `json:"files"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Graded сообщает, проверена ли сдача.
+func (s Submission) Graded() bool { return s.GradedAt != nil }
+
+// SubmissionGrade — оценка за сдачу; попадает в журнал с комментарием Feedback.
+type SubmissionGrade struct {
+	Score    float64 `json:"score"`
+	Feedback string  `json:"feedback"`
+}
+
+// StudentHomework — задание в списке ученика вместе с его сдачей (nil — не сдано).
+type StudentHomework struct {
+	Homework
+	Submission *Submission `json:"submission"`
+}
+
+// HomeworkFile — файл задания (SubmissionID == nil) или сдачи.
+type HomeworkFile struct {
+	File
+	HomeworkID   int64  `json:"-"`
+	SubmissionID *int64 `json:"-"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "encoding/json"
+
+// Nullable — поле PATCH, которое можно явно очистить. Поля нет в JSON — Set == false (не меняется);
+// null — Set == true, Value == nil (очистить); значение — Set == true, Value != nil.
+type Nullable[T any] struct {
+	Set   bool
+	Value *T
+}
+
+// UnmarshalJSON вызывается и для null, поэтому отсутствие поля и null различимы.
+func (n *Nullable[T]) UnmarshalJSON(b []byte) error {
+	n.Set = true
+	if string(b) == "null" {
+		n.Value = nil
+		return nil
+	}
+	var v T
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	n.Value = &v
+	return nil
+}
@@ -0,0 +1,66 @@
+package domain
+
+import "time"
+
+// PromotionRequest — перевод учеников в новый учебный год: класс параллели N переходит в N+1,
+// выпускная параллель выпускается, ученики из HoldBack остаются на второй год.
+type PromotionRequest struct {
+	FromYear   int             `json:"from_year"`
+	ToYear     int             `json:"to_year"`     // по умолчанию FromYear+1
+	FinalGrade int             `json:"final_grade"` // выпускная параллель, по умолчанию MaxGrade
+	HoldBack   []int64         `json:"hold_back"`
+	ClassMap   map[int64]int64 `json:"class_map"` // явное соответствие: класс → класс нового года
+	DryRun     bool            `json:"dry_run"`
+}
+
+// PromotionAction — что происходит с учеником.
+type PromotionAction string
+
+const (
+	PromotionPromote  PromotionAction = "promote"
+	PromotionHoldBack PromotionAction = "hold_back"
+	PromotionGraduate PromotionAction = "graduate"
+	PromotionSkip     PromotionAction = "skip"
+)
+
+// PromotionClass — класс нового года, который будет создан при переводе.
+type PromotionClass struct {
+	Name              string `json:"name"`
+	Grade             int    `json:"grade"`
+	HomeroomTeacherID *int64 `json:"homeroom_teacher_id,omitempty"`
+}
+
+// PromotionItem — строка плана по одному ученику. ToClassID == 0 при переводе в создаваемый класс ToClass.
+type PromotionItem struct {
+	StudentID   int64           `json:"student_id"`
+	StudentName string          `json:"student_name"`
+	Action      PromotionAction `json:"action"`
+	FromClassID int64           `json:"from_class_id"`
+	FromClass   string          `json:"from_class"`
+	FromGrade   int             `json:"from_grade"`
+	ToClassID   int64           `json:"to_class_id,omitempty"`
+	ToClass     string          `json:"to_class,omitempty"`
+	ToGrade     int             `json:"to_grade,omitempty"`
+	Reason      string          `json:"reason,omitempty"`
+}
+
+type PromotionSummary struct {
+	Promoted  int `json:"promoted"`
+	HeldBack  int `json:"held_back"`
+	Graduated int `json:"graduated"`
+	Skipped   int `json:"skipped"`
+}
+
+// PromotionPlan — план перевода (dry-run) или запись журнала о выполненном переводе.
+type PromotionPlan struct {
+	ID         int64            `json:"id,omitempty"`
+	FromYear   int              `json:"from_year"`
+	ToYear     int              `json:"to_year"`
+	FinalGrade int              `json:"final_grade"`
+	DryRun     bool             `json:"dry_run"`
+	NewClasses []PromotionClass `json:"new_classes,omitempty"`
+	Items      []PromotionItem  `json:"items,omitempty"` // в списке журнала не отдаются
+	Summary    PromotionSummary `json:"summary"`
+	ExecutedBy *int64           `json:"executed_by,omitempty"`
+	ExecutedAt *time.Time       `json:"executed_at,omitempty"`
+}
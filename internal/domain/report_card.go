@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// ReportCardComment — комментарий в табеле: учителя по предмету или общий (SubjectID == nil)
+// от классного руководителя.
+type ReportCardComment struct {
+	ID        int64     `json:"id"`
+	StudentID int64     `json:"student_id"`
+	TermID    int64     `json:"term_id"`
+	SubjectID *int64    `json:"subject_id,omitempty"`
+	AuthorID  *int64    `json:"author_id,omitempty"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReportCardSubject — строка табеля: предмет, учитель, средний процент и комментарий.
+// Average == nil — оценок за период нет.
+type ReportCardSubject struct {
+	SubjectID   int64    `json:"subject_id"`
+	SubjectName string   `json:"subject_name"`
+	TeacherName string   `json:"teacher_name,omitempty"`
+	Average     *float64 `json:"average"`
+	GradesCount int      `json:"grades_count"`
+	Comment     string   `json:"comment,omitempty"`
+}
+
+// ReportCard — табель ученика за учебный период.
+type ReportCard struct {
+	StudentID       int64               `json:"student_id"`
+	StudentName     string              `json:"student_name"`
+	ClassID         int64               `json:"class_id,omitempty"`
+	ClassName       string              `json:"class_name,omitempty"`
+	HomeroomTeacher string              `json:"homeroom_teacher,omitempty"`
+	Term            Term                `json:"term"`
+	Subjects        []ReportCardSubject `json:"subjects"`
+	Average         *float64            `json:"average"` // среднее по предметам с оценками
+	Attendance      AttendanceSummary   `json:"attendance"`
+	Comment         string              `json:"comment,omitempty"` // общий комментарий классного руководителя
+	GeneratedAt     time.Time           `json:"generated_at"`
+}
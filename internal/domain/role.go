@@ -0,0 +1,26 @@
+package domain
+
+// Role — роль учётной записи, от неё зависят права на маршруты.
+type Role string
+
+const (
+	RoleSuperadmin Role = "superadmin"
+	RolePrincipal  Role = "principal"
+	RoleRegistrar  Role = "registrar"
+	RoleTeacher    Role = "teacher"
+	RoleStudent    Role = "student"
+	RoleGuardian   Role = "guardian"
+)
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleSuperadmin, RolePrincipal, RoleRegistrar, RoleTeacher, RoleStudent, RoleGuardian:
+		return true
+	}
+	return false
+}
+
+// IsStaff — администрация школы (доступ ко всем данным учеников и учителей).
+func (r Role) IsStaff() bool {
+	return r == RoleSuperadmin || r == RolePrincipal || r == RoleRegistrar
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// StudentStatus — этап жизненного цикла ученика.
+type StudentStatus string
+
+const (
+	StudentApplicant StudentStatus = "applicant"
+	StudentEnrolled  StudentStatus = "enrolled"
+	StudentSuspended StudentStatus = "suspended"
+	StudentGraduated StudentStatus = "graduated"
+	StudentWithdrawn StudentStatus = "withdrawn"
+)
+
+// studentTransitions — допустимые переходы статусов. graduated — конечное состояние.
+var studentTransitions = map[StudentStatus][]StudentStatus{
+	StudentApplicant: {StudentEnrolled, StudentWithdrawn},
+	StudentEnrolled:  {StudentSuspended, StudentGraduated, StudentWithdrawn},
+	StudentSuspended: {StudentEnrolled, StudentWithdrawn},
+	StudentWithdrawn: {StudentApplicant},
+	StudentGraduated: {},
+}
+
+func (s StudentStatus) Valid() bool {
+	_, ok := studentTransitions[s]
+	return ok
+}
+
+// CanTransitionTo сообщает, разрешён ли переход s → next. Переход в тот же статус считается no-op и разрешён.
+func (s StudentStatus) CanTransitionTo(next StudentStatus) bool {
+	if s == next {
+		return true
+	}
+	return slices.Contains(studentTransitions[s], next)
+}
+
+// InClass сообщает, учится ли ученик в этом статусе: зачисленного или временно отстранённого
+// нельзя оставить без класса, не выведя из этих статусов.
+func (s StudentStatus) InClass() bool {
+	return s == StudentEnrolled || s == StudentSuspended
+}
+
+// Минимальный и максимальный год обучения (класс/параллель).
+const (
+	MinGrade = 1
+	MaxGrade = 12
+)
+
+// Student — ученик. Grade — год обучения (параллель); ученик в классе ClassID получает параллель класса.
+// ClassName только для чтения (подтягивается из classes).
+type Student struct {
+	ID        int64         `json:"id"`
+	FirstName string        `json:"first_name"`
+	LastName  string        `json:"last_name"`
+	Email     string        `json:"email,omitempty"`
+	BirthDate *time.Time    `json:"birth_date,omitempty"`
+	Grade     int           `json:"grade"`
+	ClassID   *int64        `json:"class_id,omitempty"`
+	ClassName string        `json:"class_name,omitempty"`
+	Status    StudentStatus `json:"status"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// StudentPatch — частичное обновление (PATCH): nil-поля не меняются.
+// ClassID различает отсутствие поля и "class_id": null — второе убирает ученика из класса.
+type StudentPatch struct {
+	FirstName *string         `json:"first_name"`
+	LastName  *string         `json:"last_name"`
+	Email     *string         `json:"email"`
+	BirthDate *time.Time      `json:"birth_date"`
+	Grade     *int            `json:"grade"`
+	ClassID   Nullable[int64] `json:"class_id"`
+	Status    *StudentStatus  `json:"status"`
+}
+
+// StudentFilter — параметры выборки списка учеников.
+type StudentFilter struct {
+	Search  string
+	Grade   int
+	ClassID int64
+	Status  StudentStatus
+	Limit   int
+	Offset  int
+}
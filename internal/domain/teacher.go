@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// Teacher — преподаватель школы.
+type Teacher struct {
+	ID        int64     `json:"id"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TeacherPatch — частичное обновление (PATCH): nil-поля не меняются.
+type TeacherPatch struct {
+	FirstName *string `json:"first_name"`
+	LastName  *string `json:"last_name"`
+	Email     *string `json:"email"`
+	Phone     *string `json:"phone"`
+}
+
+// TeacherFilter — параметры выборки списка преподавателей.
+type TeacherFilter struct {
+	Search string // подстрока в имени/фамилии/email
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// AcademicYear — учебный год. Year — год начала (2025 — учебный год 2025/2026),
+// им же учебный год обозначается у классов.
+type AcademicYear struct {
+	Year      int       `json:"year"`
+	Name      string    `json:"name"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Term — учебный период внутри года. Number соответствует номеру периода у работ (Assessment.Term).
+// Закрытый период замораживает оценки, пока его не откроют снова.
+type Term struct {
+	ID           int64      `json:"id"`
+	AcademicYear int        `json:"academic_year"`
+	Number       int        `json:"number"`
+	Name         string     `json:"name"`
+	StartDate    time.Time  `json:"start_date"`
+	EndDate      time.Time  `json:"end_date"`
+	Closed       bool       `json:"closed"`
+	ClosedAt     *time.Time `json:"closed_at,omitempty"`
+	ClosedBy     *int64     `json:"closed_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Contains сообщает, приходится ли дата d на период.
+func (t Term) Contains(d time.Time) bool {
+	day := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+	return !day.Before(t.StartDate) && !day.After(t.EndDate)
+}
+
+// Range — границы периода как фильтр по датам.
+func (t Term) Range() DateRange {
+	return DateRange{From: t.StartDate, To: t.EndDate}
+}
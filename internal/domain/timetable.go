@@ -0,0 +1,113 @@
+package domain
+
+import "time"
+
+// Дни недели в расписании: 1 — понедельник, 7 — воскресенье (ISO 8601).
+const (
+	MinDay = 1
+	MaxDay = 7
+)
+
+// Room — кабинет. Capacity — число мест (0 — не указано).
+type Room struct {
+	ID        int64     `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Capacity  int       `json:"capacity"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TimetableSlot — урок в недельном расписании: день, номер урока, класс, предмет, учитель и кабинет.
+// Действует с EffectiveFrom по EffectiveTo включительно; EffectiveTo == nil — бессрочно.
+type TimetableSlot struct {
+	ID            int64      `json:"id"`
+	ClassID       int64      `json:"class_id"`
+	SubjectID     int64      `json:"subject_id"`
+	TeacherID     int64      `json:"teacher_id"`
+	RoomID        *int64     `json:"room_id,omitempty"`
+	Day           int        `json:"day"`
+	Period        int        `json:"period"`
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Денормализованные поля для чтения.
+	ClassName   string `json:"class_name,omitempty"`
+	SubjectName string `json:"subject_name,omitempty"`
+	TeacherName string `json:"teacher_name,omitempty"`
+	RoomCode    string `json:"room_code,omitempty"`
+}
+
+// Виды пересечений в расписании.
+const (
+	ClashClass   = "class"
+	ClashTeacher = "teacher"
+	ClashRoom    = "room"
+)
+
+// Clash — пересечение нового урока с уже стоящим в расписании.
+type Clash struct {
+	Kind string        `json:"kind"`
+	Slot TimetableSlot `json:"slot"`
+}
+
+// TimetableFilter — выборка расписания: уроки, действующие на дату On, либо пересекающиеся с периодом From..To.
+type TimetableFilter struct {
+	On   time.Time
+	From time.Time
+	To   time.Time
+}
+
+// LessonTime — день недели и номер урока.
+type LessonTime struct {
+	Day    int `json:"day"`
+	Period int `json:"period"`
+}
+
+// TeacherAvailability — часы, когда учитель не может вести уроки.
+type TeacherAvailability struct {
+	TeacherID   int64        `json:"teacher_id"`
+	Unavailable []LessonTime `json:"unavailable"`
+}
+
+// DraftStatus — состояние черновика расписания (фоновой задачи генерации).
+type DraftStatus string
+
+const (
+	DraftQueued    DraftStatus = "queued"
+	DraftRunning   DraftStatus = "running"
+	DraftReady     DraftStatus = "ready"
+	DraftFailed    DraftStatus = "failed"
+	DraftPublished DraftStatus = "published"
+)
+
+// GenerateTimetable — параметры генерации. Пустой ClassIDs — все классы учебного года.
+type GenerateTimetable struct {
+	AcademicYear  int       `json:"academic_year"`
+	ClassIDs      []int64   `json:"class_ids"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	Days          int       `json:"days"`
+	Periods       int       `json:"periods"`
+	TimeLimit     int       `json:"time_limit_seconds"`
+}
+
+// TimetableDraft — черновик расписания и ход его генерации. Progress — 0..100.
+type TimetableDraft struct {
+	ID            int64       `json:"id"`
+	AcademicYear  int         `json:"academic_year"`
+	ClassIDs      []int64     `json:"class_ids"`
+	EffectiveFrom time.Time   `json:"effective_from"`
+	Days          int         `json:"days"`
+	Periods       int         `json:"periods"`
+	TimeLimit     int         `json:"time_limit_seconds"`
+	Status        DraftStatus `json:"status"`
+	Progress      int         `json:"progress"`
+	Message       string      `json:"message,omitempty"`
+	CreatedBy     *int64      `json:"created_by,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	StartedAt     *time.Time  `json:"started_at,omitempty"`
+	FinishedAt    *time.Time  `json:"finished_at,omitempty"`
+	PublishedAt   *time.Time  `json:"published_at,omitempty"`
+}
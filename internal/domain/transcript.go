@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// GradingScaleKind — способ перевода процента в оценку.
+type GradingScaleKind string
+
+const (
+	ScaleBanded GradingScaleKind = "banded" // интервалы процентов: 85–100 → «5», 90–100 → «A»
+	ScaleLinear GradingScaleKind = "linear" // пропорционально: 87% → 87 из 100
+)
+
+func (k GradingScaleKind) Valid() bool {
+	return k == ScaleBanded || k == ScaleLinear
+}
+
+// GradeBand — интервал шкалы: от MinPercent включительно до MinPercent следующего интервала.
+type GradeBand struct {
+	MinPercent float64 `json:"min_percent"`
+	Label      string  `json:"label"`
+	Points     float64 `json:"points"`
+}
+
+// GradingScale — шкала оценивания. MaxPoints — максимум баллов (для GPA), у linear — и максимум оценки.
+type GradingScale struct {
+	ID        int64            `json:"id"`
+	Code      string           `json:"code"`
+	Name      string           `json:"name"`
+	Kind      GradingScaleKind `json:"kind"`
+	MaxPoints float64          `json:"max_points"`
+	Default   bool             `json:"default"`
+	Bands     []GradeBand      `json:"bands,omitempty"` // по убыванию MinPercent
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// ScaledGrade — оценка по шкале: обозначение и баллы.
+type ScaledGrade struct {
+	Label  string  `json:"label"`
+	Points float64 `json:"points"`
+}
+
+// Convert переводит процент (0..100) в оценку шкалы.
+func (s GradingScale) Convert(percent float64) ScaledGrade {
+	percent = math.Max(0, math.Min(100, percent))
+	if s.Kind == ScaleLinear {
+		points := math.Round(percent/100*s.MaxPoints*100) / 100
+		return ScaledGrade{Label: strconv.FormatFloat(math.Round(points), 'f', 0, 64), Points: points}
+	}
+	for _, b := range s.sortedBands() {
+		if percent >= b.MinPercent {
+			return ScaledGrade{Label: b.Label, Points: b.Points}
+		}
+	}
+	return ScaledGrade{}
+}
+
+// Percent — представительный процент оценки label: середина интервала для banded,
+// обратный пересчёт для linear. false — такой оценки в шкале нет.
+func (s GradingScale) Percent(label string) (float64, bool) {
+	if s.Kind == ScaleLinear {
+		v, err := strconv.ParseFloat(label, 64)
+		if err != nil || v < 0 || v > s.MaxPoints {
+			return 0, false
+		}
+		return math.Round(v/s.MaxPoints*10000) / 100, true
+	}
+	upper := 100.0
+	for _, b := range s.sortedBands() {
+		if b.Label == label {
+			return math.Round((b.MinPercent+upper)/2*100) / 100, true
+		}
+		upper = b.MinPercent
+	}
+	return 0, false
+}
+
+func (s GradingScale) sortedBands() []GradeBand {
+	bands := append([]GradeBand(nil), s.Bands...)
+	sort.Slice(bands, func(i, j int) bool { return bands[i].MinPercent > bands[j].MinPercent })
+	return bands
+}
+
+// GradeConversion — ответ GET /grading-scales/convert.
+type GradeConversion struct {
+	From    string      `json:"from"`
+	To      string      `json:"to"`
+	Value   string      `json:"value"`
+	Percent float64     `json:"percent"`
+	Result  ScaledGrade `json:"result"`
+}
+
+// TranscriptCourse — завершённый курс: предмет за учебный год с итоговой оценкой.
+type TranscriptCourse struct {
+	AcademicYear int     `json:"academic_year"`
+	ClassName    string  `json:"class_name,omitempty"`
+	SubjectID    int64   `json:"subject_id"`
+	SubjectName  string  `json:"subject_name"`
+	Credits      float64 `json:"credits"`
+	Percent      float64 `json:"percent"`
+	Grade        string  `json:"grade"`
+	Points       float64 `json:"points"`
+	GradesCount  int     `json:"grades_count"`
+}
+
+// TranscriptYear — курсы учебного года и GPA за год.
+type TranscriptYear struct {
+	AcademicYear int                `json:"academic_year"`
+	ClassName    string             `json:"class_name,omitempty"`
+	Courses      []TranscriptCourse `json:"courses"`
+	Credits      float64            `json:"credits"`
+	GPA          float64            `json:"gpa"`
+}
+
+// TranscriptScale — шкала, по которой составлена выписка.
+type TranscriptScale struct {
+	Code      string      `json:"code"`
+	Name      string      `json:"name"`
+	MaxPoints float64     `json:"max_points"`
+	Bands     []GradeBand `json:"bands,omitempty"`
+}
+
+// Transcript — выписка об успеваемости за завершённые учебные годы. GPA — среднее баллов
+// курсов, взвешенное зачётными единицами. Hash есть только у выданной выписки.
+type Transcript struct {
+	StudentID    int64            `json:"student_id"`
+	StudentName  string           `json:"student_name"`
+	BirthDate    *time.Time       `json:"birth_date,omitempty"`
+	Status       StudentStatus    `json:"status"`
+	Scale        TranscriptScale  `json:"scale"`
+	Years        []TranscriptYear `json:"years"`
+	TotalCredits float64          `json:"total_credits"`
+	GPA          float64          `json:"gpa"`
+	IssuedAt     time.Time        `json:"issued_at"`
+	IssuedBy     *int64           `json:"issued_by,omitempty"`
+	Hash         string           `json:"hash,omitempty"`
+}
+
+// TranscriptVerification — ответ GET /transcripts/verify/{hash}.
+type TranscriptVerification struct {
+	Valid      bool       `json:"valid"`
+	Transcript Transcript `json:"transcript"`
+}
+
+// IssuedTranscript — запись о выданной выписке (для списка выдач).
+type IssuedTranscript struct {
+	Hash         string    `json:"hash"`
+	StudentID    int64     `json:"student_id"`
+	Scale        string    `json:"scale"`
+	GPA          float64   `json:"gpa"`
+	TotalCredits float64   `json:"total_credits"`
+	IssuedBy     *int64    `json:"issued_by,omitempty"`
+	IssuedAt     time.Time `json:"issued_at"`
+}
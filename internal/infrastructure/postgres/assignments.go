@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AssignmentRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAssignmentRepo(pool *pgxpool.Pool) *AssignmentRepo {
+	return &AssignmentRepo{pool: pool}
+}
+
+// assignmentSelect — назначение с именами класса, предмета и учителя; источник строк — a.
+const assignmentSelect = `
+	SELECT a.id, a.class_id, a.subject_id, a.teacher_id, a.hours_per_week, a.created_at,
+	       c.name, sb.name, t.last_name || ' ' || t.first_name
+	FROM a
+	JOIN classes c   ON c.id = a.class_id
+	JOIN subjects sb ON sb.id = a.subject_id
+	JOIN teachers t  ON t.id = a.teacher_id`
+
+func scanAssignment(row pgx.Row) (domain.Assignment, error) {
+	var a domain.Assignment
+	err := row.Scan(
+		&a.ID, &a.ClassID, &a.SubjectID, &a.TeacherID, &a.HoursPerWeek, &a.CreatedAt,
+		&a.ClassName, &a.SubjectName, &a.TeacherName,
+	)
+	return a, err
+}
+
+func (r *AssignmentRepo) list(ctx context.Context, op, where string, args ...any) ([]domain.Assignment, error) {
+	rows, err := r.pool.Query(ctx,
+		`WITH a AS (SELECT * FROM class_subject_teacher WHERE `+where+`)`+assignmentSelect+
+			` ORDER BY c.name, sb.name`, args...)
+	if err != nil {
+		return nil, mapErr(op, err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.Assignment, 0)
+	for rows.Next() {
+		a, err := scanAssignment(rows)
+		if err != nil {
+			return nil, mapErr(op, err)
+		}
+		out = append(out, a)
+	}
+
+	return out, mapErr(op, rows.Err())
+}
+
+func (r *AssignmentRepo) ListByClass(ctx context.Context, classID int64) ([]domain.Assignment, error) {
+	return r.list(ctx, "list class assignments", `class_id = $1`, classID)
+}
+
+// ListByTeacher — нагрузка учителя; academicYear == 0 — за все годы.
+func (r *AssignmentRepo) ListByTeacher(ctx context.Context, teacherID int64, academicYear int) ([]domain.Assignment, error) {
+	return r.list(ctx, "list teacher assignments",
+		`teacher_id = $1 AND ($2 = 0 OR class_id IN (SELECT id FROM classes WHERE academic_year = $2))`,
+		teacherID, academicYear,
+	)
+}
+
+func (r *AssignmentRepo) Get(ctx context.Context, classID, subjectID int64) (domain.Assignment, error) {
+	row := r.pool.QueryRow(ctx,
+		`WITH a AS (SELECT * FROM class_subject_teacher WHERE class_id = $1 AND subject_id = $2)`+assignmentSelect,
+		classID, subjectID,
+	)
+	a, err := scanAssignment(row)
+	return a, mapErr("get assignment", err)
+}
+
+// Create назначает учителя; повтор пары (класс, предмет) — ErrConflict по class_subject_uniq.
+func (r *AssignmentRepo) Create(ctx context.Context, a domain.Assignment) (domain.Assignment, error) {
+	row := r.pool.QueryRow(ctx, `
+		WITH a AS (
+			INSERT INTO class_subject_teacher (class_id, subject_id, teacher_id, hours_per_week)
+			VALUES ($1, $2, $3, $4)
+			RETURNING *
+		)`+assignmentSelect,
+		a.ClassID, a.SubjectID, a.TeacherID, a.HoursPerWeek,
+	)
+	created, err := scanAssignment(row)
+	return created, mapErr("create assignment", err)
+}
+
+func (r *AssignmentRepo) Update(ctx context.Context, a domain.Assignment) (domain.Assignment, error) {
+	row := r.pool.QueryRow(ctx, `
+		WITH a AS (
+			UPDATE class_subject_teacher SET teacher_id = $3, hours_per_week = $4
+			WHERE class_id = $1 AND subject_id = $2
+			RETURNING *
+		)`+assignmentSelect,
+		a.ClassID, a.SubjectID, a.TeacherID, a.HoursPerWeek,
+	)
+	updated, err := scanAssignment(row)
+	return updated, mapErr("update assignment", err)
+}
+
+func (r *AssignmentRepo) Delete(ctx context.Context, classID, subjectID int64) error {
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM class_subject_teacher WHERE class_id = $1 AND subject_id = $2`, classID, subjectID)
+	if err != nil {
+		return mapErr("delete assignment", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return mapErr("delete assignment", domainerr.ErrNotFound)
+	}
+	return nil
+}
+
+// TeachesClass сообщает, ведёт ли учитель хоть один предмет в классе (для RBAC).
+func (r *AssignmentRepo) TeachesClass(ctx context.Context, teacherID, classID int64) (bool, error) {
+	var ok bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM class_subject_teacher WHERE teacher_id = $1 AND class_id = $2)
+		    OR EXISTS (SELECT 1 FROM classes WHERE id = $2 AND homeroom_teacher_id = $1)`,
+		teacherID, classID,
+	).Scan(&ok)
+	return ok, mapErr("check teacher class", err)
+}
+
+// TeachesStudent сообщает, ведёт ли учитель класс, в котором учится ученик.
+func (r *AssignmentRepo) TeachesStudent(ctx context.Context, teacherID, studentID int64) (bool, error) {
+	var ok bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM students s
+			WHERE s.id = $2 AND (
+				EXISTS (SELECT 1 FROM class_subject_teacher a WHERE a.class_id = s.class_id AND a.teacher_id = $1)
+				OR EXISTS (SELECT 1 FROM classes c WHERE c.id = s.class_id AND c.homeroom_teacher_id = $1)
+			)
+		)`,
+		teacherID, studentID,
+	).Scan(&ok)
+	return ok, mapErr("check teacher student", err)
+}
@@ -0,0 +1,444 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AttendanceRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAttendanceRepo(pool *pgxpool.Pool) *AttendanceRepo {
+	return &AttendanceRepo{pool: pool}
+}
+
+// nullDate — нулевая дата как NULL (граница периода не задана).
+func nullDate(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+// --- Причины отсутствия ---
+
+func (r *AttendanceRepo) ListReasons(ctx context.Context) ([]domain.AbsenceReason, error) {
+	rows, err := r.pool.Query(ctx, `SELECT id, code, name, excused FROM absence_reasons ORDER BY id`)
+	if err != nil {
+		return nil, mapErr("list absence reasons", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.AbsenceReason, 0)
+	for rows.Next() {
+		var a domain.AbsenceReason
+		if err := rows.Scan(&a.ID, &a.Code, &a.Name, &a.Excused); err != nil {
+			return nil, mapErr("scan absence reason", err)
+		}
+		out = append(out, a)
+	}
+
+	return out, mapErr("list absence reasons", rows.Err())
+}
+
+func (r *AttendanceRepo) GetReason(ctx context.Context, id int64) (domain.AbsenceReason, error) {
+	var a domain.AbsenceReason
+	err := r.pool.QueryRow(ctx, `SELECT id, code, name, excused FROM absence_reasons WHERE id = $1`, id).
+		Scan(&a.ID, &a.Code, &a.Name, &a.Excused)
+	return a, mapErr("get absence reason", err)
+}
+
+// --- Переклички ---
+
+const rollCallColumns = `id, class_id, date, lesson, subject_id, taken_by, created_at, updated_at`
+
+func scanRollCall(row pgx.Row) (domain.RollCall, error) {
+	var rc domain.RollCall
+	err := row.Scan(&rc.ID, &rc.ClassID, &rc.Date, &rc.Lesson, &rc.SubjectID, &rc.TakenBy, &rc.CreatedAt, &rc.UpdatedAt)
+	return rc, err
+}
+
+const markColumns = `m.id, m.roll_call_id, m.student_id, m.status, m.reason_id, m.minutes_late, m.note, rc.date, rc.lesson`
+
+func scanMark(row pgx.Row) (domain.AttendanceMark, error) {
+	var m domain.AttendanceMark
+	err := row.Scan(&m.ID, &m.RollCallID, &m.StudentID, &m.Status, &m.ReasonID, &m.MinutesLate, &m.Note, &m.Date, &m.Lesson)
+	return m, err
+}
+
+// SaveRollCall в одной транзакции создаёт перекличку и её отметки. Если перекличка
+// за этот день/урок уже есть: без overwrite — ErrConflict, с overwrite — отметки заменяются целиком.
+// created сообщает, была ли перекличка создана.
+func (r *AttendanceRepo) SaveRollCall(ctx context.Context, rc domain.RollCall, entries []domain.AttendanceEntry, overwrite bool) (res domain.RollCallResult, created bool, err error) {
+	err = pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		saved, err := scanRollCall(tx.QueryRow(ctx, `
+			INSERT INTO roll_calls (class_id, date, lesson, subject_id, taken_by)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (class_id, date, lesson) DO NOTHING
+			RETURNING `+rollCallColumns,
+			rc.ClassID, rc.Date, rc.Lesson, rc.SubjectID, rc.TakenBy,
+		))
+		switch {
+		case err == nil:
+			created = true
+		case !errors.Is(err, pgx.ErrNoRows):
+			return err
+		case !overwrite:
+			return domainerr.Newf(domainerr.ErrConflict, "attendance for %s lesson %d is already recorded", rc.Date.Format(time.DateOnly), rc.Lesson)
+		default:
+			saved, err = scanRollCall(tx.QueryRow(ctx, `
+				UPDATE roll_calls SET subject_id = $4, taken_by = $5, updated_at = now()
+				WHERE class_id = $1 AND date = $2 AND lesson = $3
+				RETURNING `+rollCallColumns,
+				rc.ClassID, rc.Date, rc.Lesson, rc.SubjectID, rc.TakenBy,
+			))
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, `DELETE FROM attendance_marks WHERE roll_call_id = $1`, saved.ID); err != nil {
+				return err
+			}
+		}
+		res.RollCall = saved
+
+		batch := &pgx.Batch{}
+		for _, e := range entries {
+			batch.Queue(`
+				INSERT INTO attendance_marks (roll_call_id, student_id, status, reason_id, minutes_late, note)
+				VALUES ($1, $2, $3, $4, $5, $6)
+				RETURNING id, roll_call_id, student_id, status, reason_id, minutes_late, note`,
+				saved.ID, e.StudentID, e.Status, e.ReasonID, e.MinutesLate, e.Note,
+			)
+		}
+
+		br := tx.SendBatch(ctx, batch)
+		defer br.Close()
+
+		res.Marks = make([]domain.AttendanceMark, 0, len(entries))
+		for range entries {
+			var m domain.AttendanceMark
+			if err := br.QueryRow().Scan(&m.ID, &m.RollCallID, &m.StudentID, &m.Status, &m.ReasonID, &m.MinutesLate, &m.Note); err != nil {
+				return err
+			}
+			m.Date, m.Lesson = saved.Date, saved.Lesson
+			res.Marks = append(res.Marks, m)
+		}
+		return br.Close()
+	})
+
+	return res, created, mapErr("save roll call", err)
+}
+
+// ListRollCalls — переклички класса за день вместе с отметками.
+func (r *AttendanceRepo) ListRollCalls(ctx context.Context, classID int64, date time.Time) ([]domain.RollCallResult, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+rollCallColumns+` FROM roll_calls
+		WHERE class_id = $1 AND date = $2
+		ORDER BY lesson`,
+		classID, date,
+	)
+	if err != nil {
+		return nil, mapErr("list roll calls", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.RollCallResult, 0)
+	index := make(map[int64]int)
+	for rows.Next() {
+		rc, err := scanRollCall(rows)
+		if err != nil {
+			return nil, mapErr("scan roll call", err)
+		}
+		index[rc.ID] = len(out)
+		out = append(out, domain.RollCallResult{RollCall: rc, Marks: make([]domain.AttendanceMark, 0)})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapErr("list roll calls", err)
+	}
+	if len(out) == 0 {
+		return out, nil
+	}
+
+	rows, err = r.pool.Query(ctx, `
+		SELECT `+markColumns+`
+		FROM attendance_marks m
+		JOIN roll_calls rc ON rc.id = m.roll_call_id
+		JOIN students s    ON s.id = m.student_id
+		WHERE rc.class_id = $1 AND rc.date = $2
+		ORDER BY rc.lesson, s.last_name, s.first_name`,
+		classID, date,
+	)
+	if err != nil {
+		return nil, mapErr("list attendance marks", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m, err := scanMark(rows)
+		if err != nil {
+			return nil, mapErr("scan attendance mark", err)
+		}
+		i := index[m.RollCallID]
+		out[i].Marks = append(out[i].Marks, m)
+	}
+
+	return out, mapErr("list attendance marks", rows.Err())
+}
+
+func (r *AttendanceRepo) ListStudentMarks(ctx context.Context, studentID int64, p domain.DateRange) ([]domain.AttendanceMark, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+markColumns+`
+		FROM attendance_marks m
+		JOIN roll_calls rc ON rc.id = m.roll_call_id
+		WHERE m.student_id = $1
+		  AND ($2::DATE IS NULL OR rc.date >= $2) AND ($3::DATE IS NULL OR rc.date <= $3)
+		ORDER BY rc.date, rc.lesson`,
+		studentID, nullDate(p.From), nullDate(p.To),
+	)
+	if err != nil {
+		return nil, mapErr("list student attendance", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.AttendanceMark, 0)
+	for rows.Next() {
+		m, err := scanMark(rows)
+		if err != nil {
+			return nil, mapErr("scan attendance mark", err)
+		}
+		out = append(out, m)
+	}
+
+	return out, mapErr("list student attendance", rows.Err())
+}
+
+// summaryColumns — счётчики отметок по статусам и общее число.
+const summaryColumns = `
+	count(*) FILTER (WHERE m.status = 'present'),
+	count(*) FILTER (WHERE m.status = 'absent'),
+	count(*) FILTER (WHERE m.status = 'late'),
+	count(*) FILTER (WHERE m.status = 'excused'),
+	count(*)`
+
+func (r *AttendanceRepo) StudentSummary(ctx context.Context, studentID int64, p domain.DateRange) (domain.AttendanceSummary, error) {
+	sum := domain.AttendanceSummary{StudentID: studentID}
+	err := r.pool.QueryRow(ctx, `
+		SELECT `+summaryColumns+`
+		FROM attendance_marks m
+		JOIN roll_calls rc ON rc.id = m.roll_call_id
+		WHERE m.student_id = $1
+		  AND ($2::DATE IS NULL OR rc.date >= $2) AND ($3::DATE IS NULL OR rc.date <= $3)`,
+		studentID, nullDate(p.From), nullDate(p.To),
+	).Scan(&sum.Present, &sum.Absent, &sum.Late, &sum.Excused, &sum.Total)
+	return sum, mapErr("student attendance summary", err)
+}
+
+// ClassSummary — счётчики по каждому ученику, отмеченному на перекличках класса за период.
+func (r *AttendanceRepo) ClassSummary(ctx context.Context, classID int64, p domain.DateRange) ([]domain.AttendanceSummary, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT s.id, s.last_name || ' ' || s.first_name, `+summaryColumns+`
+		FROM attendance_marks m
+		JOIN roll_calls rc ON rc.id = m.roll_call_id
+		JOIN students s    ON s.id = m.student_id
+		WHERE rc.class_id = $1
+		  AND ($2::DATE IS NULL OR rc.date >= $2) AND ($3::DATE IS NULL OR rc.date <= $3)
+		GROUP BY s.id, s.last_name, s.first_name
+		ORDER BY s.last_name, s.first_name`,
+		classID, nullDate(p.From), nullDate(p.To),
+	)
+	if err != nil {
+		return nil, mapErr("class attendance summary", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.AttendanceSummary, 0)
+	for rows.Next() {
+		var s domain.AttendanceSummary
+		if err := rows.Scan(&s.StudentID, &s.StudentName, &s.Present, &s.Absent, &s.Late, &s.Excused, &s.Total); err != nil {
+			return nil, mapErr("scan attendance summary", err)
+		}
+		out = append(out, s)
+	}
+
+	return out, mapErr("class attendance summary", rows.Err())
+}
+
+// --- Объяснительные ---
+
+const excuseColumns = `id, student_id, date_from, date_to, reason_id, text, status, submitted_by, reviewed_by, reviewed_at, created_at`
+
+func scanExcuse(row pgx.Row) (domain.ExcuseNote, error) {
+	var e domain.ExcuseNote
+	err := row.Scan(&e.ID, &e.StudentID, &e.DateFrom, &e.DateTo, &e.ReasonID, &e.Text, &e.Status,
+		&e.SubmittedBy, &e.ReviewedBy, &e.ReviewedAt, &e.CreatedAt)
+	e.Files = []domain.File{}
+	return e, err
+}
+
+func (r *AttendanceRepo) CreateExcuse(ctx context.Context, e domain.ExcuseNote) (domain.ExcuseNote, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO excuse_notes (student_id, date_from, date_to, reason_id, text, submitted_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+excuseColumns,
+		e.StudentID, e.DateFrom, e.DateTo, e.ReasonID, e.Text, e.SubmittedBy,
+	)
+	created, err := scanExcuse(row)
+	return created, mapErr("create excuse note", err)
+}
+
+func (r *AttendanceRepo) ListExcuses(ctx context.Context, studentID int64) ([]domain.ExcuseNote, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+excuseColumns+` FROM excuse_notes
+		WHERE student_id = $1
+		ORDER BY date_from DESC, id DESC`,
+		studentID,
+	)
+	if err != nil {
+		return nil, mapErr("list excuse notes", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.ExcuseNote, 0)
+	ids := make([]int64, 0)
+	for rows.Next() {
+		e, err := scanExcuse(rows)
+		if err != nil {
+			return nil, mapErr("scan excuse note", err)
+		}
+		out = append(out, e)
+		ids = append(ids, e.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapErr("list excuse notes", err)
+	}
+
+	files, err := r.excuseFiles(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range out {
+		if f, ok := files[out[i].ID]; ok {
+			out[i].Files = f
+		}
+	}
+	return out, nil
+}
+
+func (r *AttendanceRepo) GetExcuse(ctx context.Context, id int64) (domain.ExcuseNote, error) {
+	e, err := scanExcuse(r.pool.QueryRow(ctx, `SELECT `+excuseColumns+` FROM excuse_notes WHERE id = $1`, id))
+	if err != nil {
+		return domain.ExcuseNote{}, mapErr("get excuse note", err)
+	}
+	files, err := r.excuseFiles(ctx, []int64{id})
+	if err != nil {
+		return domain.ExcuseNote{}, err
+	}
+	if f, ok := files[id]; ok {
+		e.Files = f
+	}
+	return e, nil
+}
+
+// AddExcuseFile прикладывает файл к объяснительной, пока она ждёт решения; иначе — ErrConflict.
+func (r *AttendanceRepo) AddExcuseFile(ctx context.Context, excuseID int64, f domain.File) (domain.File, error) {
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO excuse_files (excuse_id, storage_key, file_name, content_type, size, uploaded_by)
+		SELECT id, $2, $3, $4, $5, $6 FROM excuse_notes WHERE id = $1 AND status = 'pending'
+		RETURNING id, created_at`,
+		excuseID, f.Key, f.Name, f.ContentType, f.Size, f.UploadedBy,
+	).Scan(&f.ID, &f.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.File{}, fmt.Errorf("add excuse file: %w", domainerr.Newf(domainerr.ErrConflict, "excuse note is already reviewed"))
+	}
+	return f, mapErr("add excuse file", err)
+}
+
+// excuseFiles — файлы объяснительных, сгруппированные по excuse_id.
+func (r *AttendanceRepo) excuseFiles(ctx context.Context, ids []int64) (map[int64][]domain.File, error) {
+	out := make(map[int64][]domain.File)
+	if len(ids) == 0 {
+		return out, nil
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT excuse_id, id, storage_key, file_name, content_type, size, uploaded_by, created_at
+		FROM excuse_files WHERE excuse_id = ANY($1)
+		ORDER BY id`,
+		ids,
+	)
+	if err != nil {
+		return nil, mapErr("list excuse files", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			excuseID int64
+			f        domain.File
+		)
+		if err := rows.Scan(&excuseID, &f.ID, &f.Key, &f.Name, &f.ContentType, &f.Size, &f.UploadedBy, &f.CreatedAt); err != nil {
+			return nil, mapErr("scan excuse file", err)
+		}
+		out[excuseID] = append(out[excuseID], f)
+	}
+	return out, mapErr("list excuse files", rows.Err())
+}
+
+// ReviewExcuse фиксирует решение по ожидающей объяснительной. При одобрении пропуски
+// (absent) ученика за период переводятся в excused с причиной из объяснительной.
+// Уже рассмотренная объяснительная — ErrConflict.
+func (r *AttendanceRepo) ReviewExcuse(ctx context.Context, id int64, status domain.ExcuseStatus, reviewerID int64) (domain.ExcuseNote, error) {
+	var e domain.ExcuseNote
+
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		var err error
+		e, err = scanExcuse(tx.QueryRow(ctx, `
+			UPDATE excuse_notes SET status = $2, reviewed_by = $3, reviewed_at = now()
+			WHERE id = $1 AND status = 'pending'
+			RETURNING `+excuseColumns,
+			id, status, reviewerID,
+		))
+		if errors.Is(err, pgx.ErrNoRows) {
+			var exists bool
+			if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM excuse_notes WHERE id = $1)`, id).Scan(&exists); err != nil {
+				return err
+			}
+			if !exists {
+				return domainerr.ErrNotFound
+			}
+			return domainerr.Newf(domainerr.ErrConflict, "excuse note is already reviewed")
+		}
+		if err != nil || status != domain.ExcuseApproved {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE attendance_marks m SET status = 'excused', reason_id = $4
+			FROM roll_calls rc
+			WHERE rc.id = m.roll_call_id AND m.student_id = $1 AND m.status = 'absent'
+			  AND rc.date BETWEEN $2 AND $3`,
+			e.StudentID, e.DateFrom, e.DateTo, e.ReasonID,
+		)
+		return err
+	})
+	if err != nil {
+		return domain.ExcuseNote{}, mapErr("review excuse note", err)
+	}
+
+	files, err := r.excuseFiles(ctx, []int64{e.ID})
+	if err != nil {
+		return domain.ExcuseNote{}, err
+	}
+	if f, ok := files[e.ID]; ok {
+		e.Files = f
+	}
+	return e, nil
+}
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CalendarTokenRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewCalendarTokenRepo(pool *pgxpool.Pool) *CalendarTokenRepo {
+	return &CalendarTokenRepo{pool: pool}
+}
+
+// Save выдаёт учётке новый токен; прежний перестаёт действовать.
+func (r *CalendarTokenRepo) Save(ctx context.Context, execID int64, hash []byte) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO calendar_tokens (exec_id, token_hash) VALUES ($1, $2)
+		ON CONFLICT (exec_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = now()`,
+		execID, hash,
+	)
+	return mapErr("save calendar token", err)
+}
+
+func (r *CalendarTokenRepo) ExecByTokenHash(ctx context.Context, hash []byte) (int64, error) {
+	var execID int64
+	err := r.pool.QueryRow(ctx, `SELECT exec_id FROM calendar_tokens WHERE token_hash = $1`, hash).Scan(&execID)
+	return execID, mapErr("get calendar token", err)
+}
+
+// Delete отзывает токен. Отсутствие токена — не ошибка.
+func (r *CalendarTokenRepo) Delete(ctx context.Context, execID int64) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM calendar_tokens WHERE exec_id = $1`, execID)
+	return mapErr("delete calendar token", err)
+}
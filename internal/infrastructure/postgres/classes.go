@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ClassRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewClassRepo(pool *pgxpool.Pool) *ClassRepo {
+	return &ClassRepo{pool: pool}
+}
+
+const classColumns = `id, name, grade, academic_year, homeroom_teacher_id, created_at, updated_at`
+
+func scanClass(row pgx.Row) (domain.Class, error) {
+	var c domain.Class
+	err := row.Scan(&c.ID, &c.Name, &c.Grade, &c.AcademicYear, &c.HomeroomTeacherID, &c.CreatedAt, &c.UpdatedAt)
+	return c, err
+}
+
+func (r *ClassRepo) List(ctx context.Context, f domain.ClassFilter) ([]domain.Class, error) {
+	var (
+		where []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if f.AcademicYear != 0 {
+		where = append(where, "academic_year = "+arg(f.AcademicYear))
+	}
+	if f.Grade != 0 {
+		where = append(where, "grade = "+arg(f.Grade))
+	}
+
+	q := `SELECT ` + classColumns + ` FROM classes`
+	if len(where) > 0 {
+		q += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	q += ` ORDER BY academic_year DESC, grade, name LIMIT ` + arg(f.Limit) + ` OFFSET ` + arg(f.Offset)
+
+	rows, err := r.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, mapErr("list classes", err)
+	}
+	defer rows.Close()
+
+	classes := make([]domain.Class, 0)
+	for rows.Next() {
+		c, err := scanClass(rows)
+		if err != nil {
+			return nil, mapErr("scan class", err)
+		}
+		classes = append(classes, c)
+	}
+
+	return classes, mapErr("list classes", rows.Err())
+}
+
+func (r *ClassRepo) Get(ctx context.Context, id int64) (domain.Class, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+classColumns+` FROM classes WHERE id = $1`, id)
+	c, err := scanClass(row)
+	return c, mapErr("get class", err)
+}
+
+func (r *ClassRepo) Create(ctx context.Context, c domain.Class) (domain.Class, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO classes (name, grade, academic_year, homeroom_teacher_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+classColumns,
+		c.Name, c.Grade, c.AcademicYear, c.HomeroomTeacherID,
+	)
+	created, err := scanClass(row)
+	return created, mapErr("create class", err)
+}
+
+func (r *ClassRepo) Update(ctx context.Context, c domain.Class) (domain.Class, error) {
+	row := r.pool.QueryRow(ctx, `
+		UPDATE classes
+		SET name = $2, grade = $3, academic_year = $4, homeroom_teacher_id = $5, updated_at = now()
+		WHERE id = $1
+		RETURNING `+classColumns,
+		c.ID, c.Name, c.Grade, c.AcademicYear, c.HomeroomTeacherID,
+	)
+	updated, err := scanClass(row)
+	return updated, mapErr("update class", err)
+}
+
+func (r *ClassRepo) Delete(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM classes WHERE id = $1`, id)
+	if err != nil {
+		return mapErr("delete class", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return mapErr("delete class", domainerr.ErrNotFound)
+	}
+	return nil
+}
+
+// StudentsOutsideClass возвращает id из списка, которые не числятся в классе.
+func (r *ClassRepo) StudentsOutsideClass(ctx context.Context, classID int64, ids []int64) ([]int64, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT x.id FROM unnest($2::BIGINT[]) AS x (id)
+		WHERE NOT EXISTS (SELECT 1 FROM students s WHERE s.id = x.id AND s.class_id = $1)`,
+		classID, ids,
+	)
+	if err != nil {
+		return nil, mapErr("check class students", err)
+	}
+	defer rows.Close()
+
+	out, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	return out, mapErr("check class students", err)
+}
+
+// CountStudents — число учеников в каждом из классов.
+func (r *ClassRepo) CountStudents(ctx context.Context, classIDs []int64) (map[int64]int, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT class_id, count(*) FROM students
+		WHERE class_id = ANY ($1)
+		GROUP BY class_id`,
+		classIDs,
+	)
+	if err != nil {
+		return nil, mapErr("count class students", err)
+	}
+	defer rows.Close()
+
+	out := make(map[int64]int, len(classIDs))
+	for rows.Next() {
+		var (
+			id int64
+			n  int
+		)
+		if err := rows.Scan(&id, &n); err != nil {
+			return nil, mapErr("count class students", err)
+		}
+		out[id] = n
+	}
+
+	return out, mapErr("count class students", rows.Err())
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Коды ошибок Postgres, которые переводим в доменные.
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+	pgExclusionViolation  = "23P01"
+)
+
+// mapErr переводит ошибки pgx в доменные (ErrNotFound/ErrConflict/ErrBadInput),
+// остальное оборачивает с контекстом op.
+func mapErr(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%s: %w", op, domainerr.ErrNotFound)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation, pgExclusionViolation:
+			return fmt.Errorf("%s: %w: %s", op, domainerr.ErrConflict, pgErr.ConstraintName)
+		case pgForeignKeyViolation, pgCheckViolation:
+			return fmt.Errorf("%s: %w: %s", op, domainerr.ErrBadInput, pgErr.ConstraintName)
+		}
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
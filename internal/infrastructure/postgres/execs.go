@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ExecRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewExecRepo(pool *pgxpool.Pool) *ExecRepo {
+	return &ExecRepo{pool: pool}
+}
+
+const execColumns = `id, first_name, last_name, email, username, password_hash, role, teacher_id, student_id,
+	guardian_id, active, last_login_at, created_at, updated_at`
+
+func scanExec(row pgx.Row) (domain.Exec, error) {
+	var e domain.Exec
+	err := row.Scan(
+		&e.ID, &e.FirstName, &e.LastName, &e.Email, &e.Username, &e.PasswordHash,
+		&e.Role, &e.TeacherID, &e.StudentID, &e.GuardianID, &e.Active, &e.LastLoginAt, &e.CreatedAt, &e.UpdatedAt,
+	)
+	return e, err
+}
+
+func (r *ExecRepo) List(ctx context.Context, search string, limit, offset int) ([]domain.Exec, error) {
+	q := `SELECT ` + execColumns + ` FROM execs`
+	args := []any{}
+
+	if s := strings.TrimSpace(search); s != "" {
+		args = append(args, "%"+s+"%")
+		q += ` WHERE first_name ILIKE $1 OR last_name ILIKE $1 OR email ILIKE $1 OR username ILIKE $1`
+	}
+
+	args = append(args, limit, offset)
+	q += ` ORDER BY id LIMIT $` + strconv.Itoa(len(args)-1) + ` OFFSET $` + strconv.Itoa(len(args))
+
+	rows, err := r.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, mapErr("list execs", err)
+	}
+	defer rows.Close()
+
+	execs := make([]domain.Exec, 0)
+	for rows.Next() {
+		e, err := scanExec(rows)
+		if err != nil {
+			return nil, mapErr("scan exec", err)
+		}
+		execs = append(execs, e)
+	}
+
+	return execs, mapErr("list execs", rows.Err())
+}
+
+func (r *ExecRepo) Get(ctx context.Context, id int64) (domain.Exec, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+execColumns+` FROM execs WHERE id = $1`, id)
+	e, err := scanExec(row)
+	return e, mapErr("get exec", err)
+}
+
+func (r *ExecRepo) GetByUsername(ctx context.Context, username string) (domain.Exec, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+execColumns+` FROM execs WHERE lower(username) = lower($1)`, username)
+	e, err := scanExec(row)
+	return e, mapErr("get exec by username", err)
+}
+
+func (r *ExecRepo) Count(ctx context.Context) (int, error) {
+	var n int
+	err := r.pool.QueryRow(ctx, `SELECT count(*) FROM execs`).Scan(&n)
+	return n, mapErr("count execs", err)
+}
+
+func (r *ExecRepo) Create(ctx context.Context, e domain.Exec) (domain.Exec, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO execs (first_name, last_name, email, username, password_hash, role, teacher_id, student_id, guardian_id, active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING `+execColumns,
+		e.FirstName, e.LastName, e.Email, e.Username, e.PasswordHash, e.Role, e.TeacherID, e.StudentID, e.GuardianID, e.Active,
+	)
+	created, err := scanExec(row)
+	return created, mapErr("create exec", err)
+}
+
+func (r *ExecRepo) Update(ctx context.Context, e domain.Exec) (domain.Exec, error) {
+	row := r.pool.QueryRow(ctx, `
+		UPDATE execs
+		SET first_name = $2, last_name = $3, email = $4, username = $5, password_hash = $6,
+		    role = $7, teacher_id = $8, student_id = $9, guardian_id = $10, active = $11, updated_at = now()
+		WHERE id = $1
+		RETURNING `+execColumns,
+		e.ID, e.FirstName, e.LastName, e.Email, e.Username, e.PasswordHash,
+		e.Role, e.TeacherID, e.StudentID, e.GuardianID, e.Active,
+	)
+	updated, err := scanExec(row)
+	return updated, mapErr("update exec", err)
+}
+
+func (r *ExecRepo) TouchLogin(ctx context.Context, id int64) error {
+	_, err := r.pool.Exec(ctx, `UPDATE execs SET last_login_at = now() WHERE id = $1`, id)
+	return mapErr("touch exec login", err)
+}
+
+func (r *ExecRepo) Delete(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM execs WHERE id = $1`, id)
+	if err != nil {
+		return mapErr("delete exec", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return mapErr("delete exec", domainerr.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+package postgres
+
+import (
+	"context"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type GradeRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewGradeRepo(pool *pgxpool.Pool) *GradeRepo {
+	return &GradeRepo{pool: pool}
+}
+
+// --- Категории ---
+
+func (r *GradeRepo) ListCategories(ctx context.Context) ([]domain.GradeCategory, error) {
+	rows, err := r.pool.Query(ctx, `SELECT id, code, name, weight FROM grade_categories ORDER BY weight, code`)
+	if err != nil {
+		return nil, mapErr("list grade categories", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.GradeCategory, 0)
+	for rows.Next() {
+		var c domain.GradeCategory
+		if err := rows.Scan(&c.ID, &c.Code, &c.Name, &c.Weight); err != nil {
+			return nil, mapErr("scan grade category", err)
+		}
+		out = append(out, c)
+	}
+
+	return out, mapErr("list grade categories", rows.Err())
+}
+
+func (r *GradeRepo) GetCategory(ctx context.Context, id int64) (domain.GradeCategory, error) {
+	var c domain.GradeCategory
+	err := r.pool.QueryRow(ctx, `SELECT id, code, name, weight FROM grade_categories WHERE id = $1`, id).
+		Scan(&c.ID, &c.Code, &c.Name, &c.Weight)
+	return c, mapErr("get grade category", err)
+}
+
+// SaveCategory создаёт категорию (ID == 0) или обновляет существующую.
+func (r *GradeRepo) SaveCategory(ctx context.Context, c domain.GradeCategory) (domain.GradeCategory, error) {
+	var row pgx.Row
+	if c.ID == 0 {
+		row = r.pool.QueryRow(ctx, `
+			INSERT INTO grade_categories (code, name, weight) VALUES ($1, $2, $3)
+			RETURNING id, code, name, weight`, c.Code, c.Name, c.Weight)
+	} else {
+		row = r.pool.QueryRow(ctx, `
+			UPDATE grade_categories SET code = $2, name = $3, weight = $4 WHERE id = $1
+			RETURNING id, code, name, weight`, c.ID, c.Code, c.Name, c.Weight)
+	}
+
+	var saved domain.GradeCategory
+	err := row.Scan(&saved.ID, &saved.Code, &saved.Name, &saved.Weight)
+	return saved, mapErr("save grade category", err)
+}
+
+// --- Работы и оценки ---
+
+const assessmentColumns = `id, class_id, subject_id, category_id, term, title, date, max_score, teacher_id, created_at`
+
+func scanAssessment(row pgx.Row) (domain.Assessment, error) {
+	var a domain.Assessment
+	err := row.Scan(&a.ID, &a.ClassID, &a.SubjectID, &a.CategoryID, &a.Term, &a.Title, &a.Date, &a.MaxScore, &a.TeacherID, &a.CreatedAt)
+	return a, err
+}
+
+func (r *GradeRepo) GetAssessment(ctx context.Context, id int64) (domain.Assessment, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+assessmentColumns+` FROM assessments WHERE id = $1`, id)
+	a, err := scanAssessment(row)
+	return a, mapErr("get assessment", err)
+}
+
+func (r *GradeRepo) ListAssessments(ctx context.Context, classID int64, f domain.GradeFilter) ([]domain.Assessment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+assessmentColumns+` FROM assessments
+		WHERE class_id = $1 AND ($2 = 0 OR term = $2) AND ($3 = 0 OR subject_id = $3)
+		ORDER BY date, id`,
+		classID, f.Term, f.SubjectID,
+	)
+	if err != nil {
+		return nil, mapErr("list assessments", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.Assessment, 0)
+	for rows.Next() {
+		a, err := scanAssessment(rows)
+		if err != nil {
+			return nil, mapErr("scan assessment", err)
+		}
+		out = append(out, a)
+	}
+
+	return out, mapErr("list assessments", rows.Err())
+}
+
+func (r *GradeRepo) DeleteAssessment(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM assessments WHERE id = $1`, id)
+	if err != nil {
+		return mapErr("delete assessment", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return mapErr("delete assessment", domainerr.ErrNotFound)
+	}
+	return nil
+}
+
+// SaveBatch в одной транзакции создаёт работу (если a.ID == 0) и upsert-ит оценки.
+func (r *GradeRepo) SaveBatch(ctx context.Context, a domain.Assessment, entries []domain.GradeEntry) (domain.GradeBatchResult, error) {
+	var res domain.GradeBatchResult
+
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if a.ID == 0 {
+			row := tx.QueryRow(ctx, `
+				INSERT INTO assessments (class_id, subject_id, category_id, term, title, date, max_score, teacher_id)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+				RETURNING `+assessmentColumns,
+				a.ClassID, a.SubjectID, a.CategoryID, a.Term, a.Title, a.Date, a.MaxScore, a.TeacherID,
+			)
+			created, err := scanAssessment(row)
+			if err != nil {
+				return err
+			}
+			a = created
+		}
+		res.Assessment = a
+
+		batch := &pgx.Batch{}
+		for _, e := range entries {
+			batch.Queue(`
+				INSERT INTO grades (assessment_id, student_id, score, comment)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (assessment_id, student_id)
+				DO UPDATE SET score = EXCLUDED.score, comment = EXCLUDED.comment, updated_at = now()
+				RETURNING id, assessment_id, student_id, score, comment, updated_at`,
+				a.ID, e.StudentID, e.Score, e.Comment,
+			)
+		}
+
+		br := tx.SendBatch(ctx, batch)
+		defer br.Close()
+
+		res.Grades = make([]domain.Grade, 0, len(entries))
+		for range entries {
+			var g domain.Grade
+			if err := br.QueryRow().Scan(&g.ID, &g.AssessmentID, &g.StudentID, &g.Score, &g.Comment, &g.UpdatedAt); err != nil {
+				return err
+			}
+			g.SubjectID, g.Title, g.Date, g.MaxScore, g.Term = a.SubjectID, a.Title, a.Date, a.MaxScore, a.Term
+			res.Grades = append(res.Grades, g)
+		}
+		return br.Close()
+	})
+
+	return res, mapErr("save grades", err)
+}
+
+// gradeFilterWhere — фильтры GradeFilter по году/четверти/предмету/учителю — $2..$5.
+const gradeFilterWhere = `
+	($2 = 0 OR c.academic_year = $2) AND ($3 = 0 OR a.term = $3) AND ($4 = 0 OR a.subject_id = $4)
+	AND ($5 = 0 OR EXISTS (
+		SELECT 1 FROM class_subject_teacher cst
+		WHERE cst.class_id = a.class_id AND cst.subject_id = a.subject_id AND cst.teacher_id = $5))`
+
+// gradeSelect — оценка с данными работы; фильтры — gradeFilterWhere.
+const gradeSelect = `
+	SELECT g.id, g.assessment_id, g.student_id, g.score, g.comment, g.updated_at,
+	       a.subject_id, sb.name, gc.code, a.title, a.date, a.max_score, a.term
+	FROM grades g
+	JOIN assessments a       ON a.id = g.assessment_id
+	JOIN classes c           ON c.id = a.class_id
+	JOIN subjects sb         ON sb.id = a.subject_id
+	JOIN grade_categories gc ON gc.id = a.category_id
+	WHERE` + gradeFilterWhere
+
+func (r *GradeRepo) listGrades(ctx context.Context, op, where string, id int64, f domain.GradeFilter) ([]domain.Grade, error) {
+	rows, err := r.pool.Query(ctx, gradeSelect+` AND `+where+` ORDER BY a.date, sb.name, g.id`,
+		id, f.AcademicYear, f.Term, f.SubjectID, f.TeacherID)
+	if err != nil {
+		return nil, mapErr(op, err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.Grade, 0)
+	for rows.Next() {
+		var g domain.Grade
+		if err := rows.Scan(
+			&g.ID, &g.AssessmentID, &g.StudentID, &g.Score, &g.Comment, &g.UpdatedAt,
+			&g.SubjectID, &g.SubjectName, &g.CategoryCode, &g.Title, &g.Date, &g.MaxScore, &g.Term,
+		); err != nil {
+			return nil, mapErr(op, err)
+		}
+		out = append(out, g)
+	}
+
+	return out, mapErr(op, rows.Err())
+}
+
+func (r *GradeRepo) ListStudentGrades(ctx context.Context, studentID int64, f domain.GradeFilter) ([]domain.Grade, error) {
+	return r.listGrades(ctx, "list student grades", `g.student_id = $1`, studentID, f)
+}
+
+func (r *GradeRepo) ListClassGrades(ctx context.Context, classID int64, f domain.GradeFilter) ([]domain.Grade, error) {
+	return r.listGrades(ctx, "list class grades", `a.class_id = $1`, classID, f)
+}
+
+// subjectAverages — средневзвешенный процент по предмету: Σ(score/max·weight) / Σweight · 100.
+const subjectAverages = `
+	SELECT g.student_id, a.subject_id,
+	       SUM(g.score / a.max_score * gc.weight) / SUM(gc.weight) * 100 AS avg,
+	       COUNT(*) AS cnt
+	FROM grades g
+	JOIN assessments a       ON a.id = g.assessment_id
+	JOIN classes c           ON c.id = a.class_id
+	JOIN grade_categories gc ON gc.id = a.category_id
+	WHERE` + gradeFilterWhere
+
+func (r *GradeRepo) StudentAverages(ctx context.Context, studentID int64, f domain.GradeFilter) ([]domain.SubjectAverage, error) {
+	rows, err := r.pool.Query(ctx, `
+		WITH avgs AS (`+subjectAverages+` AND g.student_id = $1 GROUP BY g.student_id, a.subject_id)
+		SELECT avgs.student_id, avgs.subject_id, sb.name, ROUND(avgs.avg, 2)::FLOAT8, avgs.cnt::INT
+		FROM avgs JOIN subjects sb ON sb.id = avgs.subject_id
+		ORDER BY sb.name`,
+		studentID, f.AcademicYear, f.Term, f.SubjectID, f.TeacherID,
+	)
+	if err != nil {
+		return nil, mapErr("student averages", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.SubjectAverage, 0)
+	for rows.Next() {
+		var a domain.SubjectAverage
+		if err := rows.Scan(&a.StudentID, &a.SubjectID, &a.SubjectName, &a.Average, &a.GradesCount); err != nil {
+			return nil, mapErr("scan student average", err)
+		}
+		out = append(out, a)
+	}
+
+	return out, mapErr("student averages", rows.Err())
+}
+
+// ClassRanking — рейтинг класса: среднее из средних по предметам, RANK() по убыванию.
+func (r *GradeRepo) ClassRanking(ctx context.Context, classID int64, f domain.GradeFilter) ([]domain.StudentRanking, error) {
+	rows, err := r.pool.Query(ctx, `
+		WITH avgs AS (`+subjectAverages+` AND a.class_id = $1 GROUP BY g.student_id, a.subject_id),
+		     per_student AS (SELECT student_id, AVG(avg) AS avg FROM avgs GROUP BY student_id)
+		SELECT ps.student_id, st.last_name || ' ' || st.first_name,
+		       ROUND(ps.avg, 2)::FLOAT8, RANK() OVER (ORDER BY ps.avg DESC)::INT AS rnk
+		FROM per_student ps JOIN students st ON st.id = ps.student_id
+		ORDER BY rnk, 2`,
+		classID, f.AcademicYear, f.Term, f.SubjectID, f.TeacherID,
+	)
+	if err != nil {
+		return nil, mapErr("class ranking", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.StudentRanking, 0)
+	for rows.Next() {
+		var s domain.StudentRanking
+		if err := rows.Scan(&s.StudentID, &s.StudentName, &s.Average, &s.Rank); err != nil {
+			return nil, mapErr("scan class ranking", err)
+		}
+		out = append(out, s)
+	}
+
+	return out, mapErr("class ranking", rows.Err())
+}
@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"context"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type GradingScaleRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewGradingScaleRepo(pool *pgxpool.Pool) *GradingScaleRepo {
+	return &GradingScaleRepo{pool: pool}
+}
+
+const gradingScaleColumns = `id, code, name, kind, max_points::FLOAT8, is_default, created_at, updated_at`
+
+func scanGradingScale(row pgx.Row) (domain.GradingScale, error) {
+	var s domain.GradingScale
+	err := row.Scan(&s.ID, &s.Code, &s.Name, &s.Kind, &s.MaxPoints, &s.Default, &s.CreatedAt, &s.UpdatedAt)
+	return s, err
+}
+
+// List — шкалы с интервалами.
+func (r *GradingScaleRepo) List(ctx context.Context) ([]domain.GradingScale, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+gradingScaleColumns+` FROM grading_scales ORDER BY is_default DESC, code`)
+	if err != nil {
+		return nil, mapErr("list grading scales", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.GradingScale, 0)
+	for rows.Next() {
+		s, err := scanGradingScale(rows)
+		if err != nil {
+			return nil, mapErr("scan grading scale", err)
+		}
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapErr("list grading scales", err)
+	}
+
+	bands, err := r.bands(ctx, r.pool, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].Bands = bands[out[i].ID]
+	}
+	return out, nil
+}
+
+func (r *GradingScaleRepo) Get(ctx context.Context, id int64) (domain.GradingScale, error) {
+	return r.getBy(ctx, `id = $1`, id)
+}
+
+func (r *GradingScaleRepo) GetByCode(ctx context.Context, code string) (domain.GradingScale, error) {
+	return r.getBy(ctx, `code = $1`, code)
+}
+
+// Default — шкала по умолчанию.
+func (r *GradingScaleRepo) Default(ctx context.Context) (domain.GradingScale, error) {
+	return r.getBy(ctx, `is_default`)
+}
+
+func (r *GradingScaleRepo) getBy(ctx context.Context, where string, args ...any) (domain.GradingScale, error) {
+	s, err := scanGradingScale(r.pool.QueryRow(ctx, `SELECT `+gradingScaleColumns+` FROM grading_scales WHERE `+where, args...))
+	if err != nil {
+		return domain.GradingScale{}, mapErr("get grading scale", err)
+	}
+	bands, err := r.bands(ctx, r.pool, &s.ID)
+	if err != nil {
+		return domain.GradingScale{}, err
+	}
+	s.Bands = bands[s.ID]
+	return s, nil
+}
+
+// Create сохраняет шкалу с интервалами. Новая шкала по умолчанию снимает признак с прежней.
+func (r *GradingScaleRepo) Create(ctx context.Context, s domain.GradingScale) (domain.GradingScale, error) {
+	var created domain.GradingScale
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if err := clearDefaultScale(ctx, tx, s); err != nil {
+			return err
+		}
+		var err error
+		created, err = scanGradingScale(tx.QueryRow(ctx, `
+			INSERT INTO grading_scales (code, name, kind, max_points, is_default) VALUES ($1, $2, $3, $4, $5)
+			RETURNING `+gradingScaleColumns,
+			s.Code, s.Name, s.Kind, s.MaxPoints, s.Default,
+		))
+		if err != nil {
+			return err
+		}
+		created.Bands, err = r.replaceBands(ctx, tx, created.ID, s.Bands)
+		return err
+	})
+	return created, mapErr("create grading scale", err)
+}
+
+// Update заменяет шкалу вместе с интервалами.
+func (r *GradingScaleRepo) Update(ctx context.Context, s domain.GradingScale) (domain.GradingScale, error) {
+	var updated domain.GradingScale
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if err := clearDefaultScale(ctx, tx, s); err != nil {
+			return err
+		}
+		var err error
+		updated, err = scanGradingScale(tx.QueryRow(ctx, `
+			UPDATE grading_scales SET code = $2, name = $3, kind = $4, max_points = $5, is_default = $6, updated_at = now()
+			WHERE id = $1
+			RETURNING `+gradingScaleColumns,
+			s.ID, s.Code, s.Name, s.Kind, s.MaxPoints, s.Default,
+		))
+		if err != nil {
+			return err
+		}
+		updated.Bands, err = r.replaceBands(ctx, tx, updated.ID, s.Bands)
+		return err
+	})
+	return updated, mapErr("update grading scale", err)
+}
+
+func (r *GradingScaleRepo) Delete(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM grading_scales WHERE id = $1`, id)
+	if err != nil {
+		return mapErr("delete grading scale", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return mapErr("delete grading scale", domainerr.ErrNotFound)
+	}
+	return nil
+}
+
+func clearDefaultScale(ctx context.Context, tx pgx.Tx, s domain.GradingScale) error {
+	if !s.Default {
+		return nil
+	}
+	_, err := tx.Exec(ctx, `UPDATE grading_scales SET is_default = false WHERE is_default AND id <> $1`, s.ID)
+	return err
+}
+
+func (r *GradingScaleRepo) replaceBands(ctx context.Context, tx pgx.Tx, scaleID int64, bands []domain.GradeBand) ([]domain.GradeBand, error) {
+	if _, err := tx.Exec(ctx, `DELETE FROM grading_scale_bands WHERE scale_id = $1`, scaleID); err != nil {
+		return nil, err
+	}
+	batch := &pgx.Batch{}
+	for _, b := range bands {
+		batch.Queue(`INSERT INTO grading_scale_bands (scale_id, min_percent, label, points) VALUES ($1, $2, $3, $4)`,
+			scaleID, b.MinPercent, b.Label, b.Points)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return nil, err
+	}
+	saved, err := r.bands(ctx, tx, &scaleID)
+	return saved[scaleID], err
+}
+
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// bands — интервалы шкал по убыванию min_percent; scaleID == nil — всех шкал.
+func (r *GradingScaleRepo) bands(ctx context.Context, q querier, scaleID *int64) (map[int64][]domain.GradeBand, error) {
+	rows, err := q.Query(ctx, `
+		SELECT scale_id, min_percent::FLOAT8, label, points::FLOAT8 FROM grading_scale_bands
+		WHERE $1::BIGINT IS NULL OR scale_id = $1
+		ORDER BY scale_id, min_percent DESC`,
+		scaleID,
+	)
+	if err != nil {
+		return nil, mapErr("list grading scale bands", err)
+	}
+	defer rows.Close()
+
+	out := make(map[int64][]domain.GradeBand)
+	for rows.Next() {
+		var id int64
+		var b domain.GradeBand
+		if err := rows.Scan(&id, &b.MinPercent, &b.Label, &b.Points); err != nil {
+			return nil, mapErr("scan grading scale band", err)
+		}
+		out[id] = append(out[id], b)
+	}
+	return out, mapErr("list grading scale bands", rows.Err())
+}
@@ -0,0 +1,297 @@
+package postgres
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type GuardianRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewGuardianRepo(pool *pgxpool.Pool) *GuardianRepo {
+	return &GuardianRepo{pool: pool}
+}
+
+// guardianSelect — выборка представителя с признаком учётки. Используется и поверх CTE
+// с INSERT/UPDATE, поэтому источник строк называется g.
+const guardianSelect = `
+	SELECT g.id, g.first_name, g.last_name, COALESCE(g.email, ''), COALESCE(g.phone, ''), g.address,
+	       EXISTS (SELECT 1 FROM execs e WHERE e.guardian_id = g.id), g.created_at, g.updated_at
+	FROM g`
+
+func scanGuardian(row pgx.Row) (domain.Guardian, error) {
+	var g domain.Guardian
+	err := row.Scan(&g.ID, &g.FirstName, &g.LastName, &g.Email, &g.Phone, &g.Address, &g.HasAccount, &g.CreatedAt, &g.UpdatedAt)
+	return g, err
+}
+
+func (r *GuardianRepo) List(ctx context.Context, f domain.GuardianFilter) ([]domain.Guardian, error) {
+	q := `WITH g AS (SELECT * FROM guardians`
+	args := []any{}
+
+	if s := strings.TrimSpace(f.Search); s != "" {
+		args = append(args, "%"+s+"%")
+		q += ` WHERE first_name ILIKE $1 OR last_name ILIKE $1 OR email ILIKE $1 OR phone ILIKE $1`
+	}
+	q += `)` + guardianSelect
+
+	args = append(args, f.Limit, f.Offset)
+	q += ` ORDER BY g.last_name, g.first_name, g.id LIMIT $` + strconv.Itoa(len(args)-1) + ` OFFSET $` + strconv.Itoa(len(args))
+
+	rows, err := r.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, mapErr("list guardians", err)
+	}
+	defer rows.Close()
+
+	guardians := make([]domain.Guardian, 0)
+	for rows.Next() {
+		g, err := scanGuardian(rows)
+		if err != nil {
+			return nil, mapErr("scan guardian", err)
+		}
+		guardians = append(guardians, g)
+	}
+
+	return guardians, mapErr("list guardians", rows.Err())
+}
+
+func (r *GuardianRepo) Get(ctx context.Context, id int64) (domain.Guardian, error) {
+	row := r.pool.QueryRow(ctx, `WITH g AS (SELECT * FROM guardians WHERE id = $1)`+guardianSelect, id)
+	g, err := scanGuardian(row)
+	return g, mapErr("get guardian", err)
+}
+
+func (r *GuardianRepo) Create(ctx context.Context, g domain.Guardian) (domain.Guardian, error) {
+	row := r.pool.QueryRow(ctx, `
+		WITH g AS (
+			INSERT INTO guardians (first_name, last_name, email, phone, address)
+			VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5)
+			RETURNING *
+		)`+guardianSelect,
+		g.FirstName, g.LastName, g.Email, g.Phone, g.Address,
+	)
+	created, err := scanGuardian(row)
+	return created, mapErr("create guardian", err)
+}
+
+func (r *GuardianRepo) Update(ctx context.Context, g domain.Guardian) (domain.Guardian, error) {
+	row := r.pool.QueryRow(ctx, `
+		WITH g AS (
+			UPDATE guardians
+			SET first_name = $2, last_name = $3, email = NULLIF($4, ''), phone = NULLIF($5, ''),
+			    address = $6, updated_at = now()
+			WHERE id = $1
+			RETURNING *
+		)`+guardianSelect,
+		g.ID, g.FirstName, g.LastName, g.Email, g.Phone, g.Address,
+	)
+	updated, err := scanGuardian(row)
+	return updated, mapErr("update guardian", err)
+}
+
+// Delete удаляет представителя вместе со связями, приглашениями и учёткой.
+func (r *GuardianRepo) Delete(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM guardians WHERE id = $1`, id)
+	if err != nil {
+		return mapErr("delete guardian", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return mapErr("delete guardian", domainerr.ErrNotFound)
+	}
+	return nil
+}
+
+// ListByStudent — представители ученика, основной контакт первым.
+func (r *GuardianRepo) ListByStudent(ctx context.Context, studentID int64) ([]domain.StudentGuardian, error) {
+	rows, err := r.pool.Query(ctx, `
+		WITH g AS (
+			SELECT gd.*, sg.relationship, sg.is_primary
+			FROM student_guardians sg JOIN guardians gd ON gd.id = sg.guardian_id
+			WHERE sg.student_id = $1
+		)
+		SELECT g.id, g.first_name, g.last_name, COALESCE(g.email, ''), COALESCE(g.phone, ''), g.address,
+		       EXISTS (SELECT 1 FROM execs e WHERE e.guardian_id = g.id), g.created_at, g.updated_at,
+		       g.relationship, g.is_primary
+		FROM g
+		ORDER BY g.is_primary DESC, g.last_name, g.first_name, g.id`,
+		studentID,
+	)
+	if err != nil {
+		return nil, mapErr("list student guardians", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.StudentGuardian, 0)
+	for rows.Next() {
+		var sg domain.StudentGuardian
+		g := &sg.Guardian
+		if err := rows.Scan(
+			&g.ID, &g.FirstName, &g.LastName, &g.Email, &g.Phone, &g.Address, &g.HasAccount, &g.CreatedAt, &g.UpdatedAt,
+			&sg.Relationship, &sg.Primary,
+		); err != nil {
+			return nil, mapErr("scan student guardian", err)
+		}
+		out = append(out, sg)
+	}
+
+	return out, mapErr("list student guardians", rows.Err())
+}
+
+// ListWards — дети представителя.
+func (r *GuardianRepo) ListWards(ctx context.Context, guardianID int64) ([]domain.Ward, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT s.id, s.first_name, s.last_name, COALESCE(s.email, ''), s.birth_date, s.grade,
+		       s.class_id, COALESCE(c.name, ''), s.status, s.created_at, s.updated_at,
+		       sg.relationship, sg.is_primary
+		FROM student_guardians sg
+		JOIN students s ON s.id = sg.student_id
+		LEFT JOIN classes c ON c.id = s.class_id
+		WHERE sg.guardian_id = $1
+		ORDER BY s.last_name, s.first_name, s.id`,
+		guardianID,
+	)
+	if err != nil {
+		return nil, mapErr("list wards", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.Ward, 0)
+	for rows.Next() {
+		var w domain.Ward
+		s := &w.Student
+		if err := rows.Scan(
+			&s.ID, &s.FirstName, &s.LastName, &s.Email, &s.BirthDate, &s.Grade,
+			&s.ClassID, &s.ClassName, &s.Status, &s.CreatedAt, &s.UpdatedAt,
+			&w.Relationship, &w.Primary,
+		); err != nil {
+			return nil, mapErr("scan ward", err)
+		}
+		out = append(out, w)
+	}
+
+	return out, mapErr("list wards", rows.Err())
+}
+
+// Link создаёт или обновляет связь. Основной контакт у ученика один: новый снимает признак с прежнего.
+func (r *GuardianRepo) Link(ctx context.Context, studentID, guardianID int64, l domain.GuardianLink) error {
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if l.Primary {
+			if _, err := tx.Exec(ctx, `
+				UPDATE student_guardians SET is_primary = false
+				WHERE student_id = $1 AND guardian_id <> $2 AND is_primary`,
+				studentID, guardianID,
+			); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO student_guardians (student_id, guardian_id, relationship, is_primary)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (student_id, guardian_id)
+			DO UPDATE SET relationship = EXCLUDED.relationship, is_primary = EXCLUDED.is_primary`,
+			studentID, guardianID, l.Relationship, l.Primary,
+		)
+		return err
+	})
+	return mapErr("link guardian", err)
+}
+
+func (r *GuardianRepo) Unlink(ctx context.Context, studentID, guardianID int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM student_guardians WHERE student_id = $1 AND guardian_id = $2`, studentID, guardianID)
+	if err != nil {
+		return mapErr("unlink guardian", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return mapErr("unlink guardian", domainerr.ErrNotFound)
+	}
+	return nil
+}
+
+func (r *GuardianRepo) IsGuardianOf(ctx context.Context, guardianID, studentID int64) (bool, error) {
+	var ok bool
+	err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM student_guardians WHERE guardian_id = $1 AND student_id = $2)`,
+		guardianID, studentID,
+	).Scan(&ok)
+	return ok, mapErr("check guardian", err)
+}
+
+const inviteColumns = `id, guardian_id, created_by, expires_at, used_at, created_at`
+
+func scanInvite(row pgx.Row) (domain.GuardianInvite, error) {
+	var inv domain.GuardianInvite
+	err := row.Scan(&inv.ID, &inv.GuardianID, &inv.CreatedBy, &inv.ExpiresAt, &inv.UsedAt, &inv.CreatedAt)
+	return inv, err
+}
+
+// CreateInvite сохраняет приглашение; прежние непогашенные приглашения представителя перестают действовать.
+func (r *GuardianRepo) CreateInvite(ctx context.Context, inv domain.GuardianInvite, codeHash []byte) (domain.GuardianInvite, error) {
+	var created domain.GuardianInvite
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `
+			UPDATE guardian_invites SET expires_at = now()
+			WHERE guardian_id = $1 AND used_at IS NULL AND expires_at > now()`,
+			inv.GuardianID,
+		); err != nil {
+			return err
+		}
+
+		var err error
+		created, err = scanInvite(tx.QueryRow(ctx, `
+			INSERT INTO guardian_invites (guardian_id, code_hash, created_by, expires_at)
+			VALUES ($1, $2, $3, $4)
+			RETURNING `+inviteColumns,
+			inv.GuardianID, codeHash, inv.CreatedBy, inv.ExpiresAt,
+		))
+		return err
+	})
+	return created, mapErr("create guardian invite", err)
+}
+
+// ActiveInvite — непогашенное и непросроченное приглашение по хешу кода.
+func (r *GuardianRepo) ActiveInvite(ctx context.Context, codeHash []byte) (domain.GuardianInvite, error) {
+	row := r.pool.QueryRow(ctx, `
+		SELECT `+inviteColumns+` FROM guardian_invites
+		WHERE code_hash = $1 AND used_at IS NULL AND expires_at > now()`,
+		codeHash,
+	)
+	inv, err := scanInvite(row)
+	return inv, mapErr("get guardian invite", err)
+}
+
+// Redeem гасит приглашение и создаёт учётку представителя в одной транзакции:
+// код нельзя использовать дважды, даже при одновременных запросах.
+func (r *GuardianRepo) Redeem(ctx context.Context, inviteID int64, e domain.Exec) (domain.Exec, error) {
+	var created domain.Exec
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE guardian_invites SET used_at = now()
+			WHERE id = $1 AND used_at IS NULL AND expires_at > now()`,
+			inviteID,
+		)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domainerr.ErrNotFound
+		}
+
+		created, err = scanExec(tx.QueryRow(ctx, `
+			INSERT INTO execs (first_name, last_name, email, username, password_hash, role, guardian_id, active)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING `+execColumns,
+			e.FirstName, e.LastName, e.Email, e.Username, e.PasswordHash, e.Role, e.GuardianID, e.Active,
+		))
+		return err
+	})
+	return created, mapErr("redeem guardian invite", err)
+}
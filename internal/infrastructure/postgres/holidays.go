@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type HolidayRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewHolidayRepo(pool *pgxpool.Pool) *HolidayRepo {
+	return &HolidayRepo{pool: pool}
+}
+
+const holidayColumns = `id, name, kind, start_date, end_date, created_at, updated_at`
+
+func scanHoliday(row pgx.Row) (domain.Holiday, error) {
+	var h domain.Holiday
+	err := row.Scan(&h.ID, &h.Name, &h.Kind, &h.StartDate, &h.EndDate, &h.CreatedAt, &h.UpdatedAt)
+	return h, err
+}
+
+// List — каникулы, пересекающиеся с периодом p (нулевые границы не ограничивают).
+func (r *HolidayRepo) List(ctx context.Context, p domain.DateRange) ([]domain.Holiday, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+holidayColumns+` FROM holidays
+		WHERE ($1::DATE IS NULL OR end_date >= $1) AND ($2::DATE IS NULL OR start_date <= $2)
+		ORDER BY start_date, id`,
+		nullDate(p.From), nullDate(p.To),
+	)
+	if err != nil {
+		return nil, mapErr("list holidays", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.Holiday, 0)
+	for rows.Next() {
+		h, err := scanHoliday(rows)
+		if err != nil {
+			return nil, mapErr("scan holiday", err)
+		}
+		out = append(out, h)
+	}
+
+	return out, mapErr("list holidays", rows.Err())
+}
+
+func (r *HolidayRepo) Get(ctx context.Context, id int64) (domain.Holiday, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+holidayColumns+` FROM holidays WHERE id = $1`, id)
+	h, err := scanHoliday(row)
+	return h, mapErr("get holiday", err)
+}
+
+func (r *HolidayRepo) Create(ctx context.Context, h domain.Holiday) (domain.Holiday, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO holidays (name, kind, start_date, end_date) VALUES ($1, $2, $3, $4)
+		RETURNING `+holidayColumns,
+		h.Name, h.Kind, h.StartDate, h.EndDate,
+	)
+	created, err := scanHoliday(row)
+	return created, mapErr("create holiday", err)
+}
+
+func (r *HolidayRepo) Update(ctx context.Context, h domain.Holiday) (domain.Holiday, error) {
+	row := r.pool.QueryRow(ctx, `
+		UPDATE holidays SET name = $2, kind = $3, start_date = $4, end_date = $5, updated_at = now()
+		WHERE id = $1
+		RETURNING `+holidayColumns,
+		h.ID, h.Name, h.Kind, h.StartDate, h.EndDate,
+	)
+	updated, err := scanHoliday(row)
+	return updated, mapErr("update holiday", err)
+}
+
+func (r *HolidayRepo) Delete(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM holidays WHERE id = $1`, id)
+	if err != nil {
+		return mapErr("delete holiday", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return mapErr("delete holiday", domainerr.ErrNotFound)
+	}
+	return nil
+}
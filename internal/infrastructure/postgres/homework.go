@@ -0,0 +1,411 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type HomeworkRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewHomeworkRepo(pool *pgxpool.Pool) *HomeworkRepo {
+	return &HomeworkRepo{pool: pool}
+}
+
+// homeworkSelect — задание с полями его работы в журнале (категория, период, максимум баллов).
+const homeworkSelect = `
+	SELECT h.id, h.class_id, h.subject_id, sb.name, h.teacher_id, h.assessment_id, a.category_id, a.term,
+	       h.title, h.description, h.due_at, a.max_score, h.allow_late, h.created_by, h.created_at, h.updated_at
+	FROM homework h
+	JOIN assessments a ON a.id = h.assessment_id
+	JOIN subjects sb   ON sb.id = h.subject_id`
+
+func scanHomework(row pgx.Row) (domain.Homework, error) {
+	var h domain.Homework
+	err := row.Scan(&h.ID, &h.ClassID, &h.SubjectID, &h.SubjectName, &h.TeacherID, &h.AssessmentID, &h.CategoryID, &h.Term,
+		&h.Title, &h.Description, &h.DueAt, &h.MaxScore, &h.AllowLate, &h.CreatedBy, &h.CreatedAt, &h.UpdatedAt)
+	return h, err
+}
+
+// Get — задание с вложениями.
+func (r *HomeworkRepo) Get(ctx context.Context, id int64) (domain.Homework, error) {
+	h, err := scanHomework(r.pool.QueryRow(ctx, homeworkSelect+` WHERE h.id = $1`, id))
+	if err != nil {
+		return h, mapErr("get homework", err)
+	}
+	files, err := r.files(ctx, `homework_id = ANY($1) AND submission_id IS NULL`, []int64{id})
+	if err != nil {
+		return h, err
+	}
+	h.Attachments = files[id]
+	return h, nil
+}
+
+// ListByClass — задания класса по сроку сдачи.
+func (r *HomeworkRepo) ListByClass(ctx context.Context, classID int64, f domain.HomeworkFilter) ([]domain.Homework, error) {
+	rows, err := r.pool.Query(ctx, homeworkSelect+`
+		WHERE h.class_id = $1 AND ($2 = 0 OR h.subject_id = $2)
+		  AND ($3::TIMESTAMPTZ IS NULL OR h.due_at >= $3) AND ($4::TIMESTAMPTZ IS NULL OR h.due_at < $4)
+		ORDER BY h.due_at, h.id
+		LIMIT $5 OFFSET $6`,
+		classID, f.SubjectID, nullDate(f.DueFrom), nullDate(f.DueTo), f.Limit, f.Offset,
+	)
+	if err != nil {
+		return nil, mapErr("list homework", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.Homework, 0)
+	for rows.Next() {
+		h, err := scanHomework(rows)
+		if err != nil {
+			return nil, mapErr("scan homework", err)
+		}
+		out = append(out, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapErr("list homework", err)
+	}
+
+	return out, r.attach(ctx, out)
+}
+
+// attach заполняет вложения заданий list.
+func (r *HomeworkRepo) attach(ctx context.Context, list []domain.Homework) error {
+	if len(list) == 0 {
+		return nil
+	}
+	ids := make([]int64, len(list))
+	for i, h := range list {
+		ids[i] = h.ID
+	}
+	files, err := r.files(ctx, `homework_id = ANY($1) AND submission_id IS NULL`, ids)
+	if err != nil {
+		return err
+	}
+	for i := range list {
+		list[i].Attachments = files[list[i].ID]
+	}
+	return nil
+}
+
+// Create в одной транзакции заводит работу в журнале и задание к ней.
+func (r *HomeworkRepo) Create(ctx context.Context, a domain.Assessment, h domain.Homework) (domain.Homework, error) {
+	var created domain.Homework
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		var assessmentID int64
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO assessments (class_id, subject_id, category_id, term, title, date, max_score, teacher_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id`,
+			a.ClassID, a.SubjectID, a.CategoryID, a.Term, a.Title, a.Date, a.MaxScore, a.TeacherID,
+		).Scan(&assessmentID); err != nil {
+			return err
+		}
+
+		var id int64
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO homework (class_id, subject_id, teacher_id, assessment_id, title, description, due_at, allow_late, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING id`,
+			h.ClassID, h.SubjectID, h.TeacherID, assessmentID, h.Title, h.Description, h.DueAt, h.AllowLate, h.CreatedBy,
+		).Scan(&id); err != nil {
+			return err
+		}
+
+		var err error
+		created, err = scanHomework(tx.QueryRow(ctx, homeworkSelect+` WHERE h.id = $1`, id))
+		return err
+	})
+	return created, mapErr("create homework", err)
+}
+
+// Update меняет задание и его работу в журнале (предмет и класс не меняются).
+func (r *HomeworkRepo) Update(ctx context.Context, a domain.Assessment, h domain.Homework) (domain.Homework, error) {
+	var updated domain.Homework
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE homework SET title = $2, description = $3, due_at = $4, allow_late = $5, updated_at = now()
+			WHERE id = $1`,
+			h.ID, h.Title, h.Description, h.DueAt, h.AllowLate,
+		)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domainerr.ErrNotFound
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE assessments SET category_id = $2, term = $3, title = $4, date = $5, max_score = $6
+			WHERE id = $1`,
+			h.AssessmentID, a.CategoryID, a.Term, a.Title, a.Date, a.MaxScore,
+		); err != nil {
+			return err
+		}
+
+		updated, err = scanHomework(tx.QueryRow(ctx, homeworkSelect+` WHERE h.id = $1`, h.ID))
+		return err
+	})
+	if err != nil {
+		return updated, mapErr("update homework", err)
+	}
+
+	files, err := r.files(ctx, `homework_id = ANY($1) AND submission_id IS NULL`, []int64{h.ID})
+	if err != nil {
+		return updated, err
+	}
+	updated.Attachments = files[h.ID]
+	return updated, nil
+}
+
+// Delete удаляет задание со сдачами и оценками и возвращает ключи его файлов в хранилище.
+func (r *HomeworkRepo) Delete(ctx context.Context, id int64) ([]string, error) {
+	keys := make([]string, 0)
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `SELECT storage_key FROM homework_files WHERE homework_id = $1`, id)
+		if err != nil {
+			return err
+		}
+		keys, err = pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return err
+		}
+
+		// Работа в журнале держит задание через RESTRICT: сначала задание, потом работа с оценками.
+		var assessmentID int64
+		if err := tx.QueryRow(ctx, `DELETE FROM homework WHERE id = $1 RETURNING assessment_id`, id).Scan(&assessmentID); err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `DELETE FROM assessments WHERE id = $1`, assessmentID)
+		return err
+	})
+	return keys, mapErr("delete homework", err)
+}
+
+// ListForStudent — задания класса classID со сдачами ученика studentID.
+func (r *HomeworkRepo) ListForStudent(ctx context.Context, studentID, classID int64, f domain.HomeworkFilter) ([]domain.StudentHomework, error) {
+	list, err := r.ListByClass(ctx, classID, f)
+	if err != nil || len(list) == 0 {
+		return nil, err
+	}
+
+	ids := make([]int64, len(list))
+	for i, h := range list {
+		ids[i] = h.ID
+	}
+	subs, err := r.submissions(ctx, `s.homework_id = ANY($1) AND s.student_id = $2`, ids, studentID)
+	if err != nil {
+		return nil, err
+	}
+	byHomework := make(map[int64]*domain.Submission, len(subs))
+	for i := range subs {
+		byHomework[subs[i].HomeworkID] = &subs[i]
+	}
+
+	out := make([]domain.StudentHomework, len(list))
+	for i, h := range list {
+		out[i] = domain.StudentHomework{Homework: h, Submission: byHomework[h.ID]}
+	}
+	return out, nil
+}
+
+// --- Файлы ---
+
+const fileColumns = `id, homework_id, submission_id, storage_key, file_name, content_type, size, uploaded_by, created_at`
+
+func scanHomeworkFile(row pgx.Row) (domain.HomeworkFile, error) {
+	var f domain.HomeworkFile
+	err := row.Scan(&f.ID, &f.HomeworkID, &f.SubmissionID, &f.Key, &f.Name, &f.ContentType, &f.Size, &f.UploadedBy, &f.CreatedAt)
+	return f, err
+}
+
+// files — файлы по условию where с параметром $1 (массив id), сгруппированные по заданию или сдаче.
+func (r *HomeworkRepo) files(ctx context.Context, where string, ids []int64) (map[int64][]domain.File, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+fileColumns+` FROM homework_files WHERE `+where+` ORDER BY id`, ids)
+	if err != nil {
+		return nil, mapErr("list homework files", err)
+	}
+	defer rows.Close()
+
+	out := make(map[int64][]domain.File)
+	for rows.Next() {
+		f, err := scanHomeworkFile(rows)
+		if err != nil {
+			return nil, mapErr("scan homework file", err)
+		}
+		owner := f.HomeworkID
+		if f.SubmissionID != nil {
+			owner = *f.SubmissionID
+		}
+		out[owner] = append(out[owner], f.File)
+	}
+
+	return out, mapErr("list homework files", rows.Err())
+}
+
+func (r *HomeworkRepo) AddFile(ctx context.Context, f domain.HomeworkFile) (domain.HomeworkFile, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO homework_files (homework_id, submission_id, storage_key, file_name, content_type, size, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+fileColumns,
+		f.HomeworkID, f.SubmissionID, f.Key, f.Name, f.ContentType, f.Size, f.UploadedBy,
+	)
+	created, err := scanHomeworkFile(row)
+	return created, mapErr("add homework file", err)
+}
+
+func (r *HomeworkRepo) GetFile(ctx context.Context, id int64) (domain.HomeworkFile, error) {
+	f, err := scanHomeworkFile(r.pool.QueryRow(ctx, `SELECT `+fileColumns+` FROM homework_files WHERE id = $1`, id))
+	return f, mapErr("get homework file", err)
+}
+
+func (r *HomeworkRepo) DeleteFile(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM homework_files WHERE id = $1`, id)
+	if err != nil {
+		return mapErr("delete homework file", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return mapErr("delete homework file", domainerr.ErrNotFound)
+	}
+	return nil
+}
+
+// --- Сдачи ---
+
+const submissionSelect = `
+	SELECT s.id, s.homework_id, s.student_id, st.last_name || ' ' || st.first_name, s.body, s.late, s.submitted_at,
+	       s.score::FLOAT8, s.feedback, s.graded_by, s.graded_at, s.updated_at
+	FROM homework_submissions s
+	JOIN students st ON st.id = s.student_id`
+
+func scanSubmission(row pgx.Row) (domain.Submission, error) {
+	var s domain.Submission
+	err := row.Scan(&s.ID, &s.HomeworkID, &s.StudentID, &s.StudentName, &s.Text, &s.Late, &s.SubmittedAt,
+		&s.Score, &s.Feedback, &s.GradedBy, &s.GradedAt, &s.UpdatedAt)
+	return s, err
+}
+
+// submissions — сдачи по условию where с файлами.
+func (r *HomeworkRepo) submissions(ctx context.Context, where string, args ...any) ([]domain.Submission, error) {
+	rows, err := r.pool.Query(ctx, submissionSelect+` WHERE `+where+` ORDER BY st.last_name, st.first_name, s.id`, args...)
+	if err != nil {
+		return nil, mapErr("list submissions", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.Submission, 0)
+	for rows.Next() {
+		s, err := scanSubmission(rows)
+		if err != nil {
+			return nil, mapErr("scan submission", err)
+		}
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapErr("list submissions", err)
+	}
+	if len(out) == 0 {
+		return out, nil
+	}
+
+	ids := make([]int64, len(out))
+	for i, s := range out {
+		ids[i] = s.ID
+	}
+	files, err := r.files(ctx, `submission_id = ANY($1)`, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].Files = files[out[i].ID]
+		if out[i].Files == nil {
+			out[i].Files = []domain.File{}
+		}
+	}
+	return out, nil
+}
+
+// submission — одна сдача по условию where; нет — ErrNotFound.
+func (r *HomeworkRepo) submission(ctx context.Context, op, where string, args ...any) (domain.Submission, error) {
+	list, err := r.submissions(ctx, where, args...)
+	if err != nil {
+		return domain.Submission{}, err
+	}
+	if len(list) == 0 {
+		return domain.Submission{}, mapErr(op, domainerr.ErrNotFound)
+	}
+	return list[0], nil
+}
+
+func (r *HomeworkRepo) GetSubmission(ctx context.Context, id int64) (domain.Submission, error) {
+	return r.submission(ctx, "get submission", `s.id = $1`, id)
+}
+
+// SubmissionFor — сдача ученика по заданию.
+func (r *HomeworkRepo) SubmissionFor(ctx context.Context, homeworkID, studentID int64) (domain.Submission, error) {
+	return r.submission(ctx, "get submission", `s.homework_id = $1 AND s.student_id = $2`, homeworkID, studentID)
+}
+
+// ListSubmissions — все сдачи по заданию.
+func (r *HomeworkRepo) ListSubmissions(ctx context.Context, homeworkID int64) ([]domain.Submission, error) {
+	return r.submissions(ctx, `s.homework_id = $1`, homeworkID)
+}
+
+// SaveSubmission создаёт или заменяет сдачу, пока она не проверена; проверенную — ErrConflict.
+func (r *HomeworkRepo) SaveSubmission(ctx context.Context, s domain.Submission) (domain.Submission, error) {
+	var id int64
+	err := r.pool.QueryRow(ctx, `
+		INSERT INTO homework_submissions (homework_id, student_id, body, late)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (homework_id, student_id)
+		DO UPDATE SET body = EXCLUDED.body, late = EXCLUDED.late, submitted_at = now(), updated_at = now()
+		WHERE homework_submissions.graded_at IS NULL
+		RETURNING id`,
+		s.HomeworkID, s.StudentID, s.Text, s.Late,
+	).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Submission{}, fmt.Errorf("save submission: %w", domainerr.Newf(domainerr.ErrConflict, "submission is already graded"))
+	}
+	if err != nil {
+		return domain.Submission{}, mapErr("save submission", err)
+	}
+	return r.GetSubmission(ctx, id)
+}
+
+// Grade в одной транзакции проверяет сдачу и ставит оценку в журнал по работе задания.
+func (r *HomeworkRepo) Grade(ctx context.Context, s domain.Submission, assessmentID int64) (domain.Submission, error) {
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE homework_submissions
+			SET score = $2, feedback = $3, graded_by = $4, graded_at = now(), updated_at = now()
+			WHERE id = $1`,
+			s.ID, s.Score, s.Feedback, s.GradedBy,
+		)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return domainerr.ErrNotFound
+		}
+		_, err = tx.Exec(ctx, `
+			INSERT INTO grades (assessment_id, student_id, score, comment)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (assessment_id, student_id)
+			DO UPDATE SET score = EXCLUDED.score, comment = EXCLUDED.comment, updated_at = now()`,
+			assessmentID, s.StudentID, s.Score, s.Feedback,
+		)
+		return err
+	})
+	if err != nil {
+		return domain.Submission{}, mapErr("grade submission", err)
+	}
+	return r.GetSubmission(ctx, s.ID)
+}
@@ -0,0 +1,297 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+
+	log "restapi/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrateLockID — ключ pg_advisory_lock, общий для всех реплик (чтобы миграции не шли параллельно).
+// Значение произвольное, но постоянное: ASCII "SCHOOL".
+const migrateLockID int64 = 0x5343484f4f4c
+
+// NilVersion — версия «ни одной миграции не применено».
+const NilVersion = -1
+
+var migrationName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+var ErrDirty = errors.New("database is dirty, fix it manually and run force")
+
+type migration struct {
+	version int64
+	name    string
+	up      string
+	down    string
+}
+
+// Migrator применяет встроенные миграции. Состояние хранится в schema_migrations
+// в том же формате, что у golang-migrate (одна строка version, dirty), — CLI migrate остаётся совместим.
+type Migrator struct {
+	pool       *pgxpool.Pool
+	migrations []migration
+}
+
+func NewMigrator(pool *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*migration)
+	for _, e := range entries {
+		m := migrationName.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: bad version: %w", e.Name(), err)
+		}
+		body, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", e.Name(), err)
+		}
+
+		mg, ok := byVersion[version]
+		if !ok {
+			mg = &migration{version: version, name: m[2]}
+			byVersion[version] = mg
+		}
+		if mg.name != m[2] {
+			return nil, fmt.Errorf("migration %d: conflicting names %q and %q", version, mg.name, m[2])
+		}
+		if m[3] == "up" {
+			mg.up = string(body)
+		} else {
+			mg.down = string(body)
+		}
+	}
+
+	migs := make([]migration, 0, len(byVersion))
+	for _, mg := range byVersion {
+		if mg.up == "" {
+			return nil, fmt.Errorf("migration %d_%s: missing up file", mg.version, mg.name)
+		}
+		migs = append(migs, *mg)
+	}
+	slices.SortFunc(migs, func(a, b migration) int { return int(a.version - b.version) })
+
+	return &Migrator{pool: pool, migrations: migs}, nil
+}
+
+// Up применяет все неприменённые миграции.
+func (m *Migrator) Up(ctx context.Context) error {
+	if len(m.migrations) == 0 {
+		return nil
+	}
+	return m.To(ctx, m.migrations[len(m.migrations)-1].version)
+}
+
+// Down откатывает steps последних миграций.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be > 0")
+	}
+
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		cur, err := m.current(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		idx := m.index(cur)
+		if cur != NilVersion && idx < 0 {
+			return fmt.Errorf("current version %d not found among embedded migrations", cur)
+		}
+
+		target := int64(NilVersion)
+		if idx-steps >= 0 {
+			target = m.migrations[idx-steps].version
+		}
+		return m.migrate(ctx, conn, cur, target)
+	})
+}
+
+// To приводит схему к версии target (вверх или вниз). target = NilVersion откатывает всё.
+func (m *Migrator) To(ctx context.Context, target int64) error {
+	if target != NilVersion && m.index(target) < 0 {
+		return fmt.Errorf("migration %d not found", target)
+	}
+
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		cur, err := m.current(ctx, conn)
+		if err != nil {
+			return err
+		}
+		return m.migrate(ctx, conn, cur, target)
+	})
+}
+
+// Version возвращает текущую версию схемы и флаг dirty.
+func (m *Migrator) Version(ctx context.Context) (int64, bool, error) {
+	var (
+		version int64 = NilVersion
+		dirty   bool
+	)
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		return conn.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NilVersion, false, nil
+	}
+	return version, dirty, err
+}
+
+// Force записывает версию без выполнения миграций и снимает dirty.
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != NilVersion && m.index(version) < 0 {
+		return fmt.Errorf("migration %d not found", version)
+	}
+
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		return setVersion(ctx, conn, version)
+	})
+}
+
+func (m *Migrator) migrate(ctx context.Context, conn *pgxpool.Conn, cur, target int64) error {
+	if cur == target {
+		log.Info("migrations: no change", "version", cur)
+		return nil
+	}
+
+	if cur < target {
+		for _, mg := range m.migrations {
+			if mg.version <= cur || mg.version > target {
+				continue
+			}
+			if err := m.apply(ctx, conn, mg.up, mg.version); err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", mg.version, mg.name, err)
+			}
+			log.Info("migration applied", "version", mg.version, "name", mg.name)
+		}
+		return nil
+	}
+
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		mg := m.migrations[i]
+		if mg.version > cur || mg.version <= target {
+			continue
+		}
+		if mg.down == "" {
+			return fmt.Errorf("migration %d_%s: missing down file", mg.version, mg.name)
+		}
+
+		prev := int64(NilVersion)
+		if i > 0 {
+			prev = m.migrations[i-1].version
+		}
+		if err := m.apply(ctx, conn, mg.down, prev); err != nil {
+			return fmt.Errorf("migration %d_%s down: %w", mg.version, mg.name, err)
+		}
+		log.Info("migration rolled back", "version", mg.version, "name", mg.name)
+	}
+	return nil
+}
+
+// apply выполняет одну миграцию в транзакции вместе со сменой версии.
+// При ошибке транзакция откатывается и схема остаётся на прежней версии (не dirty).
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, sql string, newVersion int64) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, sql); err != nil {
+		return err
+	}
+	if err := setVersion(ctx, tx, newVersion); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (m *Migrator) current(ctx context.Context, conn *pgxpool.Conn) (int64, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := conn.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NilVersion, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	if dirty {
+		return 0, fmt.Errorf("version %d: %w", version, ErrDirty)
+	}
+	return version, nil
+}
+
+func (m *Migrator) index(version int64) int {
+	for i, mg := range m.migrations {
+		if mg.version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+// withLock держит advisory lock на выделенном соединении: session-level lock живёт ровно пока живёт conn.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire conn: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrateLockID); err != nil {
+		return fmt.Errorf("acquire migrate lock: %w", err)
+	}
+	defer func() {
+		// Отдельный контекст: при отменённом ctx lock всё равно надо отпустить.
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrateLockID); err != nil {
+			log.Warn("release migrate lock", "err", err)
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT  NOT NULL PRIMARY KEY,
+			dirty   BOOLEAN NOT NULL
+		)`); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+func setVersion(ctx context.Context, db execer, version int64) error {
+	if _, err := db.Exec(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return fmt.Errorf("reset schema version: %w", err)
+	}
+	if version == NilVersion {
+		return nil
+	}
+	if _, err := db.Exec(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, FALSE)`, version); err != nil {
+		return fmt.Errorf("set schema version: %w", err)
+	}
+	return nil
+}
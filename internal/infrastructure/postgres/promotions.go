@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PromotionRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewPromotionRepo(pool *pgxpool.Pool) *PromotionRepo {
+	return &PromotionRepo{pool: pool}
+}
+
+// Apply выполняет план перевода в одной транзакции: создаёт классы нового года, переводит
+// и выпускает учеников, пишет запись в журнал. Если кто-то из учеников успел смениться
+// (другой класс или статус), откатывается всё — план устарел, его надо построить заново.
+func (r *PromotionRepo) Apply(ctx context.Context, plan domain.PromotionPlan, executedBy *int64) (domain.PromotionPlan, error) {
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		created := make(map[string]int64, len(plan.NewClasses))
+		for _, c := range plan.NewClasses {
+			var id int64
+			if err := tx.QueryRow(ctx, `
+				INSERT INTO classes (name, grade, academic_year, homeroom_teacher_id)
+				VALUES ($1, $2, $3, $4)
+				RETURNING id`,
+				c.Name, c.Grade, plan.ToYear, c.HomeroomTeacherID,
+			).Scan(&id); err != nil {
+				return err
+			}
+			created[c.Name] = id
+		}
+
+		batch := &pgx.Batch{}
+		queued := make([]int, 0, len(plan.Items))
+		for i := range plan.Items {
+			it := &plan.Items[i]
+			switch it.Action {
+			case domain.PromotionPromote, domain.PromotionHoldBack:
+				if it.ToClassID == 0 {
+					it.ToClassID = created[it.ToClass]
+				}
+				batch.Queue(`
+					UPDATE students SET class_id = $2, grade = $3, updated_at = now()
+					WHERE id = $1 AND class_id = $4 AND status IN ('enrolled', 'suspended')`,
+					it.StudentID, it.ToClassID, it.ToGrade, it.FromClassID,
+				)
+			case domain.PromotionGraduate:
+				batch.Queue(`
+					UPDATE students SET status = 'graduated', updated_at = now()
+					WHERE id = $1 AND class_id = $2 AND status = 'enrolled'`,
+					it.StudentID, it.FromClassID,
+				)
+			default:
+				continue
+			}
+			queued = append(queued, i)
+		}
+
+		results := tx.SendBatch(ctx, batch)
+		for _, i := range queued {
+			tag, err := results.Exec()
+			if err != nil {
+				_ = results.Close()
+				return err
+			}
+			if tag.RowsAffected() == 0 {
+				_ = results.Close()
+				return domainerr.Newf(domainerr.ErrConflict, "student %d changed class or status since the plan was built",
+					plan.Items[i].StudentID)
+			}
+		}
+		if err := results.Close(); err != nil {
+			return err
+		}
+
+		return tx.QueryRow(ctx, `
+			INSERT INTO promotion_runs (from_year, to_year, final_grade, promoted, held_back, graduated, skipped, plan, executed_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			RETURNING id, executed_at`,
+			plan.FromYear, plan.ToYear, plan.FinalGrade, plan.Summary.Promoted, plan.Summary.HeldBack,
+			plan.Summary.Graduated, plan.Summary.Skipped, plan, executedBy,
+		).Scan(&plan.ID, &plan.ExecutedAt)
+	})
+	if err != nil {
+		return domain.PromotionPlan{}, mapErr("apply promotion", err)
+	}
+	plan.ExecutedBy = executedBy
+	return plan, nil
+}
+
+const promotionRunColumns = `id, from_year, to_year, final_grade, promoted, held_back, graduated, skipped, executed_by, executed_at`
+
+func scanPromotionRun(row pgx.Row, extra ...any) (domain.PromotionPlan, error) {
+	var p domain.PromotionPlan
+	dest := []any{
+		&p.ID, &p.FromYear, &p.ToYear, &p.FinalGrade, &p.Summary.Promoted, &p.Summary.HeldBack,
+		&p.Summary.Graduated, &p.Summary.Skipped, &p.ExecutedBy, &p.ExecutedAt,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	return p, err
+}
+
+// List — журнал переводов без построчного плана.
+func (r *PromotionRepo) List(ctx context.Context) ([]domain.PromotionPlan, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+promotionRunColumns+` FROM promotion_runs ORDER BY from_year DESC`)
+	if err != nil {
+		return nil, mapErr("list promotion runs", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.PromotionPlan, 0)
+	for rows.Next() {
+		p, err := scanPromotionRun(rows)
+		if err != nil {
+			return nil, mapErr("scan promotion run", err)
+		}
+		out = append(out, p)
+	}
+
+	return out, mapErr("list promotion runs", rows.Err())
+}
+
+// Get — запись журнала вместе с выполненным планом.
+func (r *PromotionRepo) Get(ctx context.Context, id int64) (domain.PromotionPlan, error) {
+	var stored domain.PromotionPlan
+	row := r.pool.QueryRow(ctx, `SELECT `+promotionRunColumns+`, plan FROM promotion_runs WHERE id = $1`, id)
+	p, err := scanPromotionRun(row, &stored)
+	if err != nil {
+		return domain.PromotionPlan{}, mapErr("get promotion run", err)
+	}
+	p.NewClasses, p.Items = stored.NewClasses, stored.Items
+	return p, nil
+}
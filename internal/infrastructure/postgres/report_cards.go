@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"context"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReportCardRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewReportCardRepo(pool *pgxpool.Pool) *ReportCardRepo {
+	return &ReportCardRepo{pool: pool}
+}
+
+const reportCardCommentColumns = `id, student_id, term_id, subject_id, author_id, body, created_at, updated_at`
+
+func scanReportCardComment(row pgx.Row) (domain.ReportCardComment, error) {
+	var c domain.ReportCardComment
+	err := row.Scan(&c.ID, &c.StudentID, &c.TermID, &c.SubjectID, &c.AuthorID, &c.Text, &c.CreatedAt, &c.UpdatedAt)
+	return c, err
+}
+
+// ListComments — комментарии за период по ученикам studentIDs.
+func (r *ReportCardRepo) ListComments(ctx context.Context, termID int64, studentIDs []int64) ([]domain.ReportCardComment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+reportCardCommentColumns+` FROM report_card_comments
+		WHERE term_id = $1 AND student_id = ANY($2)
+		ORDER BY student_id, subject_id NULLS FIRST`,
+		termID, studentIDs,
+	)
+	if err != nil {
+		return nil, mapErr("list report card comments", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.ReportCardComment, 0)
+	for rows.Next() {
+		c, err := scanReportCardComment(rows)
+		if err != nil {
+			return nil, mapErr("scan report card comment", err)
+		}
+		out = append(out, c)
+	}
+
+	return out, mapErr("list report card comments", rows.Err())
+}
+
+// SaveComment создаёт или заменяет комментарий ученика за период по предмету.
+func (r *ReportCardRepo) SaveComment(ctx context.Context, c domain.ReportCardComment) (domain.ReportCardComment, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO report_card_comments (student_id, term_id, subject_id, author_id, body)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (student_id, term_id, COALESCE(subject_id, 0))
+		DO UPDATE SET author_id = EXCLUDED.author_id, body = EXCLUDED.body, updated_at = now()
+		RETURNING `+reportCardCommentColumns,
+		c.StudentID, c.TermID, c.SubjectID, c.AuthorID, c.Text,
+	)
+	saved, err := scanReportCardComment(row)
+	return saved, mapErr("save report card comment", err)
+}
+
+func (r *ReportCardRepo) DeleteComment(ctx context.Context, studentID, termID int64, subjectID *int64) error {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM report_card_comments
+		WHERE student_id = $1 AND term_id = $2 AND COALESCE(subject_id, 0) = COALESCE($3::BIGINT, 0)`,
+		studentID, termID, subjectID,
+	)
+	if err != nil {
+		return mapErr("delete report card comment", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return mapErr("delete report card comment", domainerr.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RoomRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewRoomRepo(pool *pgxpool.Pool) *RoomRepo {
+	return &RoomRepo{pool: pool}
+}
+
+const roomColumns = `id, code, name, capacity, created_at, updated_at`
+
+func scanRoom(row pgx.Row) (domain.Room, error) {
+	var rm domain.Room
+	err := row.Scan(&rm.ID, &rm.Code, &rm.Name, &rm.Capacity, &rm.CreatedAt, &rm.UpdatedAt)
+	return rm, err
+}
+
+func (r *RoomRepo) List(ctx context.Context) ([]domain.Room, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+roomColumns+` FROM rooms ORDER BY code`)
+	if err != nil {
+		return nil, mapErr("list rooms", err)
+	}
+	defer rows.Close()
+
+	rooms := make([]domain.Room, 0)
+	for rows.Next() {
+		rm, err := scanRoom(rows)
+		if err != nil {
+			return nil, mapErr("scan room", err)
+		}
+		rooms = append(rooms, rm)
+	}
+
+	return rooms, mapErr("list rooms", rows.Err())
+}
+
+func (r *RoomRepo) Get(ctx context.Context, id int64) (domain.Room, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+roomColumns+` FROM rooms WHERE id = $1`, id)
+	rm, err := scanRoom(row)
+	return rm, mapErr("get room", err)
+}
+
+func (r *RoomRepo) Create(ctx context.Context, rm domain.Room) (domain.Room, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO rooms (code, name, capacity) VALUES ($1, $2, $3)
+		RETURNING `+roomColumns,
+		rm.Code, rm.Name, rm.Capacity,
+	)
+	created, err := scanRoom(row)
+	return created, mapErr("create room", err)
+}
+
+func (r *RoomRepo) Update(ctx context.Context, rm domain.Room) (domain.Room, error) {
+	row := r.pool.QueryRow(ctx, `
+		UPDATE rooms SET code = $2, name = $3, capacity = $4, updated_at = now()
+		WHERE id = $1
+		RETURNING `+roomColumns,
+		rm.ID, rm.Code, rm.Name, rm.Capacity,
+	)
+	updated, err := scanRoom(row)
+	return updated, mapErr("update room", err)
+}
+
+// Delete удаляет кабинет. Если он стоит в расписании, FK RESTRICT даст ErrBadInput.
+func (r *RoomRepo) Delete(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM rooms WHERE id = $1`, id)
+	if err != nil {
+		return mapErr("delete room", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return mapErr("delete room", domainerr.ErrNotFound)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"restapi/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SessionRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewSessionRepo(pool *pgxpool.Pool) *SessionRepo {
+	return &SessionRepo{pool: pool}
+}
+
+const sessionColumns = `id, token_hash, exec_id, user_agent, ip, created_at, last_seen_at, expires_at`
+
+func scanSession(row pgx.Row) (domain.Session, error) {
+	var s domain.Session
+	err := row.Scan(&s.ID, &s.TokenHash, &s.ExecID, &s.UserAgent, &s.IP, &s.CreatedAt, &s.LastSeenAt, &s.ExpiresAt)
+	return s, err
+}
+
+func (r *SessionRepo) Create(ctx context.Context, s domain.Session) (domain.Session, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO sessions (token_hash, exec_id, user_agent, ip, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+sessionColumns,
+		s.TokenHash, s.ExecID, s.UserAgent, s.IP, s.ExpiresAt,
+	)
+	created, err := scanSession(row)
+	return created, mapErr("create session", err)
+}
+
+func (r *SessionRepo) GetByTokenHash(ctx context.Context, hash []byte) (domain.Session, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+sessionColumns+` FROM sessions WHERE token_hash = $1`, hash)
+	s, err := scanSession(row)
+	return s, mapErr("get session", err)
+}
+
+// Touch фиксирует активность и (при sliding renewal) сдвигает срок жизни.
+func (r *SessionRepo) Touch(ctx context.Context, id int64, lastSeen, expiresAt time.Time) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE sessions SET last_seen_at = $2, expires_at = $3 WHERE id = $1`,
+		id, lastSeen, expiresAt,
+	)
+	return mapErr("touch session", err)
+}
+
+func (r *SessionRepo) Delete(ctx context.Context, id int64) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM sessions WHERE id = $1`, id)
+	return mapErr("delete session", err)
+}
+
+func (r *SessionRepo) DeleteByExec(ctx context.Context, execID int64) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM sessions WHERE exec_id = $1`, execID)
+	return mapErr("delete exec sessions", err)
+}
+
+// DeleteExpired чистит протухшие сессии, возвращает число удалённых.
+func (r *SessionRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM sessions WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, mapErr("delete expired sessions", err)
+	}
+	return tag.RowsAffected(), nil
+}
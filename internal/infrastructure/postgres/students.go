@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type StudentRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewStudentRepo(pool *pgxpool.Pool) *StudentRepo {
+	return &StudentRepo{pool: pool}
+}
+
+// studentSelect — выборка ученика вместе с именем класса. Используется и поверх CTE с INSERT/UPDATE,
+// поэтому источник строк называется s.
+const studentSelect = `
+	SELECT s.id, s.first_name, s.last_name, COALESCE(s.email, ''), s.birth_date, s.grade,
+	       s.class_id, COALESCE(c.name, ''), s.status, s.created_at, s.updated_at
+	FROM s LEFT JOIN classes c ON c.id = s.class_id`
+
+func scanStudent(row pgx.Row) (domain.Student, error) {
+	var s domain.Student
+	err := row.Scan(
+		&s.ID, &s.FirstName, &s.LastName, &s.Email, &s.BirthDate, &s.Grade,
+		&s.ClassID, &s.ClassName, &s.Status, &s.CreatedAt, &s.UpdatedAt,
+	)
+	return s, err
+}
+
+func (r *StudentRepo) List(ctx context.Context, f domain.StudentFilter) ([]domain.Student, error) {
+	var (
+		where []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if s := strings.TrimSpace(f.Search); s != "" {
+		p := arg("%" + s + "%")
+		where = append(where, fmt.Sprintf("(first_name ILIKE %[1]s OR last_name ILIKE %[1]s OR email ILIKE %[1]s)", p))
+	}
+	if f.Grade != 0 {
+		where = append(where, "grade = "+arg(f.Grade))
+	}
+	if f.ClassID != 0 {
+		where = append(where, "class_id = "+arg(f.ClassID))
+	}
+	if f.Status != "" {
+		where = append(where, "status = "+arg(f.Status))
+	}
+
+	q := `WITH s AS (SELECT * FROM students`
+	if len(where) > 0 {
+		q += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	q += `)` + studentSelect +
+		` ORDER BY s.grade, c.name NULLS LAST, s.last_name, s.first_name, s.id LIMIT ` + arg(f.Limit) + ` OFFSET ` + arg(f.Offset)
+
+	rows, err := r.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, mapErr("list students", err)
+	}
+	defer rows.Close()
+
+	students := make([]domain.Student, 0)
+	for rows.Next() {
+		s, err := scanStudent(rows)
+		if err != nil {
+			return nil, mapErr("scan student", err)
+		}
+		students = append(students, s)
+	}
+
+	return students, mapErr("list students", rows.Err())
+}
+
+func (r *StudentRepo) Get(ctx context.Context, id int64) (domain.Student, error) {
+	row := r.pool.QueryRow(ctx, `WITH s AS (SELECT * FROM students WHERE id = $1)`+studentSelect, id)
+	s, err := scanStudent(row)
+	return s, mapErr("get student", err)
+}
+
+func (r *StudentRepo) Create(ctx context.Context, s domain.Student) (domain.Student, error) {
+	row := r.pool.QueryRow(ctx, `
+		WITH s AS (
+			INSERT INTO students (first_name, last_name, email, birth_date, grade, class_id, status)
+			VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7)
+			RETURNING *
+		)`+studentSelect,
+		s.FirstName, s.LastName, s.Email, s.BirthDate, s.Grade, s.ClassID, s.Status,
+	)
+	created, err := scanStudent(row)
+	return created, mapErr("create student", err)
+}
+
+// Update сохраняет запись, только если статус в БД всё ещё prevStatus —
+// так параллельный переход не проскочит мимо проверки машины состояний.
+func (r *StudentRepo) Update(ctx context.Context, s domain.Student, prevStatus domain.StudentStatus) (domain.Student, error) {
+	row := r.pool.QueryRow(ctx, `
+		WITH s AS (
+			UPDATE students
+			SET first_name = $2, last_name = $3, email = NULLIF($4, ''), birth_date = $5,
+			    grade = $6, class_id = $7, status = $8, updated_at = now()
+			WHERE id = $1 AND status = $9
+			RETURNING *
+		)`+studentSelect,
+		s.ID, s.FirstName, s.LastName, s.Email, s.BirthDate, s.Grade, s.ClassID, s.Status, prevStatus,
+	)
+	updated, err := scanStudent(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.Student{}, fmt.Errorf("update student: %w", domainerr.Newf(domainerr.ErrConflict, "status changed concurrently"))
+	}
+	return updated, mapErr("update student", err)
+}
+
+func (r *StudentRepo) Delete(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM students WHERE id = $1`, id)
+	if err != nil {
+		return mapErr("delete student", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return mapErr("delete student", domainerr.ErrNotFound)
+	}
+	return nil
+}
+
+// --- Фотография ---
+
+func (r *StudentRepo) GetPhoto(ctx context.Context, studentID int64) (domain.StudentPhoto, error) {
+	var p domain.StudentPhoto
+	err := r.pool.QueryRow(ctx, `
+		SELECT student_id, storage_key, content_type, size, uploaded_by, updated_at
+		FROM student_photos WHERE student_id = $1`,
+		studentID,
+	).Scan(&p.StudentID, &p.Key, &p.ContentType, &p.Size, &p.UploadedBy, &p.UpdatedAt)
+	return p, mapErr("get student photo", err)
+}
+
+// SetPhoto сохраняет фотографию и возвращает ключ заменённой ("" — фотографии не было).
+func (r *StudentRepo) SetPhoto(ctx context.Context, p domain.StudentPhoto) (domain.StudentPhoto, string, error) {
+	var prev *string
+	err := r.pool.QueryRow(ctx, `
+		WITH old AS (SELECT storage_key FROM student_photos WHERE student_id = $1 FOR UPDATE)
+		INSERT INTO student_photos (student_id, storage_key, content_type, size, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (student_id) DO UPDATE
+		SET storage_key = EXCLUDED.storage_key, content_type = EXCLUDED.content_type,
+		    size = EXCLUDED.size, uploaded_by = EXCLUDED.uploaded_by, updated_at = now()
+		RETURNING updated_at, (SELECT storage_key FROM old)`,
+		p.StudentID, p.Key, p.ContentType, p.Size, p.UploadedBy,
+	).Scan(&p.UpdatedAt, &prev)
+	if err != nil {
+		return domain.StudentPhoto{}, "", mapErr("set student photo", err)
+	}
+	if prev == nil {
+		return p, "", nil
+	}
+	return p, *prev, nil
+}
+
+// DeletePhoto удаляет фотографию и возвращает её ключ в хранилище.
+func (r *StudentRepo) DeletePhoto(ctx context.Context, studentID int64) (string, error) {
+	var key string
+	err := r.pool.QueryRow(ctx, `DELETE FROM student_photos WHERE student_id = $1 RETURNING storage_key`, studentID).Scan(&key)
+	return key, mapErr("delete student photo", err)
+}
@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SubjectRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewSubjectRepo(pool *pgxpool.Pool) *SubjectRepo {
+	return &SubjectRepo{pool: pool}
+}
+
+const subjectColumns = `id, code, name, credits::FLOAT8, created_at, updated_at`
+
+func scanSubject(row pgx.Row) (domain.Subject, error) {
+	var s domain.Subject
+	err := row.Scan(&s.ID, &s.Code, &s.Name, &s.Credits, &s.CreatedAt, &s.UpdatedAt)
+	return s, err
+}
+
+func (r *SubjectRepo) List(ctx context.Context) ([]domain.Subject, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+subjectColumns+` FROM subjects ORDER BY name`)
+	if err != nil {
+		return nil, mapErr("list subjects", err)
+	}
+	defer rows.Close()
+
+	subjects := make([]domain.Subject, 0)
+	for rows.Next() {
+		s, err := scanSubject(rows)
+		if err != nil {
+			return nil, mapErr("scan subject", err)
+		}
+		subjects = append(subjects, s)
+	}
+
+	return subjects, mapErr("list subjects", rows.Err())
+}
+
+func (r *SubjectRepo) Get(ctx context.Context, id int64) (domain.Subject, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+subjectColumns+` FROM subjects WHERE id = $1`, id)
+	s, err := scanSubject(row)
+	return s, mapErr("get subject", err)
+}
+
+func (r *SubjectRepo) Create(ctx context.Context, s domain.Subject) (domain.Subject, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO subjects (code, name, credits) VALUES ($1, $2, $3)
+		RETURNING `+subjectColumns,
+		s.Code, s.Name, s.Credits,
+	)
+	created, err := scanSubject(row)
+	return created, mapErr("create subject", err)
+}
+
+func (r *SubjectRepo) Update(ctx context.Context, s domain.Subject) (domain.Subject, error) {
+	row := r.pool.QueryRow(ctx, `
+		UPDATE subjects SET code = $2, name = $3, credits = $4, updated_at = now()
+		WHERE id = $1
+		RETURNING `+subjectColumns,
+		s.ID, s.Code, s.Name, s.Credits,
+	)
+	updated, err := scanSubject(row)
+	return updated, mapErr("update subject", err)
+}
+
+// Delete удаляет предмет. Если он кому-то назначен, FK RESTRICT даст ErrBadInput.
+func (r *SubjectRepo) Delete(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM subjects WHERE id = $1`, id)
+	if err != nil {
+		return mapErr("delete subject", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return mapErr("delete subject", domainerr.ErrNotFound)
+	}
+	return nil
+}
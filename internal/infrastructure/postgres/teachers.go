@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TeacherRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTeacherRepo(pool *pgxpool.Pool) *TeacherRepo {
+	return &TeacherRepo{pool: pool}
+}
+
+const teacherColumns = `id, first_name, last_name, email, phone, created_at, updated_at`
+
+func scanTeacher(row pgx.Row) (domain.Teacher, error) {
+	var t domain.Teacher
+	err := row.Scan(&t.ID, &t.FirstName, &t.LastName, &t.Email, &t.Phone, &t.CreatedAt, &t.UpdatedAt)
+	return t, err
+}
+
+func (r *TeacherRepo) List(ctx context.Context, f domain.TeacherFilter) ([]domain.Teacher, error) {
+	q := `SELECT ` + teacherColumns + ` FROM teachers`
+	args := []any{}
+
+	if s := strings.TrimSpace(f.Search); s != "" {
+		args = append(args, "%"+s+"%")
+		q += ` WHERE first_name ILIKE $1 OR last_name ILIKE $1 OR email ILIKE $1`
+	}
+
+	args = append(args, f.Limit, f.Offset)
+	q += ` ORDER BY id LIMIT $` + strconv.Itoa(len(args)-1) + ` OFFSET $` + strconv.Itoa(len(args))
+
+	rows, err := r.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, mapErr("list teachers", err)
+	}
+	defer rows.Close()
+
+	teachers := make([]domain.Teacher, 0)
+	for rows.Next() {
+		t, err := scanTeacher(rows)
+		if err != nil {
+			return nil, mapErr("scan teacher", err)
+		}
+		teachers = append(teachers, t)
+	}
+
+	return teachers, mapErr("list teachers", rows.Err())
+}
+
+func (r *TeacherRepo) Get(ctx context.Context, id int64) (domain.Teacher, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+teacherColumns+` FROM teachers WHERE id = $1`, id)
+	t, err := scanTeacher(row)
+	return t, mapErr("get teacher", err)
+}
+
+func (r *TeacherRepo) Create(ctx context.Context, t domain.Teacher) (domain.Teacher, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO teachers (first_name, last_name, email, phone)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+teacherColumns,
+		t.FirstName, t.LastName, t.Email, t.Phone,
+	)
+	created, err := scanTeacher(row)
+	return created, mapErr("create teacher", err)
+}
+
+func (r *TeacherRepo) Update(ctx context.Context, t domain.Teacher) (domain.Teacher, error) {
+	row := r.pool.QueryRow(ctx, `
+		UPDATE teachers
+		SET first_name = $2, last_name = $3, email = $4, phone = $5, updated_at = now()
+		WHERE id = $1
+		RETURNING `+teacherColumns,
+		t.ID, t.FirstName, t.LastName, t.Email, t.Phone,
+	)
+	updated, err := scanTeacher(row)
+	return updated, mapErr("update teacher", err)
+}
+
+func (r *TeacherRepo) Delete(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM teachers WHERE id = $1`, id)
+	if err != nil {
+		return mapErr("delete teacher", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return mapErr("delete teacher", domainerr.ErrNotFound)
+	}
+	return nil
+}
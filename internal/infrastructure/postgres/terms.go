@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TermRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTermRepo(pool *pgxpool.Pool) *TermRepo {
+	return &TermRepo{pool: pool}
+}
+
+const academicYearColumns = `year, name, start_date, end_date, created_at, updated_at`
+
+func scanAcademicYear(row pgx.Row) (domain.AcademicYear, error) {
+	var y domain.AcademicYear
+	err := row.Scan(&y.Year, &y.Name, &y.StartDate, &y.EndDate, &y.CreatedAt, &y.UpdatedAt)
+	return y, err
+}
+
+func (r *TermRepo) ListYears(ctx context.Context) ([]domain.AcademicYear, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+academicYearColumns+` FROM academic_years ORDER BY year DESC`)
+	if err != nil {
+		return nil, mapErr("list academic years", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.AcademicYear, 0)
+	for rows.Next() {
+		y, err := scanAcademicYear(rows)
+		if err != nil {
+			return nil, mapErr("scan academic year", err)
+		}
+		out = append(out, y)
+	}
+
+	return out, mapErr("list academic years", rows.Err())
+}
+
+func (r *TermRepo) GetYear(ctx context.Context, year int) (domain.AcademicYear, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+academicYearColumns+` FROM academic_years WHERE year = $1`, year)
+	y, err := scanAcademicYear(row)
+	return y, mapErr("get academic year", err)
+}
+
+func (r *TermRepo) CreateYear(ctx context.Context, y domain.AcademicYear) (domain.AcademicYear, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO academic_years (year, name, start_date, end_date) VALUES ($1, $2, $3, $4)
+		RETURNING `+academicYearColumns,
+		y.Year, y.Name, y.StartDate, y.EndDate,
+	)
+	created, err := scanAcademicYear(row)
+	return created, mapErr("create academic year", err)
+}
+
+func (r *TermRepo) UpdateYear(ctx context.Context, y domain.AcademicYear) (domain.AcademicYear, error) {
+	row := r.pool.QueryRow(ctx, `
+		UPDATE academic_years SET name = $2, start_date = $3, end_date = $4, updated_at = now()
+		WHERE year = $1
+		RETURNING `+academicYearColumns,
+		y.Year, y.Name, y.StartDate, y.EndDate,
+	)
+	updated, err := scanAcademicYear(row)
+	return updated, mapErr("update academic year", err)
+}
+
+// DeleteYear удаляет учебный год вместе с его периодами.
+func (r *TermRepo) DeleteYear(ctx context.Context, year int) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM academic_years WHERE year = $1`, year)
+	if err != nil {
+		return mapErr("delete academic year", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return mapErr("delete academic year", domainerr.ErrNotFound)
+	}
+	return nil
+}
+
+const termColumns = `id, academic_year, number, name, start_date, end_date, closed_at IS NOT NULL, closed_at, closed_by,
+	created_at, updated_at`
+
+func scanTerm(row pgx.Row) (domain.Term, error) {
+	var t domain.Term
+	err := row.Scan(
+		&t.ID, &t.AcademicYear, &t.Number, &t.Name, &t.StartDate, &t.EndDate,
+		&t.Closed, &t.ClosedAt, &t.ClosedBy, &t.CreatedAt, &t.UpdatedAt,
+	)
+	return t, err
+}
+
+func (r *TermRepo) ListTerms(ctx context.Context, year int) ([]domain.Term, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+termColumns+` FROM terms WHERE academic_year = $1 ORDER BY number`, year)
+	if err != nil {
+		return nil, mapErr("list terms", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.Term, 0)
+	for rows.Next() {
+		t, err := scanTerm(rows)
+		if err != nil {
+			return nil, mapErr("scan term", err)
+		}
+		out = append(out, t)
+	}
+
+	return out, mapErr("list terms", rows.Err())
+}
+
+func (r *TermRepo) GetTerm(ctx context.Context, id int64) (domain.Term, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+termColumns+` FROM terms WHERE id = $1`, id)
+	t, err := scanTerm(row)
+	return t, mapErr("get term", err)
+}
+
+// TermByNumber — период учебного года по номеру (как у работ в журнале).
+func (r *TermRepo) TermByNumber(ctx context.Context, year, number int) (domain.Term, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+termColumns+` FROM terms WHERE academic_year = $1 AND number = $2`, year, number)
+	t, err := scanTerm(row)
+	return t, mapErr("get term by number", err)
+}
+
+// TermOn — период, на который приходится дата d. Между периодами (каникулы) — ErrNotFound.
+func (r *TermRepo) TermOn(ctx context.Context, d time.Time) (domain.Term, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+termColumns+` FROM terms WHERE $1::DATE BETWEEN start_date AND end_date`, d)
+	t, err := scanTerm(row)
+	return t, mapErr("get term on date", err)
+}
+
+func (r *TermRepo) CreateTerm(ctx context.Context, t domain.Term) (domain.Term, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO terms (academic_year, number, name, start_date, end_date) VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+termColumns,
+		t.AcademicYear, t.Number, t.Name, t.StartDate, t.EndDate,
+	)
+	created, err := scanTerm(row)
+	return created, mapErr("create term", err)
+}
+
+// UpdateTerm меняет название и даты. Год, номер и признак закрытия не меняются.
+func (r *TermRepo) UpdateTerm(ctx context.Context, t domain.Term) (domain.Term, error) {
+	row := r.pool.QueryRow(ctx, `
+		UPDATE terms SET name = $2, start_date = $3, end_date = $4, updated_at = now()
+		WHERE id = $1
+		RETURNING `+termColumns,
+		t.ID, t.Name, t.StartDate, t.EndDate,
+	)
+	updated, err := scanTerm(row)
+	return updated, mapErr("update term", err)
+}
+
+func (r *TermRepo) DeleteTerm(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM terms WHERE id = $1`, id)
+	if err != nil {
+		return mapErr("delete term", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return mapErr("delete term", domainerr.ErrNotFound)
+	}
+	return nil
+}
+
+// Close закрывает период; повторное закрытие не меняет, кто и когда его закрыл.
+func (r *TermRepo) Close(ctx context.Context, id int64, closedBy *int64) (domain.Term, error) {
+	row := r.pool.QueryRow(ctx, `
+		UPDATE terms
+		SET closed_at = COALESCE(closed_at, now()), closed_by = CASE WHEN closed_at IS NULL THEN $2 ELSE closed_by END,
+		    updated_at = now()
+		WHERE id = $1
+		RETURNING `+termColumns,
+		id, closedBy,
+	)
+	t, err := scanTerm(row)
+	return t, mapErr("close term", err)
+}
+
+func (r *TermRepo) Reopen(ctx context.Context, id int64) (domain.Term, error) {
+	row := r.pool.QueryRow(ctx, `
+		UPDATE terms SET closed_at = NULL, closed_by = NULL, updated_at = now()
+		WHERE id = $1
+		RETURNING `+termColumns,
+		id,
+	)
+	t, err := scanTerm(row)
+	return t, mapErr("reopen term", err)
+}
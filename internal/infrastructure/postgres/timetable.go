@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TimetableRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTimetableRepo(pool *pgxpool.Pool) *TimetableRepo {
+	return &TimetableRepo{pool: pool}
+}
+
+// slotSelect — урок с именами класса, предмета, учителя и кодом кабинета; источник строк — t.
+const slotSelect = `
+	SELECT t.id, t.class_id, t.subject_id, t.teacher_id, t.room_id, t.day, t.period,
+	       t.effective_from, t.effective_to, t.created_at, t.updated_at,
+	       c.name, sb.name, tc.last_name || ' ' || tc.first_name, COALESCE(rm.code, '')
+	FROM t
+	JOIN classes c     ON c.id = t.class_id
+	JOIN subjects sb   ON sb.id = t.subject_id
+	JOIN teachers tc   ON tc.id = t.teacher_id
+	LEFT JOIN rooms rm ON rm.id = t.room_id`
+
+func scanSlot(row pgx.Row) (domain.TimetableSlot, error) {
+	var s domain.TimetableSlot
+	err := row.Scan(
+		&s.ID, &s.ClassID, &s.SubjectID, &s.TeacherID, &s.RoomID, &s.Day, &s.Period,
+		&s.EffectiveFrom, &s.EffectiveTo, &s.CreatedAt, &s.UpdatedAt,
+		&s.ClassName, &s.SubjectName, &s.TeacherName, &s.RoomCode,
+	)
+	return s, err
+}
+
+func (r *TimetableRepo) list(ctx context.Context, op, where string, args ...any) ([]domain.TimetableSlot, error) {
+	rows, err := r.pool.Query(ctx,
+		`WITH t AS (SELECT * FROM timetable_slots WHERE `+where+`)`+slotSelect+
+			` ORDER BY t.day, t.period, t.effective_from, c.name`, args...)
+	if err != nil {
+		return nil, mapErr(op, err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.TimetableSlot, 0)
+	for rows.Next() {
+		s, err := scanSlot(rows)
+		if err != nil {
+			return nil, mapErr(op, err)
+		}
+		out = append(out, s)
+	}
+
+	return out, mapErr(op, rows.Err())
+}
+
+// periodWhere — урок действует хотя бы один день из периода $2..$3 (NULL — граница не задана).
+const periodWhere = ` AND ($2::DATE IS NULL OR effective_to IS NULL OR effective_to >= $2)
+	AND ($3::DATE IS NULL OR effective_from <= $3)`
+
+func (r *TimetableRepo) ListByClass(ctx context.Context, classID int64, f domain.TimetableFilter) ([]domain.TimetableSlot, error) {
+	return r.list(ctx, "list class timetable", `class_id = $1`+periodWhere, classID, nullDate(f.From), nullDate(f.To))
+}
+
+func (r *TimetableRepo) ListByTeacher(ctx context.Context, teacherID int64, f domain.TimetableFilter) ([]domain.TimetableSlot, error) {
+	return r.list(ctx, "list teacher timetable", `teacher_id = $1`+periodWhere, teacherID, nullDate(f.From), nullDate(f.To))
+}
+
+func (r *TimetableRepo) ListByRoom(ctx context.Context, roomID int64, f domain.TimetableFilter) ([]domain.TimetableSlot, error) {
+	return r.list(ctx, "list room timetable", `room_id = $1`+periodWhere, roomID, nullDate(f.From), nullDate(f.To))
+}
+
+// Clashes — уроки в тот же день и час с пересекающимся периодом действия, у которых совпадает
+// класс, учитель или кабинет. Сам s (по ID) не учитывается.
+func (r *TimetableRepo) Clashes(ctx context.Context, s domain.TimetableSlot) ([]domain.TimetableSlot, error) {
+	return r.list(ctx, "find timetable clashes", `
+		id <> $1 AND day = $2 AND period = $3
+		AND daterange(effective_from, effective_to, '[]') && daterange($4::DATE, $5::DATE, '[]')
+		AND (class_id = $6 OR teacher_id = $7 OR room_id = $8)`,
+		s.ID, s.Day, s.Period, s.EffectiveFrom, s.EffectiveTo, s.ClassID, s.TeacherID, s.RoomID,
+	)
+}
+
+func (r *TimetableRepo) Get(ctx context.Context, id int64) (domain.TimetableSlot, error) {
+	row := r.pool.QueryRow(ctx, `WITH t AS (SELECT * FROM timetable_slots WHERE id = $1)`+slotSelect, id)
+	s, err := scanSlot(row)
+	return s, mapErr("get timetable slot", err)
+}
+
+// Create вставляет урок. Пересечение, проскочившее проверку сервиса (гонка), отсекает
+// EXCLUDE-ограничение — ErrConflict.
+func (r *TimetableRepo) Create(ctx context.Context, s domain.TimetableSlot) (domain.TimetableSlot, error) {
+	row := r.pool.QueryRow(ctx, `
+		WITH t AS (
+			INSERT INTO timetable_slots (class_id, subject_id, teacher_id, room_id, day, period, effective_from, effective_to)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING *
+		)`+slotSelect,
+		s.ClassID, s.SubjectID, s.TeacherID, s.RoomID, s.Day, s.Period, s.EffectiveFrom, s.EffectiveTo,
+	)
+	created, err := scanSlot(row)
+	return created, mapErr("create timetable slot", err)
+}
+
+func (r *TimetableRepo) Update(ctx context.Context, s domain.TimetableSlot) (domain.TimetableSlot, error) {
+	row := r.pool.QueryRow(ctx, `
+		WITH t AS (
+			UPDATE timetable_slots
+			SET class_id = $2, subject_id = $3, teacher_id = $4, room_id = $5, day = $6, period = $7,
+			    effective_from = $8, effective_to = $9, updated_at = now()
+			WHERE id = $1
+			RETURNING *
+		)`+slotSelect,
+		s.ID, s.ClassID, s.SubjectID, s.TeacherID, s.RoomID, s.Day, s.Period, s.EffectiveFrom, s.EffectiveTo,
+	)
+	updated, err := scanSlot(row)
+	return updated, mapErr("update timetable slot", err)
+}
+
+func (r *TimetableRepo) Delete(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM timetable_slots WHERE id = $1`, id)
+	if err != nil {
+		return mapErr("delete timetable slot", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return mapErr("delete timetable slot", domainerr.ErrNotFound)
+	}
+	return nil
+}
+
+// ListAll — все уроки, действующие в период f (используется генератором как занятость).
+func (r *TimetableRepo) ListAll(ctx context.Context, f domain.TimetableFilter) ([]domain.TimetableSlot, error) {
+	return r.list(ctx, "list timetable", `
+		($1::DATE IS NULL OR effective_to IS NULL OR effective_to >= $1) AND ($2::DATE IS NULL OR effective_from <= $2)`,
+		nullDate(f.From), nullDate(f.To),
+	)
+}
+
+// --- Доступность учителей ---
+
+// ListUnavailability — занятые часы учителей из списка.
+func (r *TimetableRepo) ListUnavailability(ctx context.Context, teacherIDs []int64) (map[int64][]domain.LessonTime, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT teacher_id, day, period FROM teacher_unavailability
+		WHERE teacher_id = ANY ($1)
+		ORDER BY teacher_id, day, period`,
+		teacherIDs,
+	)
+	if err != nil {
+		return nil, mapErr("list teacher unavailability", err)
+	}
+	defer rows.Close()
+
+	out := make(map[int64][]domain.LessonTime)
+	for rows.Next() {
+		var (
+			id int64
+			lt domain.LessonTime
+		)
+		if err := rows.Scan(&id, &lt.Day, &lt.Period); err != nil {
+			return nil, mapErr("scan teacher unavailability", err)
+		}
+		out[id] = append(out[id], lt)
+	}
+
+	return out, mapErr("list teacher unavailability", rows.Err())
+}
+
+// SetUnavailability заменяет занятые часы учителя целиком.
+func (r *TimetableRepo) SetUnavailability(ctx context.Context, teacherID int64, times []domain.LessonTime) error {
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM teacher_unavailability WHERE teacher_id = $1`, teacherID); err != nil {
+			return err
+		}
+
+		rows := make([][]any, 0, len(times))
+		for _, t := range times {
+			rows = append(rows, []any{teacherID, t.Day, t.Period})
+		}
+		_, err := tx.CopyFrom(ctx, pgx.Identifier{"teacher_unavailability"},
+			[]string{"teacher_id", "day", "period"}, pgx.CopyFromRows(rows))
+		return err
+	})
+	return mapErr("set teacher unavailability", err)
+}
@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TimetableDraftRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTimetableDraftRepo(pool *pgxpool.Pool) *TimetableDraftRepo {
+	return &TimetableDraftRepo{pool: pool}
+}
+
+const draftColumns = `id, academic_year, class_ids, effective_from, days, periods, time_limit,
+	status, progress, message, created_by, created_at, started_at, finished_at, published_at`
+
+func scanDraft(row pgx.Row) (domain.TimetableDraft, error) {
+	var d domain.TimetableDraft
+	err := row.Scan(&d.ID, &d.AcademicYear, &d.ClassIDs, &d.EffectiveFrom, &d.Days, &d.Periods, &d.TimeLimit,
+		&d.Status, &d.Progress, &d.Message, &d.CreatedBy, &d.CreatedAt, &d.StartedAt, &d.FinishedAt, &d.PublishedAt)
+	return d, err
+}
+
+func (r *TimetableDraftRepo) Create(ctx context.Context, d domain.TimetableDraft) (domain.TimetableDraft, error) {
+	row := r.pool.QueryRow(ctx, `
+		INSERT INTO timetable_drafts (academic_year, class_ids, effective_from, days, periods, time_limit, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+draftColumns,
+		d.AcademicYear, d.ClassIDs, d.EffectiveFrom, d.Days, d.Periods, d.TimeLimit, d.CreatedBy,
+	)
+	created, err := scanDraft(row)
+	return created, mapErr("create timetable draft", err)
+}
+
+func (r *TimetableDraftRepo) Get(ctx context.Context, id int64) (domain.TimetableDraft, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+draftColumns+` FROM timetable_drafts WHERE id = $1`, id)
+	d, err := scanDraft(row)
+	return d, mapErr("get timetable draft", err)
+}
+
+func (r *TimetableDraftRepo) List(ctx context.Context, limit, offset int) ([]domain.TimetableDraft, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+draftColumns+` FROM timetable_drafts
+		ORDER BY id DESC LIMIT $1 OFFSET $2`,
+		limit, offset,
+	)
+	if err != nil {
+		return nil, mapErr("list timetable drafts", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.TimetableDraft, 0)
+	for rows.Next() {
+		d, err := scanDraft(rows)
+		if err != nil {
+			return nil, mapErr("scan timetable draft", err)
+		}
+		out = append(out, d)
+	}
+
+	return out, mapErr("list timetable drafts", rows.Err())
+}
+
+// Start переводит черновик из queued в running.
+func (r *TimetableDraftRepo) Start(ctx context.Context, id int64) (domain.TimetableDraft, error) {
+	row := r.pool.QueryRow(ctx, `
+		UPDATE timetable_drafts SET status = 'running', started_at = now()
+		WHERE id = $1 AND status = 'queued'
+		RETURNING `+draftColumns, id)
+	d, err := scanDraft(row)
+	return d, mapErr("start timetable draft", err)
+}
+
+func (r *TimetableDraftRepo) SetProgress(ctx context.Context, id int64, progress int) error {
+	_, err := r.pool.Exec(ctx,
+		`UPDATE timetable_drafts SET progress = $2 WHERE id = $1 AND status = 'running'`, id, progress)
+	return mapErr("set timetable draft progress", err)
+}
+
+// Finish сохраняет найденные уроки и переводит черновик в ready.
+func (r *TimetableDraftRepo) Finish(ctx context.Context, id int64, slots []domain.TimetableSlot, message string) error {
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		rows := make([][]any, 0, len(slots))
+		for _, s := range slots {
+			rows = append(rows, []any{id, s.ClassID, s.SubjectID, s.TeacherID, s.RoomID, s.Day, s.Period})
+		}
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{"timetable_draft_slots"},
+			[]string{"draft_id", "class_id", "subject_id", "teacher_id", "room_id", "day", "period"},
+			pgx.CopyFromRows(rows)); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(ctx, `
+			UPDATE timetable_drafts SET status = 'ready', progress = 100, message = $2, finished_at = now()
+			WHERE id = $1`, id, message)
+		return err
+	})
+	return mapErr("finish timetable draft", err)
+}
+
+func (r *TimetableDraftRepo) Fail(ctx context.Context, id int64, message string) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE timetable_drafts SET status = 'failed', message = $2, finished_at = now()
+		WHERE id = $1 AND status IN ('queued', 'running')`, id, message)
+	return mapErr("fail timetable draft", err)
+}
+
+// FailInterrupted помечает failed задачи, оставшиеся queued/running после остановки процесса.
+func (r *TimetableDraftRepo) FailInterrupted(ctx context.Context) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE timetable_drafts SET status = 'failed', message = 'interrupted by server restart', finished_at = now()
+		WHERE status IN ('queued', 'running')`)
+	if err != nil {
+		return 0, mapErr("fail interrupted timetable drafts", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// Slots — уроки черновика; classID == 0 — по всем классам.
+func (r *TimetableDraftRepo) Slots(ctx context.Context, id, classID int64) ([]domain.TimetableSlot, error) {
+	rows, err := r.pool.Query(ctx, `
+		WITH t AS (
+			SELECT ds.id, ds.class_id, ds.subject_id, ds.teacher_id, ds.room_id, ds.day, ds.period,
+			       d.effective_from, NULL::DATE AS effective_to, d.created_at, d.created_at AS updated_at
+			FROM timetable_draft_slots ds
+			JOIN timetable_drafts d ON d.id = ds.draft_id
+			WHERE ds.draft_id = $1 AND ($2 = 0 OR ds.class_id = $2)
+		)`+slotSelect+`
+		ORDER BY c.name, t.day, t.period`,
+		id, classID,
+	)
+	if err != nil {
+		return nil, mapErr("list timetable draft slots", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.TimetableSlot, 0)
+	for rows.Next() {
+		s, err := scanSlot(rows)
+		if err != nil {
+			return nil, mapErr("scan timetable draft slot", err)
+		}
+		out = append(out, s)
+	}
+
+	return out, mapErr("list timetable draft slots", rows.Err())
+}
+
+// Publish в одной транзакции заменяет расписание классов черновика с даты effective_from:
+// действующие уроки закрываются накануне, более поздние удаляются, уроки черновика вставляются.
+// Пересечение с расписанием других классов отсекает EXCLUDE-ограничение (ErrConflict).
+func (r *TimetableDraftRepo) Publish(ctx context.Context, id int64) (domain.TimetableDraft, error) {
+	var d domain.TimetableDraft
+
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		var err error
+		d, err = scanDraft(tx.QueryRow(ctx, `SELECT `+draftColumns+` FROM timetable_drafts WHERE id = $1 FOR UPDATE`, id))
+		if err != nil {
+			return err
+		}
+		if d.Status != domain.DraftReady {
+			return domainerr.Newf(domainerr.ErrConflict, "draft is %s, only ready drafts can be published", d.Status)
+		}
+
+		dayBefore := d.EffectiveFrom.AddDate(0, 0, -1)
+		if _, err := tx.Exec(ctx, `
+			DELETE FROM timetable_slots WHERE class_id = ANY ($1) AND effective_from >= $2`,
+			d.ClassIDs, d.EffectiveFrom); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE timetable_slots SET effective_to = $3, updated_at = now()
+			WHERE class_id = ANY ($1) AND (effective_to IS NULL OR effective_to >= $2)`,
+			d.ClassIDs, d.EffectiveFrom, dayBefore); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO timetable_slots (class_id, subject_id, teacher_id, room_id, day, period, effective_from)
+			SELECT class_id, subject_id, teacher_id, room_id, day, period, $2
+			FROM timetable_draft_slots WHERE draft_id = $1`,
+			id, d.EffectiveFrom); err != nil {
+			return err
+		}
+
+		d, err = scanDraft(tx.QueryRow(ctx, `
+			UPDATE timetable_drafts SET status = 'published', published_at = $2
+			WHERE id = $1
+			RETURNING `+draftColumns, id, time.Now()))
+		return err
+	})
+
+	return d, mapErr("publish timetable draft", err)
+}
+
+// Delete удаляет черновик, если генерация по нему не идёт.
+func (r *TimetableDraftRepo) Delete(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM timetable_drafts WHERE id = $1 AND status <> 'running'`, id)
+	if err != nil {
+		return mapErr("delete timetable draft", err)
+	}
+	if tag.RowsAffected() == 0 {
+		if _, err := r.Get(ctx, id); err != nil {
+			return err
+		}
+		return mapErr("delete timetable draft", domainerr.Newf(domainerr.ErrConflict, "generation is running"))
+	}
+	return nil
+}
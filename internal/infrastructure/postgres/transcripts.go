@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"restapi/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TranscriptRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTranscriptRepo(pool *pgxpool.Pool) *TranscriptRepo {
+	return &TranscriptRepo{pool: pool}
+}
+
+// Courses — средние ученика по предметам за каждый учебный год (все периоды года), с зачётными
+// единицами предмета и классом, в котором ставились оценки. Оценка по шкале не заполняется.
+func (r *TranscriptRepo) Courses(ctx context.Context, studentID int64) ([]domain.TranscriptCourse, error) {
+	rows, err := r.pool.Query(ctx, `
+		WITH avgs AS (
+			SELECT c.academic_year, a.subject_id, MIN(c.name) AS class_name,
+			       SUM(g.score / a.max_score * gc.weight) / SUM(gc.weight) * 100 AS avg,
+			       COUNT(*) AS cnt
+			FROM grades g
+			JOIN assessments a       ON a.id = g.assessment_id
+			JOIN classes c           ON c.id = a.class_id
+			JOIN grade_categories gc ON gc.id = a.category_id
+			WHERE g.student_id = $1
+			GROUP BY c.academic_year, a.subject_id
+		)
+		SELECT avgs.academic_year, avgs.class_name, avgs.subject_id, sb.name, sb.credits::FLOAT8,
+		       ROUND(avgs.avg, 2)::FLOAT8, avgs.cnt::INT
+		FROM avgs JOIN subjects sb ON sb.id = avgs.subject_id
+		ORDER BY avgs.academic_year, sb.name`,
+		studentID,
+	)
+	if err != nil {
+		return nil, mapErr("transcript courses", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.TranscriptCourse, 0)
+	for rows.Next() {
+		var c domain.TranscriptCourse
+		if err := rows.Scan(&c.AcademicYear, &c.ClassName, &c.SubjectID, &c.SubjectName, &c.Credits, &c.Percent, &c.GradesCount); err != nil {
+			return nil, mapErr("scan transcript course", err)
+		}
+		out = append(out, c)
+	}
+
+	return out, mapErr("transcript courses", rows.Err())
+}
+
+// Issue сохраняет выданную выписку как есть.
+func (r *TranscriptRepo) Issue(ctx context.Context, t domain.Transcript) error {
+	doc, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("issue transcript: %w", err)
+	}
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO transcripts (hash, student_id, scale_code, gpa, total_credits, document, issued_by, issued_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		t.Hash, t.StudentID, t.Scale.Code, t.GPA, t.TotalCredits, doc, t.IssuedBy, t.IssuedAt,
+	)
+	return mapErr("issue transcript", err)
+}
+
+// ListIssued — выданные ученику выписки, новые первыми.
+func (r *TranscriptRepo) ListIssued(ctx context.Context, studentID int64) ([]domain.IssuedTranscript, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT hash, student_id, scale_code, COALESCE(gpa, 0)::FLOAT8, total_credits::FLOAT8, issued_by, issued_at
+		FROM transcripts WHERE student_id = $1
+		ORDER BY issued_at DESC`,
+		studentID,
+	)
+	if err != nil {
+		return nil, mapErr("list transcripts", err)
+	}
+	defer rows.Close()
+
+	out := make([]domain.IssuedTranscript, 0)
+	for rows.Next() {
+		var t domain.IssuedTranscript
+		if err := rows.Scan(&t.Hash, &t.StudentID, &t.Scale, &t.GPA, &t.TotalCredits, &t.IssuedBy, &t.IssuedAt); err != nil {
+			return nil, mapErr("scan transcript", err)
+		}
+		out = append(out, t)
+	}
+
+	return out, mapErr("list transcripts", rows.Err())
+}
+
+// Get — выданная выписка по хешу в том виде, в каком её сохранили.
+func (r *TranscriptRepo) Get(ctx context.Context, hash string) (domain.Transcript, error) {
+	var doc []byte
+	if err := r.pool.QueryRow(ctx, `SELECT document FROM transcripts WHERE hash = $1`, hash).Scan(&doc); err != nil {
+		return domain.Transcript{}, mapErr("get transcript", err)
+	}
+	var t domain.Transcript
+	if err := json.Unmarshal(doc, &t); err != nil {
+		return domain.Transcript{}, fmt.Errorf("get transcript: %w", err)
+	}
+	return t, nil
+}
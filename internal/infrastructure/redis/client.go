@@ -0,0 +1,35 @@
+// Package redis — подключение к Redis и построенные на нём хранилища (лимиты запросов).
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"restapi/internal/config"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// NewClient создаёт клиента без обращения к серверу: соединения открываются по первому запросу
+// и восстанавливаются сами, поэтому клиент пригоден и при недоступном Redis. Доступность проверяет Ping.
+func NewClient(cfg *config.Redis) *goredis.Client {
+	return goredis.NewClient(&goredis.Options{
+		Addr:         cfg.Addr,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+	})
+}
+
+// Ping проверяет, что Redis отвечает, за время HealthTimeout.
+func Ping(ctx context.Context, client *goredis.Client, cfg *config.Redis) error {
+	pingCtx, cancel := context.WithTimeout(ctx, cfg.HealthTimeout)
+	defer cancel()
+
+	if err := client.Ping(pingCtx).Err(); err != nil {
+		return fmt.Errorf("ping redis: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"restapi/internal/config"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestClientReconnects(t *testing.T) {
+	mr := miniredis.RunT(t)
+	addr := mr.Addr()
+	mr.Close()
+
+	cfg := &config.Redis{Addr: addr, DialTimeout: time.Second, ReadTimeout: time.Second, WriteTimeout: time.Second, HealthTimeout: time.Second}
+	client := NewClient(cfg)
+	t.Cleanup(func() { _ = client.Close() })
+
+	if err := Ping(context.Background(), client, cfg); err == nil {
+		t.Fatal("want error while redis is down")
+	}
+
+	// Клиент, созданный при недоступном Redis, начинает работать, когда сервер поднимается.
+	if err := mr.StartAddr(addr); err != nil {
+		t.Fatal(err)
+	}
+	if err := Ping(context.Background(), client, cfg); err != nil {
+		t.Fatalf("after restart: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"restapi/internal/transport/http/middlewares"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// gcraScript — GCRA (generic cell rate algorithm): вместо счётчика хранится TAT — момент, когда
+// корзина клиента снова станет полной. Запрос проходит, если после него TAT опережает «сейчас» не
+// больше чем на burst интервалов. Время берётся у Redis, чтобы часы реплик API не влияли на квоту.
+//
+// KEYS[1] — ключ клиента; ARGV[1] — интервал между запросами в мкс, ARGV[2] — burst.
+// Возвращает {разрешён (1/0), осталось запросов, мкс до следующего разрешённого, мкс до полной корзины}.
+const gcraScript = `
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then
+	tat = now
+end
+local new_tat = tat + interval
+local allow_at = new_tat - burst * interval
+if allow_at > now then
+	return {0, 0, allow_at - now, tat - now}
+end
+
+redis.call('SET', KEYS[1], string.format('%d', new_tat), 'PX', math.ceil((new_tat - now) / 1000))
+return {1, math.floor((now - allow_at) / interval), 0, new_tat - now}
+`
+
+// RateLimitStore — лимиты запросов в Redis, общие для всех реплик API.
+type RateLimitStore struct {
+	client goredis.Scripter
+	prefix string
+	script *goredis.Script
+}
+
+var _ middlewares.LimitStore = (*RateLimitStore)(nil)
+
+// NewRateLimitStore — ключи клиентов получают префикс prefix + ":ratelimit:".
+func NewRateLimitStore(client goredis.Scripter, prefix string) *RateLimitStore {
+	return &RateLimitStore{client: client, prefix: prefix + ":ratelimit:", script: goredis.NewScript(gcraScript)}
+}
+
+func (s *RateLimitStore) Allow(ctx context.Context, key string, l middlewares.Limit) (middlewares.Decision, error) {
+	interval := int64(math.Ceil(float64(time.Second/time.Microsecond) / float64(l.Rate)))
+	res, err := s.script.Run(ctx, s.client, []string{s.prefix + key}, interval, l.Burst).Int64Slice()
+	if err != nil {
+		return middlewares.Decision{}, fmt.Errorf("redis rate limit: %w", err)
+	}
+	if len(res) != 4 {
+		return middlewares.Decision{}, fmt.Errorf("redis rate limit: unexpected reply %v", res)
+	}
+	return middlewares.Decision{
+		Allowed:    res[0] == 1,
+		Remaining:  int(res[1]),
+		RetryAfter: time.Duration(res[2]) * time.Microsecond,
+		Reset:      time.Duration(res[3]) * time.Microsecond,
+	}, nil
+}
@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"restapi/internal/transport/http/middlewares"
+	"restapi/internal/transport/http/middlewares/limitstoretest"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func newTestStore(t *testing.T) (*RateLimitStore, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	mr.SetTime(time.Date(2026, 9, 1, 8, 0, 0, 0, time.UTC))
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRateLimitStore(client, "test"), mr
+}
+
+// clockedStore переводит часы miniredis перед каждым запросом: GCRA берёт время из TIME.
+type clockedStore struct {
+	*RateLimitStore
+	mr    *miniredis.Miniredis
+	clock *limitstoretest.Clock
+}
+
+func (s clockedStore) Allow(ctx context.Context, key string, l middlewares.Limit) (middlewares.Decision, error) {
+	s.mr.SetTime(s.clock.Now())
+	return s.RateLimitStore.Allow(ctx, key, l)
+}
+
+func TestRateLimitStore(t *testing.T) {
+	limitstoretest.Run(t, func(t *testing.T, clock *limitstoretest.Clock) middlewares.LimitStore {
+		s, mr := newTestStore(t)
+		return clockedStore{RateLimitStore: s, mr: mr, clock: clock}
+	})
+}
+
+func TestRateLimitStoreKeys(t *testing.T) {
+	s, mr := newTestStore(t)
+	l := middlewares.Limit{Rate: 1, Burst: 1}
+
+	if _, err := s.Allow(context.Background(), "a", l); err != nil {
+		t.Fatal(err)
+	}
+
+	if !mr.Exists("test:ratelimit:a") {
+		t.Errorf("keys = %v, want test:ratelimit:a", mr.Keys())
+	}
+	// Ключ живёт, пока корзина не наполнится: иначе Redis копил бы ключи всех клиентов.
+	if ttl := mr.TTL("test:ratelimit:a"); ttl <= 0 || ttl > time.Second {
+		t.Errorf("ttl = %v, want (0, 1s]", ttl)
+	}
+}
+
+func TestRateLimitStoreUnavailable(t *testing.T) {
+	s, mr := newTestStore(t)
+	mr.Close()
+
+	if _, err := s.Allow(context.Background(), "k", middlewares.Limit{Rate: 1, Burst: 1}); err == nil {
+		t.Fatal("want error when redis is down")
+	}
+}
@@ -0,0 +1,187 @@
+// Package storage — хранилища содержимого загруженных файлов: локальный диск и S3-совместимое.
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	domainerr "restapi/internal/domain/errors"
+)
+
+// LocalConfig — параметры локального хранилища.
+type LocalConfig struct {
+	Root       string
+	SigningKey []byte        // HMAC-ключ подписанных ссылок
+	BaseURL    string        // адрес API перед /files/…; пусто — относительные ссылки
+	URLTTL     time.Duration // срок действия ссылки
+}
+
+// LocalPathPrefix — путь API, по которому локальное хранилище отдаёт файлы по подписанным ссылкам.
+const LocalPathPrefix = "/files/"
+
+// Local хранит файлы в каталоге на диске; ключ — относительный путь со слешами.
+// Файлы отдаёт API по ссылкам с HMAC-подписью и сроком действия (см. Verify).
+type Local struct {
+	cfg LocalConfig
+	now func() time.Time
+}
+
+// NewLocal создаёт корневой каталог, если его нет.
+func NewLocal(cfg LocalConfig) (*Local, error) {
+	if len(cfg.SigningKey) == 0 {
+		return nil, errors.New("storage: signing key is required")
+	}
+	if cfg.URLTTL <= 0 {
+		return nil, errors.New("storage: link TTL must be > 0")
+	}
+	if err := os.MkdirAll(cfg.Root, 0o750); err != nil {
+		return nil, fmt.Errorf("storage: create %s: %w", cfg.Root, err)
+	}
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	return &Local{cfg: cfg, now: time.Now}, nil
+}
+
+// Put записывает ровно size байт под ключом key. Файл пишется во временный и переименовывается:
+// читатель не увидит недописанный файл.
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64, _ string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("storage: put %s: %w", key, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("storage: put %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name()) // после Rename — no-op
+
+	n, err := io.Copy(tmp, io.LimitReader(contextReader{ctx: ctx, r: r}, size+1))
+	if err == nil && n != size {
+		err = fmt.Errorf("got %d bytes, want %d", n, size)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("storage: put %s: %w", key, err)
+	}
+	return nil
+}
+
+// Open открывает файл на чтение; нет файла — ErrNotFound. Возвращаемый файл поддерживает Seek.
+func (l *Local) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("storage: open %s: %w", key, domainerr.ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("storage: open %s: %w", key, err)
+	}
+	return f, nil
+}
+
+// Delete удаляет файл; отсутствие файла ошибкой не считается.
+func (l *Local) Delete(_ context.Context, key string) error {
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("storage: delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// SignedURL — ссылка /files/{key}?exp=&name=&type=&sig= на URLTTL.
+func (l *Local) SignedURL(_ context.Context, key, name, contentType string) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	exp := strconv.FormatInt(l.now().Add(l.cfg.URLTTL).Unix(), 10)
+
+	q := url.Values{}
+	q.Set("exp", exp)
+	q.Set("name", name)
+	q.Set("type", contentType)
+	q.Set("sig", l.sign(key, exp, name, contentType))
+	return l.cfg.BaseURL + LocalPathPrefix + escapePath(key) + "?" + q.Encode(), nil
+}
+
+// Verify проверяет подпись и срок ссылки на key и возвращает имя и тип файла из неё.
+// Поддельная или просроченная ссылка — ErrForbidden.
+func (l *Local) Verify(key string, q url.Values) (name, contentType string, err error) {
+	exp, name, contentType := q.Get("exp"), q.Get("name"), q.Get("type")
+	sig, err := base64.RawURLEncoding.DecodeString(q.Get("sig"))
+	if err != nil || validKey(key) != nil {
+		return "", "", domainerr.Newf(domainerr.ErrForbidden, "invalid file link")
+	}
+	want, _ := base64.RawURLEncoding.DecodeString(l.sign(key, exp, name, contentType))
+	if !hmac.Equal(sig, want) {
+		return "", "", domainerr.Newf(domainerr.ErrForbidden, "invalid file link")
+	}
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || l.now().Unix() > unix {
+		return "", "", domainerr.Newf(domainerr.ErrForbidden, "file link has expired")
+	}
+	return name, contentType, nil
+}
+
+func (l *Local) sign(key, exp, name, contentType string) string {
+	m := hmac.New(sha256.New, l.cfg.SigningKey)
+	m.Write([]byte(key + "\n" + exp + "\n" + name + "\n" + contentType))
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil))
+}
+
+// path переводит ключ в путь внутри корня.
+func (l *Local) path(key string) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(l.cfg.Root, filepath.FromSlash(key)), nil
+}
+
+// validKey отклоняет пустые ключи, абсолютные пути и выход за корень через «..».
+func validKey(key string) error {
+	if key == "" || strings.Contains(key, `\`) || !filepath.IsLocal(filepath.FromSlash(key)) {
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid storage key %q", key)
+	}
+	return nil
+}
+
+// contextReader прерывает копирование при отмене запроса.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
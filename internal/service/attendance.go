@@ -0,0 +1,401 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+// maxRollCall — ограничение на число отметок в одной перекличке.
+const maxRollCall = 200
+
+// Ограничения объяснительных.
+const (
+	maxExcuseText  = 2000 // символов
+	maxExcuseFiles = 5
+)
+
+type AttendanceRepository interface {
+	ListReasons(ctx context.Context) ([]domain.AbsenceReason, error)
+	GetReason(ctx context.Context, id int64) (domain.AbsenceReason, error)
+
+	SaveRollCall(ctx context.Context, rc domain.RollCall, entries []domain.AttendanceEntry, overwrite bool) (domain.RollCallResult, bool, error)
+	ListRollCalls(ctx context.Context, classID int64, date time.Time) ([]domain.RollCallResult, error)
+	ListStudentMarks(ctx context.Context, studentID int64, p domain.DateRange) ([]domain.AttendanceMark, error)
+	StudentSummary(ctx context.Context, studentID int64, p domain.DateRange) (domain.AttendanceSummary, error)
+	ClassSummary(ctx context.Context, classID int64, p domain.DateRange) ([]domain.AttendanceSummary, error)
+
+	CreateExcuse(ctx context.Context, e domain.ExcuseNote) (domain.ExcuseNote, error)
+	ListExcuses(ctx context.Context, studentID int64) ([]domain.ExcuseNote, error)
+	GetExcuse(ctx context.Context, id int64) (domain.ExcuseNote, error)
+	AddExcuseFile(ctx context.Context, excuseID int64, f domain.File) (domain.File, error)
+	ReviewExcuse(ctx context.Context, id int64, status domain.ExcuseStatus, reviewerID int64) (domain.ExcuseNote, error)
+}
+
+type AttendanceService struct {
+	repo     AttendanceRepository
+	classes  ClassRepository
+	students StudentRepository
+	subjects SubjectRepository
+	terms    TermRepository
+	storage  FileStorage
+	policy   UploadPolicy
+	now      func() time.Time
+}
+
+func NewAttendanceService(
+	repo AttendanceRepository, classes ClassRepository, students StudentRepository, subjects SubjectRepository,
+	terms TermRepository, storage FileStorage, policy UploadPolicy,
+) *AttendanceService {
+	return &AttendanceService{
+		repo: repo, classes: classes, students: students, subjects: subjects, terms: terms,
+		storage: storage, policy: policy, now: time.Now,
+	}
+}
+
+func (s *AttendanceService) Reasons(ctx context.Context) ([]domain.AbsenceReason, error) {
+	return s.repo.ListReasons(ctx)
+}
+
+// RecordRollCall сохраняет перекличку класса. Повтор за тот же день и урок — ErrConflict,
+// если не запрошена перезапись (in.Overwrite). created сообщает, создана ли новая перекличка.
+func (s *AttendanceService) RecordRollCall(ctx context.Context, actor domain.Principal, classID int64, in domain.RollCallInput) (domain.RollCallResult, bool, error) {
+	if _, err := s.classes.Get(ctx, classID); err != nil {
+		return domain.RollCallResult{}, false, err
+	}
+
+	var v domainerr.ValidationError
+	if in.Date.IsZero() {
+		v.Add("date", "is required")
+	}
+	if in.Lesson < 0 || in.Lesson > domain.MaxLesson {
+		v.Add("lesson", fmt.Sprintf("must be between 0 (whole day) and %d", domain.MaxLesson))
+	}
+	if in.SubjectID != nil {
+		if err := exists(&v, "subject_id", func() error {
+			_, err := s.subjects.Get(ctx, *in.SubjectID)
+			return err
+		}); err != nil {
+			return domain.RollCallResult{}, false, err
+		}
+	}
+	if err := v.Err(); err != nil {
+		return domain.RollCallResult{}, false, err
+	}
+
+	if err := s.validateMarks(ctx, classID, in.Marks); err != nil {
+		return domain.RollCallResult{}, false, err
+	}
+
+	rc := domain.RollCall{
+		ClassID:   classID,
+		Date:      dateOnly(in.Date),
+		Lesson:    in.Lesson,
+		SubjectID: in.SubjectID,
+	}
+	if actor.ExecID != 0 {
+		rc.TakenBy = &actor.ExecID
+	}
+
+	return s.repo.SaveRollCall(ctx, rc, in.Marks, in.Overwrite)
+}
+
+// ClassDay — переклички класса за день.
+func (s *AttendanceService) ClassDay(ctx context.Context, classID int64, date time.Time) ([]domain.RollCallResult, error) {
+	if date.IsZero() {
+		var v domainerr.ValidationError
+		v.Add("date", "is required")
+		return nil, v.Err()
+	}
+	if _, err := s.classes.Get(ctx, classID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListRollCalls(ctx, classID, dateOnly(date))
+}
+
+// ClassSummary — посещаемость класса за период: по ученикам и итог.
+func (s *AttendanceService) ClassSummary(ctx context.Context, classID int64, p domain.DateRange) (domain.ClassAttendance, error) {
+	if err := validateDateRange(p); err != nil {
+		return domain.ClassAttendance{}, err
+	}
+	if _, err := s.classes.Get(ctx, classID); err != nil {
+		return domain.ClassAttendance{}, err
+	}
+	p, err := s.withCurrentTerm(ctx, p)
+	if err != nil {
+		return domain.ClassAttendance{}, err
+	}
+
+	rows, err := s.repo.ClassSummary(ctx, classID, p)
+	if err != nil {
+		return domain.ClassAttendance{}, err
+	}
+
+	res := domain.ClassAttendance{ClassID: classID, Students: rows}
+	for i := range rows {
+		rows[i].Rate = attendanceRate(rows[i])
+		res.Summary.Present += rows[i].Present
+		res.Summary.Absent += rows[i].Absent
+		res.Summary.Late += rows[i].Late
+		res.Summary.Excused += rows[i].Excused
+		res.Summary.Total += rows[i].Total
+	}
+	res.Summary.Rate = attendanceRate(res.Summary)
+
+	return res, nil
+}
+
+// StudentAttendance — отметки ученика за период и сводка.
+func (s *AttendanceService) StudentAttendance(ctx context.Context, studentID int64, p domain.DateRange) (domain.StudentAttendance, error) {
+	if err := validateDateRange(p); err != nil {
+		return domain.StudentAttendance{}, err
+	}
+	if _, err := s.students.Get(ctx, studentID); err != nil {
+		return domain.StudentAttendance{}, err
+	}
+	p, err := s.withCurrentTerm(ctx, p)
+	if err != nil {
+		return domain.StudentAttendance{}, err
+	}
+
+	marks, err := s.repo.ListStudentMarks(ctx, studentID, p)
+	if err != nil {
+		return domain.StudentAttendance{}, err
+	}
+	sum, err := s.repo.StudentSummary(ctx, studentID, p)
+	if err != nil {
+		return domain.StudentAttendance{}, err
+	}
+	sum.Rate = attendanceRate(sum)
+
+	return domain.StudentAttendance{StudentID: studentID, Marks: marks, Summary: sum}, nil
+}
+
+// SubmitExcuse регистрирует объяснительную за период; она ждёт решения администрации.
+func (s *AttendanceService) SubmitExcuse(ctx context.Context, actor domain.Principal, studentID int64, e domain.ExcuseNote) (domain.ExcuseNote, error) {
+	if _, err := s.students.Get(ctx, studentID); err != nil {
+		return domain.ExcuseNote{}, err
+	}
+
+	e.StudentID = studentID
+	e.DateFrom, e.DateTo = dateOnly(e.DateFrom), dateOnly(e.DateTo)
+	e.Text = strings.TrimSpace(e.Text)
+	e.SubmittedBy = nil
+	if actor.ExecID != 0 {
+		e.SubmittedBy = &actor.ExecID
+	}
+
+	var v domainerr.ValidationError
+	if e.DateFrom.IsZero() {
+		v.Add("date_from", "is required")
+	}
+	if e.DateTo.IsZero() {
+		v.Add("date_to", "is required")
+	}
+	if !e.DateFrom.IsZero() && e.DateTo.Before(e.DateFrom) {
+		v.Add("date_to", "must not be before date_from")
+	}
+	if len(e.Text) > maxExcuseText {
+		v.Add("text", fmt.Sprintf("at most %d characters", maxExcuseText))
+	}
+	if err := exists(&v, "reason_id", func() error {
+		_, err := s.repo.GetReason(ctx, e.ReasonID)
+		return err
+	}); err != nil {
+		return domain.ExcuseNote{}, err
+	}
+	if err := v.Err(); err != nil {
+		return domain.ExcuseNote{}, err
+	}
+
+	return s.repo.CreateExcuse(ctx, e)
+}
+
+func (s *AttendanceService) Excuses(ctx context.Context, studentID int64) ([]domain.ExcuseNote, error) {
+	if _, err := s.students.Get(ctx, studentID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListExcuses(ctx, studentID)
+}
+
+// AddExcuseFiles прикладывает файлы (справки) к объяснительной ученика, пока она ждёт решения.
+func (s *AttendanceService) AddExcuseFiles(ctx context.Context, actor domain.Principal, studentID, excuseID int64, uploads []Upload) ([]domain.File, error) {
+	e, err := s.excuse(ctx, studentID, excuseID)
+	if err != nil {
+		return nil, err
+	}
+	if e.Status != domain.ExcusePending {
+		return nil, domainerr.Newf(domainerr.ErrConflict, "excuse note is already reviewed")
+	}
+	prepared, err := prepareUploads(s.policy, uploads, maxExcuseFiles, len(e.Files))
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := "excuses/" + strconv.FormatInt(e.ID, 10)
+	out := make([]domain.File, 0, len(prepared))
+	for _, u := range prepared {
+		f, err := putUpload(ctx, s.storage, prefix, u)
+		if err != nil {
+			return nil, err
+		}
+		f.UploadedBy = &actor.ExecID
+
+		saved, err := s.repo.AddExcuseFile(ctx, e.ID, f)
+		if err != nil {
+			deleteStored(ctx, s.storage, f.Key)
+			return nil, err
+		}
+		out = append(out, saved)
+	}
+	return out, nil
+}
+
+// ExcuseFileURL — подписанная ссылка на файл объяснительной.
+func (s *AttendanceService) ExcuseFileURL(ctx context.Context, studentID, excuseID, fileID int64) (string, error) {
+	e, err := s.excuse(ctx, studentID, excuseID)
+	if err != nil {
+		return "", err
+	}
+	for _, f := range e.Files {
+		if f.ID == fileID {
+			return s.storage.SignedURL(ctx, f.Key, f.Name, f.ContentType)
+		}
+	}
+	return "", domainerr.Newf(domainerr.ErrNotFound, "excuse file %d", fileID)
+}
+
+// excuse — объяснительная ученика; чужая объяснительная неотличима от несуществующей.
+func (s *AttendanceService) excuse(ctx context.Context, studentID, excuseID int64) (domain.ExcuseNote, error) {
+	if excuseID <= 0 {
+		return domain.ExcuseNote{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	e, err := s.repo.GetExcuse(ctx, excuseID)
+	if err != nil {
+		return domain.ExcuseNote{}, err
+	}
+	if e.StudentID != studentID {
+		return domain.ExcuseNote{}, domainerr.Newf(domainerr.ErrNotFound, "excuse note %d", excuseID)
+	}
+	return e, nil
+}
+
+// ReviewExcuse одобряет или отклоняет объяснительную; одобрение оправдывает пропуски за период.
+func (s *AttendanceService) ReviewExcuse(ctx context.Context, actor domain.Principal, id int64, r domain.ExcuseReview) (domain.ExcuseNote, error) {
+	if id <= 0 {
+		return domain.ExcuseNote{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+
+	status := domain.ExcuseRejected
+	if r.Approve {
+		status = domain.ExcuseApproved
+	}
+	return s.repo.ReviewExcuse(ctx, id, status, actor.ExecID)
+}
+
+func (s *AttendanceService) validateMarks(ctx context.Context, classID int64, marks []domain.AttendanceEntry) error {
+	var v domainerr.ValidationError
+	if len(marks) == 0 {
+		v.Add("marks", "at least one mark is required")
+		return v.Err()
+	}
+	if len(marks) > maxRollCall {
+		v.Add("marks", fmt.Sprintf("at most %d marks per request", maxRollCall))
+		return v.Err()
+	}
+
+	reasons, err := s.repo.ListReasons(ctx)
+	if err != nil {
+		return err
+	}
+	known := make(map[int64]bool, len(reasons))
+	for _, r := range reasons {
+		known[r.ID] = true
+	}
+
+	seen := make(map[int64]bool, len(marks))
+	ids := make([]int64, 0, len(marks))
+	for i := range marks {
+		m := &marks[i]
+		field := "marks[" + strconv.Itoa(i) + "]"
+		m.Note = strings.TrimSpace(m.Note)
+
+		if seen[m.StudentID] {
+			v.Add(field+".student_id", "duplicate student")
+		}
+		seen[m.StudentID] = true
+		ids = append(ids, m.StudentID)
+
+		if !m.Status.Valid() {
+			v.Add(field+".status", "must be one of present, absent, late, excused")
+			continue
+		}
+		switch {
+		case m.Status == domain.AttendancePresent && m.ReasonID != nil:
+			v.Add(field+".reason_id", "must be empty for present")
+		case m.Status == domain.AttendanceExcused && m.ReasonID == nil:
+			v.Add(field+".reason_id", "is required for excused")
+		case m.ReasonID != nil && !known[*m.ReasonID]:
+			v.Add(field+".reason_id", "does not exist")
+		}
+		if m.MinutesLate < 0 || (m.MinutesLate > 0 && m.Status != domain.AttendanceLate) {
+			v.Add(field+".minutes_late", "allowed only for late and must be >= 0")
+		}
+	}
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	outside, err := s.classes.StudentsOutsideClass(ctx, classID, ids)
+	if err != nil {
+		return err
+	}
+	for _, id := range outside {
+		v.Add("marks.student_id", fmt.Sprintf("student %d is not in this class", id))
+	}
+	return v.Err()
+}
+
+// attendanceRate — доля присутствия (present + late) в процентах, два знака после запятой.
+func attendanceRate(s domain.AttendanceSummary) float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return math.Round(float64(s.Present+s.Late)/float64(s.Total)*10000) / 100
+}
+
+// withCurrentTerm подставляет границы текущего периода, если период выборки не задан.
+func (s *AttendanceService) withCurrentTerm(ctx context.Context, p domain.DateRange) (domain.DateRange, error) {
+	if !p.From.IsZero() || !p.To.IsZero() {
+		return p, nil
+	}
+	t, ok, err := termOn(ctx, s.terms, s.now())
+	if err != nil || !ok {
+		return p, err
+	}
+	return t.Range(), nil
+}
+
+func validateDateRange(p domain.DateRange) error {
+	if !p.From.IsZero() && !p.To.IsZero() && p.To.Before(p.From) {
+		var v domainerr.ValidationError
+		v.Add("to", "must not be before from")
+		return v.Err()
+	}
+	return nil
+}
+
+// dateOnly отбрасывает время суток: переклички и периоды считаются по календарным датам.
+func dateOnly(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
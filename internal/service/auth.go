@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+const sessionTokenBytes = 32
+
+type SessionRepository interface {
+	Create(ctx context.Context, s domain.Session) (domain.Session, error)
+	GetByTokenHash(ctx context.Context, hash []byte) (domain.Session, error)
+	Touch(ctx context.Context, id int64, lastSeen, expiresAt time.Time) error
+	Delete(ctx context.Context, id int64) error
+	DeleteByExec(ctx context.Context, execID int64) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// CalendarTokenRepository — секреты персональных ссылок на календарь (хранится sha256).
+type CalendarTokenRepository interface {
+	Save(ctx context.Context, execID int64, hash []byte) error
+	ExecByTokenHash(ctx context.Context, hash []byte) (int64, error)
+	Delete(ctx context.Context, execID int64) error
+}
+
+type AuthService struct {
+	execs          ExecRepository
+	sessions       SessionRepository
+	calendarTokens CalendarTokenRepository
+
+	ttl         time.Duration // idle-таймаут, сдвигается при активности
+	maxLifetime time.Duration // абсолютный предел от момента входа
+	now         func() time.Time
+
+	// dummyHash сравнивается при неизвестном логине, чтобы время ответа не выдавало существование пользователя.
+	dummyHash string
+}
+
+func NewAuthService(execs ExecRepository, sessions SessionRepository, calendarTokens CalendarTokenRepository, ttl, maxLifetime time.Duration) (*AuthService, error) {
+	dummy, err := hashPassword("dummy-password-for-timing")
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthService{
+		execs:          execs,
+		sessions:       sessions,
+		calendarTokens: calendarTokens,
+		ttl:            ttl,
+		maxLifetime:    maxLifetime,
+		now:            time.Now,
+		dummyHash:      dummy,
+	}, nil
+}
+
+// LoginMeta — сведения о клиенте, сохраняемые в сессии.
+type LoginMeta struct {
+	UserAgent string
+	IP        string
+}
+
+// Login проверяет логин/пароль и открывает новую сессию. Возвращает токен (только клиенту) и сессию.
+func (s *AuthService) Login(ctx context.Context, username, password string, meta LoginMeta) (string, domain.Session, error) {
+	if username == "" || password == "" {
+		return "", domain.Session{}, domainerr.Newf(domainerr.ErrBadInput, "username and password are required")
+	}
+
+	e, err := s.execs.GetByUsername(ctx, username)
+	if errors.Is(err, domainerr.ErrNotFound) {
+		_, _ = verifyPassword(password, s.dummyHash)
+		return "", domain.Session{}, domainerr.ErrInvalidAuth
+	}
+	if err != nil {
+		return "", domain.Session{}, err
+	}
+
+	ok, err := verifyPassword(password, e.PasswordHash)
+	if err != nil {
+		return "", domain.Session{}, fmt.Errorf("verify password for exec %d: %w", e.ID, err)
+	}
+	if !ok || !e.Active {
+		return "", domain.Session{}, domainerr.ErrInvalidAuth
+	}
+
+	token, hash, err := newSessionToken()
+	if err != nil {
+		return "", domain.Session{}, err
+	}
+
+	sess, err := s.sessions.Create(ctx, domain.Session{
+		TokenHash: hash,
+		ExecID:    e.ID,
+		UserAgent: meta.UserAgent,
+		IP:        meta.IP,
+		ExpiresAt: s.now().Add(s.ttl),
+	})
+	if err != nil {
+		return "", domain.Session{}, err
+	}
+
+	if err := s.execs.TouchLogin(ctx, e.ID); err != nil {
+		return "", domain.Session{}, err
+	}
+
+	return token, sess, nil
+}
+
+// Logout закрывает сессию по токену. Неизвестный токен — не ошибка (идемпотентно).
+func (s *AuthService) Logout(ctx context.Context, token string) error {
+	sess, err := s.sessions.GetByTokenHash(ctx, hashToken(token))
+	if errors.Is(err, domainerr.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.sessions.Delete(ctx, sess.ID)
+}
+
+// Authenticate проверяет токен и продлевает сессию (sliding renewal).
+// Пишем в БД не на каждый запрос, а когда прошла хотя бы минута или осталось меньше половины TTL.
+func (s *AuthService) Authenticate(ctx context.Context, token string) (domain.Principal, domain.Session, error) {
+	if token == "" {
+		return domain.Principal{}, domain.Session{}, domainerr.ErrUnauthorized
+	}
+
+	sess, err := s.sessions.GetByTokenHash(ctx, hashToken(token))
+	if errors.Is(err, domainerr.ErrNotFound) {
+		return domain.Principal{}, domain.Session{}, domainerr.ErrUnauthorized
+	}
+	if err != nil {
+		return domain.Principal{}, domain.Session{}, err
+	}
+
+	now := s.now()
+	hardLimit := sess.CreatedAt.Add(s.maxLifetime)
+	if !now.Before(sess.ExpiresAt) || !now.Before(hardLimit) {
+		_ = s.sessions.Delete(ctx, sess.ID)
+		return domain.Principal{}, domain.Session{}, domainerr.ErrSessionExpired
+	}
+
+	if now.Sub(sess.LastSeenAt) >= time.Minute || sess.ExpiresAt.Sub(now) < s.ttl/2 {
+		expires := now.Add(s.ttl)
+		if expires.After(hardLimit) {
+			expires = hardLimit
+		}
+		if err := s.sessions.Touch(ctx, sess.ID, now, expires); err != nil {
+			return domain.Principal{}, domain.Session{}, err
+		}
+		sess.LastSeenAt, sess.ExpiresAt = now, expires
+	}
+
+	// Роль читаем при каждом запросе: её смена или деактивация учётки действуют сразу.
+	e, err := s.execs.Get(ctx, sess.ExecID)
+	if errors.Is(err, domainerr.ErrNotFound) {
+		return domain.Principal{}, domain.Session{}, domainerr.ErrUnauthorized
+	}
+	if err != nil {
+		return domain.Principal{}, domain.Session{}, err
+	}
+	if !e.Active {
+		_ = s.sessions.Delete(ctx, sess.ID)
+		return domain.Principal{}, domain.Session{}, domainerr.ErrUnauthorized
+	}
+
+	return principalOf(e, sess.ID), sess, nil
+}
+
+// IssueCalendarToken выдаёт новый секрет для ссылок на календарь; прежний отзывается.
+// Токен возвращается только здесь — в БД хранится его хэш.
+func (s *AuthService) IssueCalendarToken(ctx context.Context, execID int64) (string, error) {
+	token, hash, err := newSessionToken()
+	if err != nil {
+		return "", err
+	}
+	if err := s.calendarTokens.Save(ctx, execID, hash); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *AuthService) RevokeCalendarToken(ctx context.Context, execID int64) error {
+	return s.calendarTokens.Delete(ctx, execID)
+}
+
+// AuthenticateFeedToken проверяет секрет из ссылки на календарь. Права — как у владельца учётки.
+func (s *AuthService) AuthenticateFeedToken(ctx context.Context, token string) (domain.Principal, error) {
+	if token == "" {
+		return domain.Principal{}, domainerr.ErrUnauthorized
+	}
+
+	execID, err := s.calendarTokens.ExecByTokenHash(ctx, hashToken(token))
+	if errors.Is(err, domainerr.ErrNotFound) {
+		return domain.Principal{}, domainerr.ErrUnauthorized
+	}
+	if err != nil {
+		return domain.Principal{}, err
+	}
+
+	e, err := s.execs.Get(ctx, execID)
+	if errors.Is(err, domainerr.ErrNotFound) {
+		return domain.Principal{}, domainerr.ErrUnauthorized
+	}
+	if err != nil {
+		return domain.Principal{}, err
+	}
+	if !e.Active {
+		return domain.Principal{}, domainerr.ErrUnauthorized
+	}
+	return principalOf(e, 0), nil
+}
+
+// PurgeExpired удаляет протухшие сессии (вызывается периодически).
+func (s *AuthService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.sessions.DeleteExpired(ctx, s.now())
+}
+
+func principalOf(e domain.Exec, sessionID int64) domain.Principal {
+	p := domain.Principal{ExecID: e.ID, SessionID: sessionID, Role: e.Role}
+	if e.TeacherID != nil {
+		p.TeacherID = *e.TeacherID
+	}
+	if e.StudentID != nil {
+		p.StudentID = *e.StudentID
+	}
+	if e.GuardianID != nil {
+		p.GuardianID = *e.GuardianID
+	}
+	return p
+}
+
+func newSessionToken() (string, []byte, error) {
+	b := make([]byte, sessionTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, fmt.Errorf("read session token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"restapi/internal/domain"
+)
+
+// calendarLookback — насколько в прошлое календарь показывает уроки, ещё действующие в этот период.
+const calendarLookback = 90 * 24 * time.Hour
+
+// calendarHorizon — на сколько вперёд ищутся каникулы для бессрочных уроков.
+const calendarHorizon = 366 * 24 * time.Hour
+
+// CalendarService превращает расписание в еженедельно повторяющиеся события календаря;
+// занятия, попадающие на каникулы, исключаются.
+type CalendarService struct {
+	timetable TimetableRepository
+	holidays  HolidayRepository
+	classes   ClassRepository
+	students  StudentRepository
+	teachers  TeacherRepository
+
+	bells domain.BellSchedule
+	loc   *time.Location
+	now   func() time.Time
+}
+
+func NewCalendarService(timetable TimetableRepository, holidays HolidayRepository, classes ClassRepository, students StudentRepository, teachers TeacherRepository, bells domain.BellSchedule, loc *time.Location) *CalendarService {
+	return &CalendarService{
+		timetable: timetable,
+		holidays:  holidays,
+		classes:   classes,
+		students:  students,
+		teachers:  teachers,
+		bells:     bells,
+		loc:       loc,
+		now:       time.Now,
+	}
+}
+
+// Location — часовой пояс школы, в котором заданы звонки.
+func (s *CalendarService) Location() *time.Location {
+	return s.loc
+}
+
+func (s *CalendarService) ClassEvents(ctx context.Context, classID int64) ([]domain.CalendarEvent, error) {
+	if _, err := s.classes.Get(ctx, classID); err != nil {
+		return nil, err
+	}
+	slots, err := s.timetable.ListByClass(ctx, classID, s.window())
+	if err != nil {
+		return nil, err
+	}
+	return s.events(ctx, slots)
+}
+
+func (s *CalendarService) TeacherEvents(ctx context.Context, teacherID int64) ([]domain.CalendarEvent, error) {
+	if _, err := s.teachers.Get(ctx, teacherID); err != nil {
+		return nil, err
+	}
+	slots, err := s.timetable.ListByTeacher(ctx, teacherID, s.window())
+	if err != nil {
+		return nil, err
+	}
+	return s.events(ctx, slots)
+}
+
+// StudentEvents — расписание класса ученика; ученик без класса получает пустой календарь.
+func (s *CalendarService) StudentEvents(ctx context.Context, studentID int64) ([]domain.CalendarEvent, error) {
+	st, err := s.students.Get(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if st.ClassID == nil {
+		return make([]domain.CalendarEvent, 0), nil
+	}
+	slots, err := s.timetable.ListByClass(ctx, *st.ClassID, s.window())
+	if err != nil {
+		return nil, err
+	}
+	return s.events(ctx, slots)
+}
+
+// window — уроки, не закончившиеся до начала окна календаря.
+func (s *CalendarService) window() domain.TimetableFilter {
+	return domain.TimetableFilter{From: dateOnly(s.now().Add(-calendarLookback))}
+}
+
+func (s *CalendarService) events(ctx context.Context, slots []domain.TimetableSlot) ([]domain.CalendarEvent, error) {
+	out := make([]domain.CalendarEvent, 0, len(slots))
+	if len(slots) == 0 {
+		return out, nil
+	}
+
+	from := slots[0].EffectiveFrom
+	for _, sl := range slots {
+		if sl.EffectiveFrom.Before(from) {
+			from = sl.EffectiveFrom
+		}
+	}
+	holidays, err := s.holidays.List(ctx, domain.DateRange{From: from, To: dateOnly(s.now().Add(calendarHorizon))})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sl := range slots {
+		out = append(out, s.event(sl, holidays))
+	}
+	return out, nil
+}
+
+// event строит событие урока: первое занятие — первый день недели sl.Day не раньше EffectiveFrom.
+func (s *CalendarService) event(sl domain.TimetableSlot, holidays []domain.Holiday) domain.CalendarEvent {
+	first := sl.EffectiveFrom
+	for isoWeekday(first) != sl.Day {
+		first = first.AddDate(0, 0, 1)
+	}
+
+	start := s.lessonStart(first, sl.Period)
+	ev := domain.CalendarEvent{
+		UID:         "timetable-slot-" + strconv.FormatInt(sl.ID, 10),
+		Summary:     sl.SubjectName + " — " + sl.ClassName,
+		Location:    sl.RoomCode,
+		Description: fmt.Sprintf("Teacher: %s\nPeriod: %d", sl.TeacherName, sl.Period),
+		Start:       start,
+		End:         start.Add(s.bells.Lesson),
+		Updated:     sl.UpdatedAt,
+	}
+
+	last := dateOnly(s.now().Add(calendarHorizon))
+	if sl.EffectiveTo != nil {
+		until := time.Date(sl.EffectiveTo.Year(), sl.EffectiveTo.Month(), sl.EffectiveTo.Day(), 23, 59, 59, 0, s.loc)
+		ev.Until = &until
+		last = *sl.EffectiveTo
+	}
+
+	for _, h := range holidays {
+		for d := h.StartDate; !d.After(h.EndDate) && !d.After(last); d = d.AddDate(0, 0, 1) {
+			if d.Before(first) || isoWeekday(d) != sl.Day {
+				continue
+			}
+			ev.ExDates = append(ev.ExDates, s.lessonStart(d, sl.Period))
+		}
+	}
+	return ev
+}
+
+// lessonStart — начало урока period в календарный день d по часовому поясу школы.
+func (s *CalendarService) lessonStart(d time.Time, period int) time.Time {
+	// Складываем часы и минуты стенного времени, а не длительность: в день перевода часов сутки не 24 ч.
+	offset := s.bells.PeriodStart(period)
+	return time.Date(d.Year(), d.Month(), d.Day(), int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, s.loc)
+}
+
+// isoWeekday — день недели по ISO 8601: 1 — понедельник, 7 — воскресенье.
+func isoWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
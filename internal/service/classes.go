@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+type ClassRepository interface {
+	List(ctx context.Context, f domain.ClassFilter) ([]domain.Class, error)
+	Get(ctx context.Context, id int64) (domain.Class, error)
+	Create(ctx context.Context, c domain.Class) (domain.Class, error)
+	Update(ctx context.Context, c domain.Class) (domain.Class, error)
+	Delete(ctx context.Context, id int64) error
+	StudentsOutsideClass(ctx context.Context, classID int64, ids []int64) ([]int64, error)
+	CountStudents(ctx context.Context, classIDs []int64) (map[int64]int, error)
+}
+
+type AssignmentRepository interface {
+	ListByClass(ctx context.Context, classID int64) ([]domain.Assignment, error)
+	ListByTeacher(ctx context.Context, teacherID int64, academicYear int) ([]domain.Assignment, error)
+	Get(ctx context.Context, classID, subjectID int64) (domain.Assignment, error)
+	Create(ctx context.Context, a domain.Assignment) (domain.Assignment, error)
+	Update(ctx context.Context, a domain.Assignment) (domain.Assignment, error)
+	Delete(ctx context.Context, classID, subjectID int64) error
+	TeachesClass(ctx context.Context, teacherID, classID int64) (bool, error)
+	TeachesStudent(ctx context.Context, teacherID, studentID int64) (bool, error)
+}
+
+type ClassService struct {
+	repo        ClassRepository
+	assignments AssignmentRepository
+	teachers    TeacherRepository
+	subjects    SubjectRepository
+}
+
+func NewClassService(repo ClassRepository, assignments AssignmentRepository, teachers TeacherRepository, subjects SubjectRepository) *ClassService {
+	return &ClassService{repo: repo, assignments: assignments, teachers: teachers, subjects: subjects}
+}
+
+func (s *ClassService) List(ctx context.Context, f domain.ClassFilter) ([]domain.Class, error) {
+	f.Limit, f.Offset = normalizePage(f.Limit, f.Offset)
+	return s.repo.List(ctx, f)
+}
+
+func (s *ClassService) Get(ctx context.Context, id int64) (domain.Class, error) {
+	if id <= 0 {
+		return domain.Class{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Get(ctx, id)
+}
+
+func (s *ClassService) Create(ctx context.Context, c domain.Class) (domain.Class, error) {
+	if c.AcademicYear == 0 {
+		c.AcademicYear = CurrentAcademicYear(time.Now())
+	}
+	c.Name = strings.ToUpper(strings.TrimSpace(c.Name))
+	if err := s.validate(ctx, c); err != nil {
+		return domain.Class{}, err
+	}
+	return s.repo.Create(ctx, c)
+}
+
+// Update — полная замена (PUT).
+func (s *ClassService) Update(ctx context.Context, c domain.Class) (domain.Class, error) {
+	if c.ID <= 0 {
+		return domain.Class{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	c.Name = strings.ToUpper(strings.TrimSpace(c.Name))
+	if err := s.validate(ctx, c); err != nil {
+		return domain.Class{}, err
+	}
+	return s.repo.Update(ctx, c)
+}
+
+func (s *ClassService) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Subjects — предметы класса с назначенными учителями.
+func (s *ClassService) Subjects(ctx context.Context, classID int64) ([]domain.Assignment, error) {
+	if _, err := s.Get(ctx, classID); err != nil {
+		return nil, err
+	}
+	return s.assignments.ListByClass(ctx, classID)
+}
+
+// Assign назначает учителя на предмет в классе. Повторное назначение того же предмета — ErrConflict.
+func (s *ClassService) Assign(ctx context.Context, a domain.Assignment) (domain.Assignment, error) {
+	if err := s.validateAssignment(ctx, a); err != nil {
+		return domain.Assignment{}, err
+	}
+	return s.assignments.Create(ctx, a)
+}
+
+// Reassign меняет учителя или часы у существующего назначения.
+func (s *ClassService) Reassign(ctx context.Context, a domain.Assignment) (domain.Assignment, error) {
+	if err := s.validateAssignment(ctx, a); err != nil {
+		return domain.Assignment{}, err
+	}
+	return s.assignments.Update(ctx, a)
+}
+
+func (s *ClassService) Unassign(ctx context.Context, classID, subjectID int64) error {
+	if classID <= 0 || subjectID <= 0 {
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.assignments.Delete(ctx, classID, subjectID)
+}
+
+// TeacherLoad — назначения учителя за учебный год (0 — текущий).
+func (s *ClassService) TeacherLoad(ctx context.Context, teacherID int64, academicYear int) (domain.TeacherLoad, error) {
+	if _, err := s.teachers.Get(ctx, teacherID); err != nil {
+		return domain.TeacherLoad{}, err
+	}
+	if academicYear == 0 {
+		academicYear = CurrentAcademicYear(time.Now())
+	}
+
+	list, err := s.assignments.ListByTeacher(ctx, teacherID, academicYear)
+	if err != nil {
+		return domain.TeacherLoad{}, err
+	}
+
+	load := domain.TeacherLoad{TeacherID: teacherID, AcademicYear: academicYear, Assignments: list}
+	for _, a := range list {
+		load.TotalHours += a.HoursPerWeek
+	}
+	return load, nil
+}
+
+// TeachesClass — ведёт ли учитель предмет в классе или является классным руководителем.
+func (s *ClassService) TeachesClass(ctx context.Context, teacherID, classID int64) (bool, error) {
+	return s.assignments.TeachesClass(ctx, teacherID, classID)
+}
+
+// TeachesStudent — ведёт ли учитель класс, где учится ученик.
+func (s *ClassService) TeachesStudent(ctx context.Context, teacherID, studentID int64) (bool, error) {
+	return s.assignments.TeachesStudent(ctx, teacherID, studentID)
+}
+
+func (s *ClassService) validate(ctx context.Context, c domain.Class) error {
+	var v domainerr.ValidationError
+	if c.Name == "" {
+		v.Add("name", "is required")
+	}
+	if c.Grade < domain.MinGrade || c.Grade > domain.MaxGrade {
+		v.Add("grade", fmt.Sprintf("must be between %d and %d", domain.MinGrade, domain.MaxGrade))
+	}
+	if c.AcademicYear < 2000 || c.AcademicYear > 2100 {
+		v.Add("academic_year", "must be a start year between 2000 and 2100")
+	}
+	if c.HomeroomTeacherID != nil {
+		if err := exists(&v, "homeroom_teacher_id", func() error {
+			_, err := s.teachers.Get(ctx, *c.HomeroomTeacherID)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+	return v.Err()
+}
+
+func (s *ClassService) validateAssignment(ctx context.Context, a domain.Assignment) error {
+	if _, err := s.Get(ctx, a.ClassID); err != nil {
+		return err
+	}
+
+	var v domainerr.ValidationError
+	if a.HoursPerWeek < 1 || a.HoursPerWeek > 40 {
+		v.Add("hours_per_week", "must be between 1 and 40")
+	}
+	if err := exists(&v, "subject_id", func() error {
+		_, err := s.subjects.Get(ctx, a.SubjectID)
+		return err
+	}); err != nil {
+		return err
+	}
+	if err := exists(&v, "teacher_id", func() error {
+		_, err := s.teachers.Get(ctx, a.TeacherID)
+		return err
+	}); err != nil {
+		return err
+	}
+	return v.Err()
+}
+
+// exists вызывает get и превращает ErrNotFound в ошибку поля field; прочие ошибки возвращает как есть.
+func exists(v *domainerr.ValidationError, field string, get func() error) error {
+	err := get()
+	if errors.Is(err, domainerr.ErrNotFound) || errors.Is(err, domainerr.ErrBadInput) {
+		v.Add(field, "does not exist")
+		return nil
+	}
+	return err
+}
+
+// CurrentAcademicYear — год начала текущего учебного года (учебный год начинается 1 сентября).
+func CurrentAcademicYear(now time.Time) int {
+	if now.Month() >= time.September {
+		return now.Year()
+	}
+	return now.Year() - 1
+}
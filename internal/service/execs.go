@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+type ExecRepository interface {
+	List(ctx context.Context, search string, limit, offset int) ([]domain.Exec, error)
+	Get(ctx context.Context, id int64) (domain.Exec, error)
+	GetByUsername(ctx context.Context, username string) (domain.Exec, error)
+	Count(ctx context.Context) (int, error)
+	Create(ctx context.Context, e domain.Exec) (domain.Exec, error)
+	Update(ctx context.Context, e domain.Exec) (domain.Exec, error)
+	TouchLogin(ctx context.Context, id int64) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type ExecService struct {
+	repo     ExecRepository
+	sessions SessionRepository
+}
+
+func NewExecService(repo ExecRepository, sessions SessionRepository) *ExecService {
+	return &ExecService{repo: repo, sessions: sessions}
+}
+
+func (s *ExecService) List(ctx context.Context, search string, limit, offset int) ([]domain.Exec, error) {
+	limit, offset = normalizePage(limit, offset)
+	return s.repo.List(ctx, search, limit, offset)
+}
+
+func (s *ExecService) Get(ctx context.Context, id int64) (domain.Exec, error) {
+	if id <= 0 {
+		return domain.Exec{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Get(ctx, id)
+}
+
+func (s *ExecService) Create(ctx context.Context, in domain.ExecInput) (domain.Exec, error) {
+	e := domain.Exec{
+		FirstName:  in.FirstName,
+		LastName:   in.LastName,
+		Email:      in.Email,
+		Username:   in.Username,
+		Role:       in.Role,
+		TeacherID:  in.TeacherID,
+		StudentID:  in.StudentID,
+		GuardianID: in.GuardianID,
+		Active:     in.Active == nil || *in.Active,
+	}
+	normalizeExec(&e)
+	if err := validateExec(e); err != nil {
+		return domain.Exec{}, err
+	}
+
+	hash, err := newPasswordHash(in.Password)
+	if err != nil {
+		return domain.Exec{}, err
+	}
+	e.PasswordHash = hash
+
+	return s.repo.Create(ctx, e)
+}
+
+// Patch — частичное обновление. Смена пароля, роли или деактивация завершает все сессии exec.
+func (s *ExecService) Patch(ctx context.Context, id int64, p domain.ExecPatch) (domain.Exec, error) {
+	e, err := s.Get(ctx, id)
+	if err != nil {
+		return domain.Exec{}, err
+	}
+
+	if p.FirstName != nil {
+		e.FirstName = *p.FirstName
+	}
+	if p.LastName != nil {
+		e.LastName = *p.LastName
+	}
+	if p.Email != nil {
+		e.Email = *p.Email
+	}
+	if p.Username != nil {
+		e.Username = *p.Username
+	}
+	if p.Role != nil {
+		e.Role = *p.Role
+		// При смене роли старая привязка к профилю теряет смысл.
+		if e.Role != domain.RoleTeacher {
+			e.TeacherID = nil
+		}
+		if e.Role != domain.RoleStudent {
+			e.StudentID = nil
+		}
+		if e.Role != domain.RoleGuardian {
+			e.GuardianID = nil
+		}
+	}
+	if p.TeacherID != nil {
+		e.TeacherID = p.TeacherID
+	}
+	if p.StudentID != nil {
+		e.StudentID = p.StudentID
+	}
+	if p.GuardianID != nil {
+		e.GuardianID = p.GuardianID
+	}
+	if p.Active != nil {
+		e.Active = *p.Active
+	}
+	roleChanged := p.Role != nil || p.TeacherID != nil || p.StudentID != nil || p.GuardianID != nil
+
+	normalizeExec(&e)
+	if err := validateExec(e); err != nil {
+		return domain.Exec{}, err
+	}
+
+	if p.Password != nil {
+		hash, err := newPasswordHash(*p.Password)
+		if err != nil {
+			return domain.Exec{}, err
+		}
+		e.PasswordHash = hash
+	}
+
+	updated, err := s.repo.Update(ctx, e)
+	if err != nil {
+		return domain.Exec{}, err
+	}
+
+	if p.Password != nil || !updated.Active || roleChanged {
+		if err := s.sessions.DeleteByExec(ctx, id); err != nil {
+			return domain.Exec{}, err
+		}
+	}
+
+	return updated, nil
+}
+
+func (s *ExecService) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Bootstrap создаёт первого exec, если в таблице ещё никого нет. Пустые username/password — no-op.
+func (s *ExecService) Bootstrap(ctx context.Context, username, password, email string) (bool, error) {
+	if username == "" || password == "" {
+		return false, nil
+	}
+
+	n, err := s.repo.Count(ctx)
+	if err != nil {
+		return false, err
+	}
+	if n > 0 {
+		return false, nil
+	}
+
+	_, err = s.Create(ctx, domain.ExecInput{
+		FirstName: "Admin",
+		LastName:  "Admin",
+		Email:     email,
+		Username:  username,
+		Password:  password,
+		Role:      domain.RoleSuperadmin,
+	})
+	if err != nil {
+		return false, fmt.Errorf("bootstrap exec: %w", err)
+	}
+	return true, nil
+}
+
+func newPasswordHash(password string) (string, error) {
+	if len(password) < minPasswordLen {
+		var v domainerr.ValidationError
+		v.Add("password", fmt.Sprintf("must be at least %d characters", minPasswordLen))
+		return "", v.Err()
+	}
+	return hashPassword(password)
+}
+
+func normalizeExec(e *domain.Exec) {
+	e.FirstName = strings.TrimSpace(e.FirstName)
+	e.LastName = strings.TrimSpace(e.LastName)
+	e.Email = strings.ToLower(strings.TrimSpace(e.Email))
+	e.Username = strings.ToLower(strings.TrimSpace(e.Username))
+}
+
+func validateExec(e domain.Exec) error {
+	var v domainerr.ValidationError
+	if e.FirstName == "" {
+		v.Add("first_name", "is required")
+	}
+	if e.LastName == "" {
+		v.Add("last_name", "is required")
+	}
+	if _, err := mail.ParseAddress(e.Email); err != nil {
+		v.Add("email", "must be a valid email address")
+	}
+	if len(e.Username) < 3 {
+		v.Add("username", "must be at least 3 characters")
+	}
+	if !e.Role.Valid() {
+		v.Add("role", fmt.Sprintf("unknown role %q", e.Role))
+	}
+	// Привязка к профилю обязательна ровно для соответствующей роли.
+	if (e.Role == domain.RoleTeacher) != (e.TeacherID != nil) {
+		v.Add("teacher_id", "is required for role teacher only")
+	}
+	if (e.Role == domain.RoleStudent) != (e.StudentID != nil) {
+		v.Add("student_id", "is required for role student only")
+	}
+	if (e.Role == domain.RoleGuardian) != (e.GuardianID != nil) {
+		v.Add("guardian_id", "is required for role guardian only")
+	}
+	return v.Err()
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+	log "restapi/internal/logger"
+)
+
+const (
+	maxFileNameLen = 255
+	sniffLen       = 512 // столько байт смотрит http.DetectContentType
+)
+
+// FileStorage — хранилище содержимого файлов (локальный диск, S3). Ключи выдаёт сервис,
+// метаданные хранятся в БД. Файлы не публичны: скачать можно только по подписанной ссылке.
+type FileStorage interface {
+	// Put сохраняет ровно size байт из r под ключом key.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Delete удаляет файл; отсутствие файла ошибкой не считается.
+	Delete(ctx context.Context, key string) error
+	// SignedURL — ссылка на скачивание с ограниченным сроком; name и contentType уходят в заголовки ответа.
+	SignedURL(ctx context.Context, key, name, contentType string) (string, error)
+}
+
+// Upload — файл из multipart-запроса. Size посчитан сервером при разборе тела, ContentType — заявлен
+// клиентом и не используется: тип определяется по содержимому.
+type Upload struct {
+	Name        string
+	ContentType string
+	Size        int64
+	Body        io.Reader
+}
+
+// UploadPolicy — ограничения на файлы одного назначения (документы, фотографии).
+type UploadPolicy struct {
+	MaxSize int64
+	Types   []string // допустимые MIME-типы без параметров
+}
+
+// zipDocuments — офисные форматы в контейнере ZIP: по содержимому видно только application/zip,
+// конкретный тип берётся по расширению.
+var zipDocuments = map[string]string{
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".odt":  "application/vnd.oasis.opendocument.text",
+	".ods":  "application/vnd.oasis.opendocument.spreadsheet",
+}
+
+// preparedUpload — проверенный файл: имя очищено, тип определён по первым байтам.
+type preparedUpload struct {
+	name        string
+	contentType string
+	size        int64
+	body        io.Reader
+}
+
+// prepareUploads проверяет файлы по политике: число (с учётом уже приложенных have), имя, размер и тип.
+// Ошибки по всем файлам собираются в одну ValidationError — до записи чего-либо в хранилище.
+func prepareUploads(p UploadPolicy, uploads []Upload, limit, have int) ([]preparedUpload, error) {
+	var v domainerr.ValidationError
+	if have+len(uploads) > limit {
+		v.Add("files", fmt.Sprintf("at most %d files are allowed", limit))
+		return nil, v.Err()
+	}
+
+	out := make([]preparedUpload, 0, len(uploads))
+	for i, u := range uploads {
+		field := "files[" + strconv.Itoa(i) + "]"
+		pu, err := prepareUpload(p, u)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case pu.name == "":
+			v.Add(field, "file name is required")
+		case pu.size == 0:
+			v.Add(field, "file is empty")
+		case pu.size > p.MaxSize:
+			v.Add(field, "must be at most "+formatSize(p.MaxSize))
+		case !p.allows(pu.contentType):
+			v.Add(field, fmt.Sprintf("type %s is not allowed", mediaType(pu.contentType)))
+		}
+		out = append(out, pu)
+	}
+	return out, v.Err()
+}
+
+// prepareUpload читает начало файла и определяет тип; тело остаётся целым.
+func prepareUpload(p UploadPolicy, u Upload) (preparedUpload, error) {
+	name := cleanFileName(u.Name)
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(u.Body, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return preparedUpload{}, fmt.Errorf("read upload %q: %w", name, err)
+	}
+	head = head[:n]
+	return preparedUpload{
+		name:        name,
+		contentType: sniffContentType(head, name),
+		size:        u.Size,
+		body:        io.MultiReader(bytes.NewReader(head), u.Body),
+	}, nil
+}
+
+func (p UploadPolicy) allows(contentType string) bool {
+	return slices.Contains(p.Types, mediaType(contentType))
+}
+
+// sniffContentType определяет тип по содержимому (алгоритм WHATWG, http.DetectContentType);
+// для ZIP-контейнеров уточняет офисный формат по расширению.
+func sniffContentType(head []byte, name string) string {
+	ct := http.DetectContentType(head)
+	if mediaType(ct) == "application/zip" {
+		if doc, ok := zipDocuments[strings.ToLower(path.Ext(name))]; ok {
+			return doc
+		}
+	}
+	return ct
+}
+
+func mediaType(ct string) string {
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return ct
+	}
+	return mt
+}
+
+// putUpload пишет проверенный файл в хранилище под новым ключом в каталоге prefix.
+func putUpload(ctx context.Context, storage FileStorage, prefix string, u preparedUpload) (domain.File, error) {
+	key, err := newStorageKey(prefix)
+	if err != nil {
+		return domain.File{}, err
+	}
+	if err := storage.Put(ctx, key, u.body, u.size, u.contentType); err != nil {
+		return domain.File{}, err
+	}
+	return domain.File{Key: key, Name: u.name, ContentType: u.contentType, Size: u.size}, nil
+}
+
+// deleteStored удаляет содержимое файла; запись в БД уже удалена или не создана, поэтому ошибка
+// только логируется — в хранилище остаётся «сирота», но не битая ссылка.
+func deleteStored(ctx context.Context, storage FileStorage, key string) {
+	if err := storage.Delete(ctx, key); err != nil {
+		log.Warn("delete stored file", "key", key, "err", err)
+	}
+}
+
+// newStorageKey — случайный ключ файла в каталоге prefix.
+func newStorageKey(prefix string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("read storage key: %w", err)
+	}
+	return path.Join(prefix, hex.EncodeToString(b)), nil
+}
+
+// cleanFileName оставляет от имени клиента только базовое имя без управляющих символов.
+func cleanFileName(name string) string {
+	name = strings.ReplaceAll(name, `\`, "/")
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, path.Base(name))
+	name = strings.TrimSpace(name)
+	if name == "." || name == ".." || name == "/" {
+		return ""
+	}
+	for len(name) > maxFileNameLen {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	return name
+}
+
+func formatSize(n int64) string {
+	switch {
+	case n >= 1<<20 && n%(1<<20) == 0:
+		return strconv.FormatInt(n>>20, 10) + " MB"
+	case n >= 1<<10 && n%(1<<10) == 0:
+		return strconv.FormatInt(n>>10, 10) + " KB"
+	}
+	return strconv.FormatInt(n, 10) + " bytes"
+}
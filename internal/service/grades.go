@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+// maxGradeBatch — ограничение на число оценок в одном пакетном запросе.
+const maxGradeBatch = 200
+
+type GradeRepository interface {
+	ListCategories(ctx context.Context) ([]domain.GradeCategory, error)
+	GetCategory(ctx context.Context, id int64) (domain.GradeCategory, error)
+	SaveCategory(ctx context.Context, c domain.GradeCategory) (domain.GradeCategory, error)
+
+	GetAssessment(ctx context.Context, id int64) (domain.Assessment, error)
+	ListAssessments(ctx context.Context, classID int64, f domain.GradeFilter) ([]domain.Assessment, error)
+	DeleteAssessment(ctx context.Context, id int64) error
+	SaveBatch(ctx context.Context, a domain.Assessment, entries []domain.GradeEntry) (domain.GradeBatchResult, error)
+
+	ListStudentGrades(ctx context.Context, studentID int64, f domain.GradeFilter) ([]domain.Grade, error)
+	ListClassGrades(ctx context.Context, classID int64, f domain.GradeFilter) ([]domain.Grade, error)
+	StudentAverages(ctx context.Context, studentID int64, f domain.GradeFilter) ([]domain.SubjectAverage, error)
+	ClassRanking(ctx context.Context, classID int64, f domain.GradeFilter) ([]domain.StudentRanking, error)
+}
+
+type GradeService struct {
+	repo        GradeRepository
+	classes     ClassRepository
+	students    StudentRepository
+	assignments AssignmentRepository
+	terms       TermRepository
+	now         func() time.Time
+}
+
+func NewGradeService(repo GradeRepository, classes ClassRepository, students StudentRepository, assignments AssignmentRepository, terms TermRepository) *GradeService {
+	return &GradeService{repo: repo, classes: classes, students: students, assignments: assignments, terms: terms, now: time.Now}
+}
+
+func (s *GradeService) Categories(ctx context.Context) ([]domain.GradeCategory, error) {
+	return s.repo.ListCategories(ctx)
+}
+
+// SaveCategory создаёт (ID == 0) или обновляет категорию.
+func (s *GradeService) SaveCategory(ctx context.Context, c domain.GradeCategory) (domain.GradeCategory, error) {
+	c.Code = strings.ToLower(strings.TrimSpace(c.Code))
+	c.Name = strings.TrimSpace(c.Name)
+
+	var v domainerr.ValidationError
+	if c.Code == "" {
+		v.Add("code", "is required")
+	}
+	if c.Name == "" {
+		v.Add("name", "is required")
+	}
+	if c.Weight <= 0 || c.Weight > 100 {
+		v.Add("weight", "must be in (0, 100]")
+	}
+	if err := v.Err(); err != nil {
+		return domain.GradeCategory{}, err
+	}
+
+	return s.repo.SaveCategory(ctx, c)
+}
+
+// RecordBatch сохраняет пакет оценок по классу. Учитель может ставить оценки
+// только по предмету, который сам ведёт в этом классе; администрация — по любому.
+// В закрытом периоде оценки не меняются (ErrForbidden).
+func (s *GradeService) RecordBatch(ctx context.Context, actor domain.Principal, classID int64, b domain.GradeBatch) (domain.GradeBatchResult, error) {
+	cls, err := s.classes.Get(ctx, classID)
+	if err != nil {
+		return domain.GradeBatchResult{}, err
+	}
+
+	a, err := s.resolveAssessment(ctx, cls, b)
+	if err != nil {
+		return domain.GradeBatchResult{}, err
+	}
+	// Сначала права: иначе посторонний учитель узнал бы по ответу, закрыт ли период.
+	teacherID, err := checkSubjectAccess(ctx, s.assignments, actor, classID, a.SubjectID)
+	if err != nil {
+		return domain.GradeBatchResult{}, err
+	}
+	if err := checkTermOpen(ctx, s.terms, cls.AcademicYear, a.Term); err != nil {
+		return domain.GradeBatchResult{}, err
+	}
+	if a.ID == 0 && teacherID != 0 {
+		a.TeacherID = &teacherID
+	}
+
+	if err := s.validateEntries(ctx, classID, a.MaxScore, b.Grades); err != nil {
+		return domain.GradeBatchResult{}, err
+	}
+
+	return s.repo.SaveBatch(ctx, a, b.Grades)
+}
+
+func (s *GradeService) DeleteAssessment(ctx context.Context, actor domain.Principal, id int64) error {
+	a, err := s.repo.GetAssessment(ctx, id)
+	if err != nil {
+		return err
+	}
+	if _, err := checkSubjectAccess(ctx, s.assignments, actor, a.ClassID, a.SubjectID); err != nil {
+		return err
+	}
+	cls, err := s.classes.Get(ctx, a.ClassID)
+	if err != nil {
+		return err
+	}
+	if err := checkTermOpen(ctx, s.terms, cls.AcademicYear, a.Term); err != nil {
+		return err
+	}
+	return s.repo.DeleteAssessment(ctx, id)
+}
+
+func (s *GradeService) Assessments(ctx context.Context, classID int64, f domain.GradeFilter) ([]domain.Assessment, error) {
+	if _, err := s.classes.Get(ctx, classID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListAssessments(ctx, classID, f)
+}
+
+// StudentGrades — оценки ученика и средневзвешенные по предметам. Без года и периода — за текущий период.
+// Учитель видит только предметы, которые сам ведёт в классе работы, — за любой год.
+func (s *GradeService) StudentGrades(ctx context.Context, actor domain.Principal, studentID int64, f domain.GradeFilter) (domain.StudentGrades, error) {
+	if err := validateGradeFilter(f); err != nil {
+		return domain.StudentGrades{}, err
+	}
+	// Фильтр по учителю задаётся только здесь, по роли.
+	f.TeacherID = 0
+	if actor.Role == domain.RoleTeacher {
+		if actor.TeacherID == 0 {
+			return domain.StudentGrades{}, domainerr.ErrForbidden
+		}
+		f.TeacherID = actor.TeacherID
+	}
+	if _, err := s.students.Get(ctx, studentID); err != nil {
+		return domain.StudentGrades{}, err
+	}
+	f, err := s.withCurrentTerm(ctx, f, 0)
+	if err != nil {
+		return domain.StudentGrades{}, err
+	}
+
+	grades, err := s.repo.ListStudentGrades(ctx, studentID, f)
+	if err != nil {
+		return domain.StudentGrades{}, err
+	}
+	avgs, err := s.repo.StudentAverages(ctx, studentID, f)
+	if err != nil {
+		return domain.StudentGrades{}, err
+	}
+
+	return domain.StudentGrades{StudentID: studentID, Grades: grades, Averages: avgs}, nil
+}
+
+// ClassGrades — оценки класса. Без года и периода — за текущий период, если класс текущего года.
+func (s *GradeService) ClassGrades(ctx context.Context, classID int64, f domain.GradeFilter) ([]domain.Grade, error) {
+	if err := validateGradeFilter(f); err != nil {
+		return nil, err
+	}
+	cls, err := s.classes.Get(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+	if f, err = s.withCurrentTerm(ctx, f, cls.AcademicYear); err != nil {
+		return nil, err
+	}
+	return s.repo.ListClassGrades(ctx, classID, f)
+}
+
+func (s *GradeService) ClassRanking(ctx context.Context, classID int64, f domain.GradeFilter) ([]domain.StudentRanking, error) {
+	if err := validateGradeFilter(f); err != nil {
+		return nil, err
+	}
+	cls, err := s.classes.Get(ctx, classID)
+	if err != nil {
+		return nil, err
+	}
+	if f, err = s.withCurrentTerm(ctx, f, cls.AcademicYear); err != nil {
+		return nil, err
+	}
+	return s.repo.ClassRanking(ctx, classID, f)
+}
+
+// withCurrentTerm подставляет текущий период, если ни год, ни период не заданы.
+// classYear != 0 — выборка по классу: период другого учебного года не подставляется.
+func (s *GradeService) withCurrentTerm(ctx context.Context, f domain.GradeFilter, classYear int) (domain.GradeFilter, error) {
+	if f.AcademicYear != 0 || f.Term != 0 {
+		return f, nil
+	}
+	t, ok, err := termOn(ctx, s.terms, s.now())
+	if err != nil || !ok {
+		return f, err
+	}
+	if classYear != 0 && t.AcademicYear != classYear {
+		return f, nil
+	}
+	f.AcademicYear, f.Term = t.AcademicYear, t.Number
+	return f, nil
+}
+
+// resolveAssessment возвращает существующую работу класса или собирает новую из полей пакета.
+// Период новой работы по умолчанию — тот, на который приходится её дата.
+func (s *GradeService) resolveAssessment(ctx context.Context, cls domain.Class, b domain.GradeBatch) (domain.Assessment, error) {
+	classID := cls.ID
+	if b.AssessmentID != 0 {
+		a, err := s.repo.GetAssessment(ctx, b.AssessmentID)
+		if err != nil {
+			return domain.Assessment{}, err
+		}
+		if a.ClassID != classID {
+			return domain.Assessment{}, domainerr.Newf(domainerr.ErrBadInput, "assessment %d belongs to another class", a.ID)
+		}
+		return a, nil
+	}
+
+	a := domain.Assessment{
+		ClassID:    classID,
+		SubjectID:  b.SubjectID,
+		CategoryID: b.CategoryID,
+		Term:       b.Term,
+		Title:      strings.TrimSpace(b.Title),
+		Date:       b.Date,
+		MaxScore:   b.MaxScore,
+	}
+
+	if a.Term == 0 && !a.Date.IsZero() {
+		t, ok, err := termOn(ctx, s.terms, a.Date)
+		if err != nil {
+			return domain.Assessment{}, err
+		}
+		if ok && t.AcademicYear == cls.AcademicYear {
+			a.Term = t.Number
+		}
+	}
+
+	var v domainerr.ValidationError
+	if a.Term < domain.MinTerm || a.Term > domain.MaxTerm {
+		v.Add("term", fmt.Sprintf("must be between %d and %d", domain.MinTerm, domain.MaxTerm))
+	} else if !a.Date.IsZero() {
+		// Если период заведён в календаре, дата работы должна в него попадать.
+		t, err := s.terms.TermByNumber(ctx, cls.AcademicYear, a.Term)
+		switch {
+		case errors.Is(err, domainerr.ErrNotFound):
+		case err != nil:
+			return domain.Assessment{}, err
+		case !t.Contains(a.Date):
+			v.Add("date", fmt.Sprintf("must be within term %d (%s — %s)", a.Term,
+				t.StartDate.Format(time.DateOnly), t.EndDate.Format(time.DateOnly)))
+		}
+	}
+	if a.Title == "" {
+		v.Add("title", "is required")
+	}
+	if a.Date.IsZero() {
+		v.Add("date", "is required")
+	}
+	if a.MaxScore <= 0 {
+		v.Add("max_score", "must be > 0")
+	}
+	if err := exists(&v, "category_id", func() error {
+		_, err := s.repo.GetCategory(ctx, a.CategoryID)
+		return err
+	}); err != nil {
+		return domain.Assessment{}, err
+	}
+	if a.SubjectID <= 0 {
+		v.Add("subject_id", "is required")
+	}
+
+	return a, v.Err()
+}
+
+// checkSubjectAccess проверяет право ставить оценки по предмету в классе.
+// Возвращает id учителя, ведущего предмет (0, если назначения нет и действует администрация).
+func checkSubjectAccess(ctx context.Context, assignments AssignmentRepository, actor domain.Principal, classID, subjectID int64) (int64, error) {
+	asg, err := assignments.Get(ctx, classID, subjectID)
+	switch {
+	case errors.Is(err, domainerr.ErrNotFound):
+		if actor.Role == domain.RoleTeacher {
+			return 0, domainerr.Newf(domainerr.ErrForbidden, "subject is not taught in this class by you")
+		}
+		var v domainerr.ValidationError
+		v.Add("subject_id", "subject is not assigned to this class")
+		return 0, v.Err()
+	case err != nil:
+		return 0, err
+	}
+
+	switch actor.Role {
+	case domain.RoleSuperadmin, domain.RolePrincipal:
+		return asg.TeacherID, nil
+	case domain.RoleTeacher:
+		if actor.TeacherID == asg.TeacherID {
+			return asg.TeacherID, nil
+		}
+	}
+	return 0, domainerr.Newf(domainerr.ErrForbidden, "only the subject teacher may change these grades")
+}
+
+func (s *GradeService) validateEntries(ctx context.Context, classID int64, maxScore float64, entries []domain.GradeEntry) error {
+	var v domainerr.ValidationError
+	if len(entries) == 0 {
+		v.Add("grades", "at least one grade is required")
+		return v.Err()
+	}
+	if len(entries) > maxGradeBatch {
+		v.Add("grades", fmt.Sprintf("at most %d grades per request", maxGradeBatch))
+		return v.Err()
+	}
+
+	seen := make(map[int64]bool, len(entries))
+	ids := make([]int64, 0, len(entries))
+	for i, e := range entries {
+		field := "grades[" + strconv.Itoa(i) + "]"
+		if seen[e.StudentID] {
+			v.Add(field+".student_id", "duplicate student")
+		}
+		seen[e.StudentID] = true
+		ids = append(ids, e.StudentID)
+
+		if e.Score < 0 || e.Score > maxScore {
+			v.Add(field+".score", fmt.Sprintf("must be between 0 and %g", maxScore))
+		}
+	}
+	if err := v.Err(); err != nil {
+		return err
+	}
+
+	outside, err := s.classes.StudentsOutsideClass(ctx, classID, ids)
+	if err != nil {
+		return err
+	}
+	for _, id := range outside {
+		v.Add("grades.student_id", fmt.Sprintf("student %d is not in this class", id))
+	}
+	return v.Err()
+}
+
+func validateGradeFilter(f domain.GradeFilter) error {
+	if f.Term != 0 && (f.Term < domain.MinTerm || f.Term > domain.MaxTerm) {
+		var v domainerr.ValidationError
+		v.Add("term", fmt.Sprintf("must be between %d and %d", domain.MinTerm, domain.MaxTerm))
+		return v.Err()
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+// fakeGradeRepo запоминает фильтры выборок оценок ученика.
+type fakeGradeRepo struct {
+	GradeRepository
+	filters []domain.GradeFilter
+}
+
+func (r *fakeGradeRepo) ListStudentGrades(_ context.Context, _ int64, f domain.GradeFilter) ([]domain.Grade, error) {
+	r.filters = append(r.filters, f)
+	return nil, nil
+}
+
+func (r *fakeGradeRepo) StudentAverages(_ context.Context, _ int64, f domain.GradeFilter) ([]domain.SubjectAverage, error) {
+	r.filters = append(r.filters, f)
+	return nil, nil
+}
+
+func TestStudentGradesTeacherScope(t *testing.T) {
+	tests := []struct {
+		name          string
+		actor         domain.Principal
+		wantTeacherID int64
+		wantErr       error
+	}{
+		{name: "teacher sees own subjects only", actor: domain.Principal{Role: domain.RoleTeacher, TeacherID: 4}, wantTeacherID: 4},
+		{name: "teacher without profile", actor: domain.Principal{Role: domain.RoleTeacher}, wantErr: domainerr.ErrForbidden},
+		{name: "principal sees everything", actor: domain.Principal{Role: domain.RolePrincipal, TeacherID: 4}},
+		{name: "student sees everything", actor: domain.Principal{Role: domain.RoleStudent, StudentID: 1}},
+		{name: "guardian sees everything", actor: domain.Principal{Role: domain.RoleGuardian, GuardianID: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grades := &fakeGradeRepo{}
+			students := &fakeStudentRepo{st: domain.Student{ID: 1}}
+			svc := NewGradeService(grades, fakeClassRepo{}, students, nil, nil)
+
+			// TeacherID вызывающего игнорируется: его задаёт сервис по роли.
+			f := domain.GradeFilter{AcademicYear: 2026, SubjectID: 2, TeacherID: 99}
+			_, err := svc.StudentGrades(context.Background(), tt.actor, 1, f)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				if len(grades.filters) != 0 {
+					t.Error("grades queried despite error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(grades.filters) != 2 {
+				t.Fatalf("queries = %d, want 2", len(grades.filters))
+			}
+			for _, got := range grades.filters {
+				want := domain.GradeFilter{AcademicYear: 2026, SubjectID: 2, TeacherID: tt.wantTeacherID}
+				if got != want {
+					t.Errorf("filter = %+v, want %+v", got, want)
+				}
+			}
+		})
+	}
+}
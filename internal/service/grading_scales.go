@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+type GradingScaleRepository interface {
+	List(ctx context.Context) ([]domain.GradingScale, error)
+	Get(ctx context.Context, id int64) (domain.GradingScale, error)
+	GetByCode(ctx context.Context, code string) (domain.GradingScale, error)
+	Default(ctx context.Context) (domain.GradingScale, error)
+	Create(ctx context.Context, s domain.GradingScale) (domain.GradingScale, error)
+	Update(ctx context.Context, s domain.GradingScale) (domain.GradingScale, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type GradingScaleService struct {
+	repo GradingScaleRepository
+}
+
+func NewGradingScaleService(repo GradingScaleRepository) *GradingScaleService {
+	return &GradingScaleService{repo: repo}
+}
+
+func (s *GradingScaleService) List(ctx context.Context) ([]domain.GradingScale, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *GradingScaleService) Get(ctx context.Context, id int64) (domain.GradingScale, error) {
+	if id <= 0 {
+		return domain.GradingScale{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Get(ctx, id)
+}
+
+func (s *GradingScaleService) Create(ctx context.Context, sc domain.GradingScale) (domain.GradingScale, error) {
+	normalizeScale(&sc)
+	if err := validateScale(sc); err != nil {
+		return domain.GradingScale{}, err
+	}
+	return s.repo.Create(ctx, sc)
+}
+
+// Update заменяет шкалу. Снять признак «по умолчанию» можно, только назначив другую шкалу.
+func (s *GradingScaleService) Update(ctx context.Context, sc domain.GradingScale) (domain.GradingScale, error) {
+	if sc.ID <= 0 {
+		return domain.GradingScale{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	normalizeScale(&sc)
+	if err := validateScale(sc); err != nil {
+		return domain.GradingScale{}, err
+	}
+
+	cur, err := s.repo.Get(ctx, sc.ID)
+	if err != nil {
+		return domain.GradingScale{}, err
+	}
+	if cur.Default && !sc.Default {
+		return domain.GradingScale{}, domainerr.Newf(domainerr.ErrConflict, "make another scale the default instead")
+	}
+	return s.repo.Update(ctx, sc)
+}
+
+func (s *GradingScaleService) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	cur, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if cur.Default {
+		return domainerr.Newf(domainerr.ErrConflict, "the default scale cannot be deleted")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Convert переводит оценку value шкалы from в шкалу to через процент:
+// для интервальной шкалы берётся середина интервала оценки.
+func (s *GradingScaleService) Convert(ctx context.Context, from, to, value string) (domain.GradeConversion, error) {
+	value = strings.TrimSpace(value)
+
+	var v domainerr.ValidationError
+	if from == "" {
+		v.Add("from", "is required")
+	}
+	if to == "" {
+		v.Add("to", "is required")
+	}
+	if value == "" {
+		v.Add("value", "is required")
+	}
+	if err := v.Err(); err != nil {
+		return domain.GradeConversion{}, err
+	}
+
+	src, err := scaleByCode(ctx, s.repo, "from", from)
+	if err != nil {
+		return domain.GradeConversion{}, err
+	}
+	dst, err := scaleByCode(ctx, s.repo, "to", to)
+	if err != nil {
+		return domain.GradeConversion{}, err
+	}
+
+	percent, ok := src.Percent(value)
+	if !ok {
+		v.Add("value", fmt.Sprintf("%q is not a grade of scale %s", value, src.Code))
+		return domain.GradeConversion{}, v.Err()
+	}
+	return domain.GradeConversion{From: src.Code, To: dst.Code, Value: value, Percent: percent, Result: dst.Convert(percent)}, nil
+}
+
+// scaleByCode — шкала по коду; пустой код — шкала по умолчанию. Неизвестный код — ошибка поля field.
+func scaleByCode(ctx context.Context, repo GradingScaleRepository, field, code string) (domain.GradingScale, error) {
+	if code == "" {
+		return repo.Default(ctx)
+	}
+	sc, err := repo.GetByCode(ctx, strings.ToLower(code))
+	if errors.Is(err, domainerr.ErrNotFound) {
+		var v domainerr.ValidationError
+		v.Add(field, fmt.Sprintf("unknown grading scale %q", code))
+		return domain.GradingScale{}, v.Err()
+	}
+	return sc, err
+}
+
+func normalizeScale(s *domain.GradingScale) {
+	s.Code = strings.ToLower(strings.TrimSpace(s.Code))
+	s.Name = strings.TrimSpace(s.Name)
+	for i := range s.Bands {
+		s.Bands[i].Label = strings.TrimSpace(s.Bands[i].Label)
+	}
+}
+
+func validateScale(s domain.GradingScale) error {
+	var v domainerr.ValidationError
+	if s.Code == "" {
+		v.Add("code", "is required")
+	}
+	if s.Name == "" {
+		v.Add("name", "is required")
+	}
+	if !s.Kind.Valid() {
+		v.Add("kind", "must be one of: banded, linear")
+	}
+	if s.MaxPoints <= 0 || s.MaxPoints > 1000 {
+		v.Add("max_points", "must be in (0, 1000]")
+	}
+
+	switch s.Kind {
+	case domain.ScaleLinear:
+		if len(s.Bands) > 0 {
+			v.Add("bands", "must be empty for a linear scale")
+		}
+	case domain.ScaleBanded:
+		if len(s.Bands) == 0 {
+			v.Add("bands", "at least one band is required")
+		}
+		mins := make(map[float64]bool, len(s.Bands))
+		labels := make(map[string]bool, len(s.Bands))
+		for i, b := range s.Bands {
+			field := "bands[" + strconv.Itoa(i) + "]"
+			if b.MinPercent < 0 || b.MinPercent > 100 {
+				v.Add(field+".min_percent", "must be between 0 and 100")
+			} else if mins[b.MinPercent] {
+				v.Add(field+".min_percent", "duplicate band")
+			}
+			mins[b.MinPercent] = true
+			if b.Label == "" {
+				v.Add(field+".label", "is required")
+			} else if labels[b.Label] {
+				v.Add(field+".label", "duplicate label")
+			}
+			labels[b.Label] = true
+			if b.Points < 0 || b.Points > s.MaxPoints {
+				v.Add(field+".points", "must be between 0 and max_points")
+			}
+		}
+		if len(s.Bands) > 0 && !mins[0] {
+			v.Add("bands", "a band starting at 0% is required")
+		}
+	}
+	return v.Err()
+}
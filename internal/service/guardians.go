@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+// Код приглашения: inviteCodeLen символов из алфавита без похожих друг на друга букв и цифр (I/1, O/0).
+// 32 символа — 5 бит на символ, итого 50 бит; код вводят руками, поэтому он разбит дефисом пополам.
+const (
+	inviteAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	inviteCodeLen  = 10
+)
+
+type GuardianRepository interface {
+	List(ctx context.Context, f domain.GuardianFilter) ([]domain.Guardian, error)
+	Get(ctx context.Context, id int64) (domain.Guardian, error)
+	Create(ctx context.Context, g domain.Guardian) (domain.Guardian, error)
+	Update(ctx context.Context, g domain.Guardian) (domain.Guardian, error)
+	Delete(ctx context.Context, id int64) error
+
+	ListByStudent(ctx context.Context, studentID int64) ([]domain.StudentGuardian, error)
+	ListWards(ctx context.Context, guardianID int64) ([]domain.Ward, error)
+	Link(ctx context.Context, studentID, guardianID int64, l domain.GuardianLink) error
+	Unlink(ctx context.Context, studentID, guardianID int64) error
+	IsGuardianOf(ctx context.Context, guardianID, studentID int64) (bool, error)
+
+	CreateInvite(ctx context.Context, inv domain.GuardianInvite, codeHash []byte) (domain.GuardianInvite, error)
+	ActiveInvite(ctx context.Context, codeHash []byte) (domain.GuardianInvite, error)
+	Redeem(ctx context.Context, inviteID int64, e domain.Exec) (domain.Exec, error)
+}
+
+type GuardianService struct {
+	repo      GuardianRepository
+	students  StudentRepository
+	inviteTTL time.Duration
+	now       func() time.Time
+}
+
+func NewGuardianService(repo GuardianRepository, students StudentRepository, inviteTTL time.Duration) *GuardianService {
+	return &GuardianService{repo: repo, students: students, inviteTTL: inviteTTL, now: time.Now}
+}
+
+func (s *GuardianService) List(ctx context.Context, f domain.GuardianFilter) ([]domain.Guardian, error) {
+	f.Limit, f.Offset = normalizePage(f.Limit, f.Offset)
+	return s.repo.List(ctx, f)
+}
+
+func (s *GuardianService) Get(ctx context.Context, id int64) (domain.Guardian, error) {
+	if id <= 0 {
+		return domain.Guardian{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Get(ctx, id)
+}
+
+func (s *GuardianService) Create(ctx context.Context, g domain.Guardian) (domain.Guardian, error) {
+	normalizeGuardian(&g)
+	if err := validateGuardian(g); err != nil {
+		return domain.Guardian{}, err
+	}
+	return s.repo.Create(ctx, g)
+}
+
+// Update — полная замена (PUT).
+func (s *GuardianService) Update(ctx context.Context, g domain.Guardian) (domain.Guardian, error) {
+	if g.ID <= 0 {
+		return domain.Guardian{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	normalizeGuardian(&g)
+	if err := validateGuardian(g); err != nil {
+		return domain.Guardian{}, err
+	}
+	return s.repo.Update(ctx, g)
+}
+
+// Delete удаляет представителя; его учётка удаляется вместе с ним.
+func (s *GuardianService) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *GuardianService) StudentGuardians(ctx context.Context, studentID int64) ([]domain.StudentGuardian, error) {
+	if _, err := s.students.Get(ctx, studentID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByStudent(ctx, studentID)
+}
+
+func (s *GuardianService) Wards(ctx context.Context, guardianID int64) ([]domain.Ward, error) {
+	if _, err := s.Get(ctx, guardianID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListWards(ctx, guardianID)
+}
+
+// Link связывает ученика с представителем (или меняет параметры связи) и возвращает всех представителей ученика.
+func (s *GuardianService) Link(ctx context.Context, studentID, guardianID int64, l domain.GuardianLink) ([]domain.StudentGuardian, error) {
+	if _, err := s.students.Get(ctx, studentID); err != nil {
+		return nil, err
+	}
+
+	var v domainerr.ValidationError
+	if !l.Relationship.Valid() {
+		v.Add("relationship", fmt.Sprintf("unknown relationship %q", l.Relationship))
+	}
+	if err := exists(&v, "guardian_id", func() error {
+		_, err := s.Get(ctx, guardianID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	if err := v.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Link(ctx, studentID, guardianID, l); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByStudent(ctx, studentID)
+}
+
+func (s *GuardianService) Unlink(ctx context.Context, studentID, guardianID int64) error {
+	if studentID <= 0 || guardianID <= 0 {
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Unlink(ctx, studentID, guardianID)
+}
+
+// IsGuardianOf — для RBAC: представитель видит только своих детей.
+func (s *GuardianService) IsGuardianOf(ctx context.Context, guardianID, studentID int64) (bool, error) {
+	return s.repo.IsGuardianOf(ctx, guardianID, studentID)
+}
+
+// Invite выпускает одноразовый код приглашения. Прежние непогашенные коды представителя аннулируются.
+// Код возвращается только здесь — в БД хранится его хеш.
+func (s *GuardianService) Invite(ctx context.Context, actor domain.Principal, guardianID int64) (domain.GuardianInvite, error) {
+	g, err := s.Get(ctx, guardianID)
+	if err != nil {
+		return domain.GuardianInvite{}, err
+	}
+	if g.HasAccount {
+		return domain.GuardianInvite{}, domainerr.Newf(domainerr.ErrConflict, "guardian already has an account")
+	}
+
+	code, err := newInviteCode()
+	if err != nil {
+		return domain.GuardianInvite{}, err
+	}
+
+	inv := domain.GuardianInvite{GuardianID: guardianID, ExpiresAt: s.now().Add(s.inviteTTL)}
+	if actor.ExecID != 0 {
+		inv.CreatedBy = &actor.ExecID
+	}
+	created, err := s.repo.CreateInvite(ctx, inv, hashToken(normalizeInviteCode(code)))
+	if err != nil {
+		return domain.GuardianInvite{}, err
+	}
+	created.Code = code
+	return created, nil
+}
+
+// Redeem гасит приглашение и заводит учётку представителя. Имя берётся из карточки представителя,
+// email — из запроса или из карточки.
+func (s *GuardianService) Redeem(ctx context.Context, in domain.InviteRedemption) (domain.Exec, error) {
+	invalidCode := func() error {
+		var v domainerr.ValidationError
+		v.Add("code", "is invalid or expired")
+		return v.Err()
+	}
+
+	code := normalizeInviteCode(in.Code)
+	if code == "" {
+		return domain.Exec{}, invalidCode()
+	}
+	inv, err := s.repo.ActiveInvite(ctx, hashToken(code))
+	if errors.Is(err, domainerr.ErrNotFound) {
+		return domain.Exec{}, invalidCode()
+	}
+	if err != nil {
+		return domain.Exec{}, err
+	}
+
+	g, err := s.repo.Get(ctx, inv.GuardianID)
+	if err != nil {
+		return domain.Exec{}, err
+	}
+
+	e := domain.Exec{
+		FirstName:  g.FirstName,
+		LastName:   g.LastName,
+		Email:      in.Email,
+		Username:   in.Username,
+		Role:       domain.RoleGuardian,
+		GuardianID: &g.ID,
+		Active:     true,
+	}
+	if strings.TrimSpace(e.Email) == "" {
+		e.Email = g.Email
+	}
+	normalizeExec(&e)
+	if err := validateExec(e); err != nil {
+		return domain.Exec{}, err
+	}
+
+	hash, err := newPasswordHash(in.Password)
+	if err != nil {
+		return domain.Exec{}, err
+	}
+	e.PasswordHash = hash
+
+	created, err := s.repo.Redeem(ctx, inv.ID, e)
+	if errors.Is(err, domainerr.ErrNotFound) {
+		// Код погасили параллельным запросом или он только что истёк.
+		return domain.Exec{}, invalidCode()
+	}
+	return created, err
+}
+
+func newInviteCode() (string, error) {
+	b := make([]byte, inviteCodeLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("read invite code: %w", err)
+	}
+	for i := range b {
+		b[i] = inviteAlphabet[int(b[i])%len(inviteAlphabet)]
+	}
+	return string(b[:inviteCodeLen/2]) + "-" + string(b[inviteCodeLen/2:]), nil
+}
+
+// normalizeInviteCode приводит введённый код к каноническому виду: без дефисов и пробелов, в верхнем регистре.
+func normalizeInviteCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
+
+func normalizeGuardian(g *domain.Guardian) {
+	g.FirstName = strings.TrimSpace(g.FirstName)
+	g.LastName = strings.TrimSpace(g.LastName)
+	g.Email = strings.ToLower(strings.TrimSpace(g.Email))
+	g.Phone = strings.TrimSpace(g.Phone)
+	g.Address = strings.TrimSpace(g.Address)
+}
+
+func validateGuardian(g domain.Guardian) error {
+	var v domainerr.ValidationError
+	if g.FirstName == "" {
+		v.Add("first_name", "is required")
+	}
+	if g.LastName == "" {
+		v.Add("last_name", "is required")
+	}
+	if g.Email != "" {
+		if _, err := mail.ParseAddress(g.Email); err != nil {
+			v.Add("email", "must be a valid email address")
+		}
+	}
+	if g.Email == "" && g.Phone == "" {
+		v.Add("phone", "email or phone is required")
+	}
+	return v.Err()
+}
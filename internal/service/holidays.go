@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+type HolidayRepository interface {
+	List(ctx context.Context, p domain.DateRange) ([]domain.Holiday, error)
+	Get(ctx context.Context, id int64) (domain.Holiday, error)
+	Create(ctx context.Context, h domain.Holiday) (domain.Holiday, error)
+	Update(ctx context.Context, h domain.Holiday) (domain.Holiday, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type HolidayService struct {
+	repo HolidayRepository
+}
+
+func NewHolidayService(repo HolidayRepository) *HolidayService {
+	return &HolidayService{repo: repo}
+}
+
+func (s *HolidayService) List(ctx context.Context, p domain.DateRange) ([]domain.Holiday, error) {
+	if err := validateDateRange(p); err != nil {
+		return nil, err
+	}
+	return s.repo.List(ctx, p)
+}
+
+func (s *HolidayService) Get(ctx context.Context, id int64) (domain.Holiday, error) {
+	if id <= 0 {
+		return domain.Holiday{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Get(ctx, id)
+}
+
+func (s *HolidayService) Create(ctx context.Context, h domain.Holiday) (domain.Holiday, error) {
+	normalizeHoliday(&h)
+	if err := validateHoliday(h); err != nil {
+		return domain.Holiday{}, err
+	}
+	return s.repo.Create(ctx, h)
+}
+
+func (s *HolidayService) Update(ctx context.Context, h domain.Holiday) (domain.Holiday, error) {
+	if h.ID <= 0 {
+		return domain.Holiday{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	normalizeHoliday(&h)
+	if err := validateHoliday(h); err != nil {
+		return domain.Holiday{}, err
+	}
+	return s.repo.Update(ctx, h)
+}
+
+func (s *HolidayService) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func normalizeHoliday(h *domain.Holiday) {
+	h.Name = strings.TrimSpace(h.Name)
+	if h.Kind == "" {
+		h.Kind = domain.HolidayVacation
+	}
+	h.StartDate, h.EndDate = dateOnly(h.StartDate), dateOnly(h.EndDate)
+	// Однодневный выходной можно задать одной датой.
+	if h.EndDate.IsZero() {
+		h.EndDate = h.StartDate
+	}
+}
+
+func validateHoliday(h domain.Holiday) error {
+	var v domainerr.ValidationError
+	if h.Name == "" {
+		v.Add("name", "is required")
+	}
+	if !h.Kind.Valid() {
+		v.Add("kind", fmt.Sprintf("unknown kind %q", h.Kind))
+	}
+	if h.StartDate.IsZero() {
+		v.Add("start_date", "is required")
+	} else if h.EndDate.Before(h.StartDate) {
+		v.Add("end_date", "must not be before start_date")
+	}
+	return v.Err()
+}
@@ -0,0 +1,602 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+// Ограничения домашних заданий.
+const (
+	maxHomeworkFiles = 10 // вложений у задания и файлов у одной сдачи
+	maxHomeworkTitle = 200
+	maxHomeworkText  = 20000 // описание задания и текст сдачи, символов
+	maxFeedbackText  = 1000
+
+	homeworkCategory = "homework" // категория журнала по умолчанию
+)
+
+type HomeworkRepository interface {
+	Get(ctx context.Context, id int64) (domain.Homework, error)
+	ListByClass(ctx context.Context, classID int64, f domain.HomeworkFilter) ([]domain.Homework, error)
+	ListForStudent(ctx context.Context, studentID, classID int64, f domain.HomeworkFilter) ([]domain.StudentHomework, error)
+	Create(ctx context.Context, a domain.Assessment, h domain.Homework) (domain.Homework, error)
+	Update(ctx context.Context, a domain.Assessment, h domain.Homework) (domain.Homework, error)
+	Delete(ctx context.Context, id int64) ([]string, error)
+
+	AddFile(ctx context.Context, f domain.HomeworkFile) (domain.HomeworkFile, error)
+	GetFile(ctx context.Context, id int64) (domain.HomeworkFile, error)
+	DeleteFile(ctx context.Context, id int64) error
+
+	GetSubmission(ctx context.Context, id int64) (domain.Submission, error)
+	SubmissionFor(ctx context.Context, homeworkID, studentID int64) (domain.Submission, error)
+	ListSubmissions(ctx context.Context, homeworkID int64) ([]domain.Submission, error)
+	SaveSubmission(ctx context.Context, s domain.Submission) (domain.Submission, error)
+	Grade(ctx context.Context, s domain.Submission, assessmentID int64) (domain.Submission, error)
+}
+
+type HomeworkService struct {
+	repo        HomeworkRepository
+	grades      GradeRepository
+	classes     ClassRepository
+	students    StudentRepository
+	assignments AssignmentRepository
+	guardians   GuardianRepository
+	terms       TermRepository
+	storage     FileStorage
+	policy      UploadPolicy
+	loc         *time.Location // пояс школы: по нему срок сдачи переводится в дату работы в журнале
+	now         func() time.Time
+}
+
+func NewHomeworkService(repo HomeworkRepository, grades GradeRepository, classes ClassRepository, students StudentRepository,
+	assignments AssignmentRepository, guardians GuardianRepository, terms TermRepository, storage FileStorage, policy UploadPolicy, loc *time.Location,
+) *HomeworkService {
+	return &HomeworkService{
+		repo: repo, grades: grades, classes: classes, students: students, assignments: assignments,
+		guardians: guardians, terms: terms, storage: storage, policy: policy, loc: loc, now: time.Now,
+	}
+}
+
+// ClassHomework — задания класса по сроку сдачи.
+func (s *HomeworkService) ClassHomework(ctx context.Context, classID int64, f domain.HomeworkFilter) ([]domain.Homework, error) {
+	if _, err := s.classes.Get(ctx, classID); err != nil {
+		return nil, err
+	}
+	f.Limit, f.Offset = normalizePage(f.Limit, f.Offset)
+	return s.repo.ListByClass(ctx, classID, f)
+}
+
+// StudentHomework — задания класса ученика с его сдачами. Ученик без класса — пустой список.
+func (s *HomeworkService) StudentHomework(ctx context.Context, studentID int64, f domain.HomeworkFilter) ([]domain.StudentHomework, error) {
+	st, err := s.students.Get(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if st.ClassID == nil {
+		return []domain.StudentHomework{}, nil
+	}
+	f.Limit, f.Offset = normalizePage(f.Limit, f.Offset)
+	list, err := s.repo.ListForStudent(ctx, studentID, *st.ClassID, f)
+	if list == nil && err == nil {
+		list = []domain.StudentHomework{}
+	}
+	return list, err
+}
+
+func (s *HomeworkService) Get(ctx context.Context, actor domain.Principal, id int64) (domain.Homework, error) {
+	h, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return domain.Homework{}, err
+	}
+	if err := s.checkView(ctx, actor, h.ClassID); err != nil {
+		return domain.Homework{}, err
+	}
+	return h, nil
+}
+
+// Create публикует задание классу и заводит под него работу в журнале. Учитель — только по своему предмету.
+func (s *HomeworkService) Create(ctx context.Context, actor domain.Principal, classID int64, in domain.HomeworkInput) (domain.Homework, error) {
+	cls, err := s.classes.Get(ctx, classID)
+	if err != nil {
+		return domain.Homework{}, err
+	}
+
+	normalizeHomework(&in)
+	if in.SubjectID <= 0 {
+		var v domainerr.ValidationError
+		v.Add("subject_id", "is required")
+		return domain.Homework{}, v.Err()
+	}
+	teacherID, err := checkSubjectAccess(ctx, s.assignments, actor, classID, in.SubjectID)
+	if err != nil {
+		return domain.Homework{}, err
+	}
+
+	a, err := s.assessment(ctx, cls, in)
+	if err != nil {
+		return domain.Homework{}, err
+	}
+	if err := checkTermOpen(ctx, s.terms, cls.AcademicYear, a.Term); err != nil {
+		return domain.Homework{}, err
+	}
+
+	h := domain.Homework{
+		ClassID:     classID,
+		SubjectID:   in.SubjectID,
+		Title:       in.Title,
+		Description: in.Description,
+		DueAt:       in.DueAt,
+		AllowLate:   in.AllowLate == nil || *in.AllowLate,
+		CreatedBy:   &actor.ExecID,
+	}
+	if teacherID != 0 {
+		h.TeacherID, a.TeacherID = &teacherID, &teacherID
+	}
+
+	return s.repo.Create(ctx, a, h)
+}
+
+// Update правит задание. Предмет не меняется; новый максимум не может быть ниже выставленных баллов.
+func (s *HomeworkService) Update(ctx context.Context, actor domain.Principal, id int64, in domain.HomeworkInput) (domain.Homework, error) {
+	h, cls, err := s.manage(ctx, actor, id)
+	if err != nil {
+		return domain.Homework{}, err
+	}
+
+	normalizeHomework(&in)
+	if in.SubjectID != 0 && in.SubjectID != h.SubjectID {
+		var v domainerr.ValidationError
+		v.Add("subject_id", "cannot be changed")
+		return domain.Homework{}, v.Err()
+	}
+	in.SubjectID = h.SubjectID
+
+	a, err := s.assessment(ctx, cls, in)
+	if err != nil {
+		return domain.Homework{}, err
+	}
+	if a.Term != h.Term {
+		if err := checkTermOpen(ctx, s.terms, cls.AcademicYear, a.Term); err != nil {
+			return domain.Homework{}, err
+		}
+	}
+	if a.MaxScore < h.MaxScore {
+		subs, err := s.repo.ListSubmissions(ctx, id)
+		if err != nil {
+			return domain.Homework{}, err
+		}
+		for _, sub := range subs {
+			if sub.Score != nil && *sub.Score > a.MaxScore {
+				var v domainerr.ValidationError
+				v.Add("max_score", fmt.Sprintf("must be at least %g: submissions are already graded higher", *sub.Score))
+				return domain.Homework{}, v.Err()
+			}
+		}
+	}
+
+	h.Title, h.Description, h.DueAt = in.Title, in.Description, in.DueAt
+	if in.AllowLate != nil {
+		h.AllowLate = *in.AllowLate
+	}
+	return s.repo.Update(ctx, a, h)
+}
+
+// Delete удаляет задание вместе со сдачами, оценками за него и файлами.
+func (s *HomeworkService) Delete(ctx context.Context, actor domain.Principal, id int64) error {
+	if _, _, err := s.manage(ctx, actor, id); err != nil {
+		return err
+	}
+	keys, err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		deleteStored(ctx, s.storage, key)
+	}
+	return nil
+}
+
+// AddAttachments прикладывает файлы к заданию.
+func (s *HomeworkService) AddAttachments(ctx context.Context, actor domain.Principal, id int64, uploads []Upload) ([]domain.File, error) {
+	h, _, err := s.manage(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+	prepared, err := prepareUploads(s.policy, uploads, maxHomeworkFiles, len(h.Attachments))
+	if err != nil {
+		return nil, err
+	}
+	return s.store(ctx, actor, h.ID, nil, prepared)
+}
+
+func (s *HomeworkService) DeleteAttachment(ctx context.Context, actor domain.Principal, id, fileID int64) error {
+	if _, _, err := s.manage(ctx, actor, id); err != nil {
+		return err
+	}
+	f, err := s.repo.GetFile(ctx, fileID)
+	if err != nil {
+		return err
+	}
+	if f.HomeworkID != id || f.SubmissionID != nil {
+		return domainerr.Newf(domainerr.ErrNotFound, "attachment %d", fileID)
+	}
+	return s.removeFile(ctx, f.File)
+}
+
+// AttachmentURL — подписанная ссылка на вложение задания.
+func (s *HomeworkService) AttachmentURL(ctx context.Context, actor domain.Principal, id, fileID int64) (string, error) {
+	h, err := s.Get(ctx, actor, id)
+	if err != nil {
+		return "", err
+	}
+	f, err := s.repo.GetFile(ctx, fileID)
+	if err != nil {
+		return "", err
+	}
+	if f.HomeworkID != h.ID || f.SubmissionID != nil {
+		return "", domainerr.Newf(domainerr.ErrNotFound, "attachment %d", fileID)
+	}
+	return s.storage.SignedURL(ctx, f.Key, f.Name, f.ContentType)
+}
+
+// Submissions — сдачи по заданию: администрации и учителю предмета.
+func (s *HomeworkService) Submissions(ctx context.Context, actor domain.Principal, id int64) ([]domain.Submission, error) {
+	h, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.Role.IsStaff() {
+		if _, err := checkSubjectAccess(ctx, s.assignments, actor, h.ClassID, h.SubjectID); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.ListSubmissions(ctx, id)
+}
+
+// MySubmission — сдача ученика-актора по заданию; не сдавал — ErrNotFound.
+func (s *HomeworkService) MySubmission(ctx context.Context, actor domain.Principal, id int64) (domain.Submission, error) {
+	if actor.Role != domain.RoleStudent || actor.StudentID == 0 {
+		return domain.Submission{}, domainerr.Newf(domainerr.ErrForbidden, "only students have submissions")
+	}
+	if _, err := s.Get(ctx, actor, id); err != nil {
+		return domain.Submission{}, err
+	}
+	return s.repo.SubmissionFor(ctx, id, actor.StudentID)
+}
+
+// Submit сдаёт или пересдаёт задание: текст заменяется, файлы добавляются к уже сданным.
+// После срока сдача помечается late; если задание не принимает опоздания — ErrConflict.
+// Проверенную сдачу изменить нельзя.
+func (s *HomeworkService) Submit(ctx context.Context, actor domain.Principal, id int64, text string, uploads []Upload) (domain.Submission, error) {
+	if actor.Role != domain.RoleStudent || actor.StudentID == 0 {
+		return domain.Submission{}, domainerr.Newf(domainerr.ErrForbidden, "only students submit homework")
+	}
+	h, err := s.Get(ctx, actor, id)
+	if err != nil {
+		return domain.Submission{}, err
+	}
+
+	prev, err := s.repo.SubmissionFor(ctx, id, actor.StudentID)
+	switch {
+	case errors.Is(err, domainerr.ErrNotFound):
+	case err != nil:
+		return domain.Submission{}, err
+	case prev.Graded():
+		return domain.Submission{}, domainerr.Newf(domainerr.ErrConflict, "submission is already graded")
+	}
+
+	text = strings.TrimSpace(text)
+	var v domainerr.ValidationError
+	if utf8.RuneCountInString(text) > maxHomeworkText {
+		v.Add("text", fmt.Sprintf("must be at most %d characters", maxHomeworkText))
+	}
+	if text == "" && len(uploads) == 0 && len(prev.Files) == 0 {
+		v.Add("text", "text or at least one file is required")
+	}
+	if err := v.Err(); err != nil {
+		return domain.Submission{}, err
+	}
+	prepared, err := prepareUploads(s.policy, uploads, maxHomeworkFiles, len(prev.Files))
+	if err != nil {
+		return domain.Submission{}, err
+	}
+
+	late := s.now().After(h.DueAt)
+	if late && !h.AllowLate {
+		return domain.Submission{}, domainerr.Newf(domainerr.ErrConflict, "deadline %s has passed and late submissions are not accepted",
+			h.DueAt.In(s.loc).Format(time.DateTime))
+	}
+
+	sub, err := s.repo.SaveSubmission(ctx, domain.Submission{HomeworkID: id, StudentID: actor.StudentID, Text: text, Late: late})
+	if err != nil {
+		return domain.Submission{}, err
+	}
+	if len(uploads) == 0 {
+		return sub, nil
+	}
+	if _, err := s.store(ctx, actor, id, &sub.ID, prepared); err != nil {
+		return domain.Submission{}, err
+	}
+	return s.repo.GetSubmission(ctx, sub.ID)
+}
+
+// Submission — сдача: ученику-автору, его представителям, администрации и учителям класса.
+func (s *HomeworkService) Submission(ctx context.Context, actor domain.Principal, id int64) (domain.Submission, error) {
+	sub, err := s.repo.GetSubmission(ctx, id)
+	if err != nil {
+		return domain.Submission{}, err
+	}
+	if err := s.checkSubmissionView(ctx, actor, sub); err != nil {
+		return domain.Submission{}, err
+	}
+	return sub, nil
+}
+
+// SubmissionFileURL — подписанная ссылка на файл сдачи.
+func (s *HomeworkService) SubmissionFileURL(ctx context.Context, actor domain.Principal, id, fileID int64) (string, error) {
+	sub, err := s.Submission(ctx, actor, id)
+	if err != nil {
+		return "", err
+	}
+	f, err := submissionFile(sub, fileID)
+	if err != nil {
+		return "", err
+	}
+	return s.storage.SignedURL(ctx, f.Key, f.Name, f.ContentType)
+}
+
+// DeleteSubmissionFile — ученик убирает файл из своей непроверенной сдачи.
+func (s *HomeworkService) DeleteSubmissionFile(ctx context.Context, actor domain.Principal, id, fileID int64) error {
+	sub, err := s.repo.GetSubmission(ctx, id)
+	if err != nil {
+		return err
+	}
+	if actor.Role != domain.RoleStudent || actor.StudentID != sub.StudentID {
+		return domainerr.Newf(domainerr.ErrForbidden, "only the author may change a submission")
+	}
+	if sub.Graded() {
+		return domainerr.Newf(domainerr.ErrConflict, "submission is already graded")
+	}
+	f, err := submissionFile(sub, fileID)
+	if err != nil {
+		return err
+	}
+	return s.removeFile(ctx, f)
+}
+
+// Grade проверяет сдачу: балл и отзыв попадают в журнал оценкой за работу задания.
+// Повторная проверка меняет оценку. В закрытом периоде — ErrForbidden.
+func (s *HomeworkService) Grade(ctx context.Context, actor domain.Principal, id int64, g domain.SubmissionGrade) (domain.Submission, error) {
+	sub, err := s.repo.GetSubmission(ctx, id)
+	if err != nil {
+		return domain.Submission{}, err
+	}
+	h, err := s.repo.Get(ctx, sub.HomeworkID)
+	if err != nil {
+		return domain.Submission{}, err
+	}
+	if _, err := checkSubjectAccess(ctx, s.assignments, actor, h.ClassID, h.SubjectID); err != nil {
+		return domain.Submission{}, err
+	}
+	cls, err := s.classes.Get(ctx, h.ClassID)
+	if err != nil {
+		return domain.Submission{}, err
+	}
+	if err := checkTermOpen(ctx, s.terms, cls.AcademicYear, h.Term); err != nil {
+		return domain.Submission{}, err
+	}
+
+	g.Feedback = strings.TrimSpace(g.Feedback)
+	var v domainerr.ValidationError
+	if g.Score < 0 || g.Score > h.MaxScore {
+		v.Add("score", fmt.Sprintf("must be between 0 and %g", h.MaxScore))
+	}
+	if utf8.RuneCountInString(g.Feedback) > maxFeedbackText {
+		v.Add("feedback", fmt.Sprintf("must be at most %d characters", maxFeedbackText))
+	}
+	if err := v.Err(); err != nil {
+		return domain.Submission{}, err
+	}
+
+	sub.Score, sub.Feedback, sub.GradedBy = &g.Score, g.Feedback, &actor.ExecID
+	return s.repo.Grade(ctx, sub, h.AssessmentID)
+}
+
+// manage загружает задание и проверяет право его менять: учитель предмета или администрация,
+// период работы в журнале открыт.
+func (s *HomeworkService) manage(ctx context.Context, actor domain.Principal, id int64) (domain.Homework, domain.Class, error) {
+	h, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return domain.Homework{}, domain.Class{}, err
+	}
+	if _, err := checkSubjectAccess(ctx, s.assignments, actor, h.ClassID, h.SubjectID); err != nil {
+		return domain.Homework{}, domain.Class{}, err
+	}
+	cls, err := s.classes.Get(ctx, h.ClassID)
+	if err != nil {
+		return domain.Homework{}, domain.Class{}, err
+	}
+	if err := checkTermOpen(ctx, s.terms, cls.AcademicYear, h.Term); err != nil {
+		return domain.Homework{}, domain.Class{}, err
+	}
+	return h, cls, nil
+}
+
+// checkView — задания класса видят администрация, учителя класса, его ученики и их представители.
+func (s *HomeworkService) checkView(ctx context.Context, actor domain.Principal, classID int64) error {
+	var (
+		ok  bool
+		err error
+	)
+	switch {
+	case actor.Role.IsStaff():
+		return nil
+	case actor.Role == domain.RoleTeacher && actor.TeacherID != 0:
+		ok, err = s.assignments.TeachesClass(ctx, actor.TeacherID, classID)
+	case actor.Role == domain.RoleStudent && actor.StudentID != 0:
+		var st domain.Student
+		if st, err = s.students.Get(ctx, actor.StudentID); err == nil {
+			ok = st.ClassID != nil && *st.ClassID == classID
+		}
+	case actor.Role == domain.RoleGuardian && actor.GuardianID != 0:
+		var wards []domain.Ward
+		if wards, err = s.guardians.ListWards(ctx, actor.GuardianID); err == nil {
+			for _, w := range wards {
+				ok = ok || (w.ClassID != nil && *w.ClassID == classID)
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domainerr.Newf(domainerr.ErrForbidden, "homework of another class")
+	}
+	return nil
+}
+
+func (s *HomeworkService) checkSubmissionView(ctx context.Context, actor domain.Principal, sub domain.Submission) error {
+	var (
+		ok  bool
+		err error
+	)
+	switch {
+	case actor.Role.IsStaff():
+		return nil
+	case actor.Role == domain.RoleStudent:
+		ok = actor.StudentID != 0 && actor.StudentID == sub.StudentID
+	case actor.Role == domain.RoleGuardian && actor.GuardianID != 0:
+		ok, err = s.guardians.IsGuardianOf(ctx, actor.GuardianID, sub.StudentID)
+	case actor.Role == domain.RoleTeacher && actor.TeacherID != 0:
+		var h domain.Homework
+		if h, err = s.repo.Get(ctx, sub.HomeworkID); err == nil {
+			ok, err = s.assignments.TeachesClass(ctx, actor.TeacherID, h.ClassID)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domainerr.Newf(domainerr.ErrForbidden, "submission of another student")
+	}
+	return nil
+}
+
+// assessment собирает работу в журнале для задания и проверяет поля ввода.
+// Категория по умолчанию — homework, период — тот, на который приходится срок сдачи.
+func (s *HomeworkService) assessment(ctx context.Context, cls domain.Class, in domain.HomeworkInput) (domain.Assessment, error) {
+	a := domain.Assessment{
+		ClassID:    cls.ID,
+		SubjectID:  in.SubjectID,
+		CategoryID: in.CategoryID,
+		Term:       in.Term,
+		Title:      in.Title,
+		MaxScore:   in.MaxScore,
+	}
+	if !in.DueAt.IsZero() {
+		a.Date = dateOnly(in.DueAt.In(s.loc))
+	}
+
+	var v domainerr.ValidationError
+	if in.Title == "" {
+		v.Add("title", "is required")
+	} else if utf8.RuneCountInString(in.Title) > maxHomeworkTitle {
+		v.Add("title", fmt.Sprintf("must be at most %d characters", maxHomeworkTitle))
+	}
+	if utf8.RuneCountInString(in.Description) > maxHomeworkText {
+		v.Add("description", fmt.Sprintf("must be at most %d characters", maxHomeworkText))
+	}
+	if in.DueAt.IsZero() {
+		v.Add("due_at", "is required")
+	}
+	if a.MaxScore <= 0 {
+		v.Add("max_score", "must be > 0")
+	}
+
+	if a.Term == 0 && !a.Date.IsZero() {
+		t, ok, err := termOn(ctx, s.terms, a.Date)
+		if err != nil {
+			return domain.Assessment{}, err
+		}
+		if ok && t.AcademicYear == cls.AcademicYear {
+			a.Term = t.Number
+		}
+	}
+	if a.Term < domain.MinTerm || a.Term > domain.MaxTerm {
+		v.Add("term", fmt.Sprintf("must be between %d and %d (due date is outside the class year terms)", domain.MinTerm, domain.MaxTerm))
+	}
+
+	if a.CategoryID == 0 {
+		cats, err := s.grades.ListCategories(ctx)
+		if err != nil {
+			return domain.Assessment{}, err
+		}
+		for _, c := range cats {
+			if c.Code == homeworkCategory {
+				a.CategoryID = c.ID
+			}
+		}
+		if a.CategoryID == 0 {
+			v.Add("category_id", "is required: there is no "+homeworkCategory+" category")
+		}
+	} else if err := exists(&v, "category_id", func() error {
+		_, err := s.grades.GetCategory(ctx, a.CategoryID)
+		return err
+	}); err != nil {
+		return domain.Assessment{}, err
+	}
+
+	return a, v.Err()
+}
+
+// store записывает проверенные файлы в хранилище и регистрирует их у задания (submissionID == nil)
+// или у сдачи. При ошибке уже сохранённые файлы остаются.
+func (s *HomeworkService) store(ctx context.Context, actor domain.Principal, homeworkID int64, submissionID *int64, uploads []preparedUpload) ([]domain.File, error) {
+	prefix := "homework/" + strconv.FormatInt(homeworkID, 10)
+	out := make([]domain.File, 0, len(uploads))
+	for _, u := range uploads {
+		f, err := putUpload(ctx, s.storage, prefix, u)
+		if err != nil {
+			return nil, err
+		}
+		f.UploadedBy = &actor.ExecID
+
+		saved, err := s.repo.AddFile(ctx, domain.HomeworkFile{File: f, HomeworkID: homeworkID, SubmissionID: submissionID})
+		if err != nil {
+			deleteStored(ctx, s.storage, f.Key)
+			return nil, err
+		}
+		out = append(out, saved.File)
+	}
+	return out, nil
+}
+
+func (s *HomeworkService) removeFile(ctx context.Context, f domain.File) error {
+	if err := s.repo.DeleteFile(ctx, f.ID); err != nil {
+		return err
+	}
+	deleteStored(ctx, s.storage, f.Key)
+	return nil
+}
+
+func normalizeHomework(in *domain.HomeworkInput) {
+	in.Title = strings.TrimSpace(in.Title)
+	in.Description = strings.TrimSpace(in.Description)
+}
+
+func submissionFile(sub domain.Submission, fileID int64) (domain.File, error) {
+	for _, f := range sub.Files {
+		if f.ID == fileID {
+			return f, nil
+		}
+	}
+	return domain.File{}, domainerr.Newf(domainerr.ErrNotFound, "file %d of submission %d", fileID, sub.ID)
+}
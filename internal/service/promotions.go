@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+type PromotionRepository interface {
+	Apply(ctx context.Context, plan domain.PromotionPlan, executedBy *int64) (domain.PromotionPlan, error)
+	List(ctx context.Context) ([]domain.PromotionPlan, error)
+	Get(ctx context.Context, id int64) (domain.PromotionPlan, error)
+}
+
+// PromotionService — перевод учеников в следующий учебный год.
+type PromotionService struct {
+	repo     PromotionRepository
+	classes  ClassRepository
+	students StudentRepository
+}
+
+func NewPromotionService(repo PromotionRepository, classes ClassRepository, students StudentRepository) *PromotionService {
+	return &PromotionService{repo: repo, classes: classes, students: students}
+}
+
+func (s *PromotionService) Runs(ctx context.Context) ([]domain.PromotionPlan, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *PromotionService) Run(ctx context.Context, id int64) (domain.PromotionPlan, error) {
+	if id <= 0 {
+		return domain.PromotionPlan{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Get(ctx, id)
+}
+
+// Promote строит план перевода и, если это не dry-run, выполняет его одной транзакцией.
+// Повторный перевод из того же учебного года — ErrConflict.
+func (s *PromotionService) Promote(ctx context.Context, actor domain.Principal, req domain.PromotionRequest) (domain.PromotionPlan, error) {
+	plan, err := s.plan(ctx, req)
+	if err != nil || plan.DryRun {
+		return plan, err
+	}
+
+	var executedBy *int64
+	if actor.ExecID != 0 {
+		executedBy = &actor.ExecID
+	}
+	return s.repo.Apply(ctx, plan, executedBy)
+}
+
+// promotionTarget — класс нового года, куда попадает ученик: существующий (ID) или создаваемый.
+type promotionTarget struct {
+	id    int64
+	name  string
+	grade int
+}
+
+func (s *PromotionService) plan(ctx context.Context, req domain.PromotionRequest) (domain.PromotionPlan, error) {
+	if req.ToYear == 0 {
+		req.ToYear = req.FromYear + 1
+	}
+	if req.FinalGrade == 0 {
+		req.FinalGrade = domain.MaxGrade
+	}
+
+	var v domainerr.ValidationError
+	if req.FromYear < 2000 || req.FromYear > 2100 {
+		v.Add("from_year", "must be between 2000 and 2100")
+	}
+	if req.ToYear <= req.FromYear {
+		v.Add("to_year", "must be after from_year")
+	}
+	if req.FinalGrade < domain.MinGrade || req.FinalGrade > domain.MaxGrade {
+		v.Add("final_grade", fmt.Sprintf("must be between %d and %d", domain.MinGrade, domain.MaxGrade))
+	}
+	if err := v.Err(); err != nil {
+		return domain.PromotionPlan{}, err
+	}
+
+	from, err := s.classes.List(ctx, domain.ClassFilter{AcademicYear: req.FromYear, Limit: maxListLimit})
+	if err != nil {
+		return domain.PromotionPlan{}, err
+	}
+	to, err := s.classes.List(ctx, domain.ClassFilter{AcademicYear: req.ToYear, Limit: maxListLimit})
+	if err != nil {
+		return domain.PromotionPlan{}, err
+	}
+
+	fromByID := make(map[int64]domain.Class, len(from))
+	for _, c := range from {
+		fromByID[c.ID] = c
+	}
+	toByID := make(map[int64]domain.Class, len(to))
+	toByName := make(map[string]domain.Class, len(to))
+	for _, c := range to {
+		toByID[c.ID] = c
+		toByName[strings.ToLower(c.Name)] = c
+	}
+
+	for src, dst := range req.ClassMap {
+		field := "class_map." + strconv.FormatInt(src, 10)
+		sc, ok := fromByID[src]
+		if !ok {
+			v.Add(field, fmt.Sprintf("class %d is not a class of %d", src, req.FromYear))
+			continue
+		}
+		dc, ok := toByID[dst]
+		switch {
+		case !ok:
+			v.Add(field, fmt.Sprintf("class %d is not a class of %d", dst, req.ToYear))
+		case dc.Grade != sc.Grade+1:
+			v.Add(field, fmt.Sprintf("target class must be grade %d", sc.Grade+1))
+		}
+	}
+	if err := v.Err(); err != nil {
+		return domain.PromotionPlan{}, err
+	}
+
+	plan := domain.PromotionPlan{
+		FromYear:   req.FromYear,
+		ToYear:     req.ToYear,
+		FinalGrade: req.FinalGrade,
+		DryRun:     req.DryRun,
+		NewClasses: make([]domain.PromotionClass, 0),
+		Items:      make([]domain.PromotionItem, 0),
+	}
+
+	// target находит класс нового года по имени или добавляет его в список создаваемых.
+	target := func(src domain.Class, name string, grade int) promotionTarget {
+		if c, ok := toByName[strings.ToLower(name)]; ok {
+			return promotionTarget{id: c.ID, name: c.Name, grade: c.Grade}
+		}
+		c := domain.Class{Name: name, Grade: grade, AcademicYear: req.ToYear, HomeroomTeacherID: src.HomeroomTeacherID}
+		toByName[strings.ToLower(name)] = c
+		plan.NewClasses = append(plan.NewClasses, domain.PromotionClass{Name: name, Grade: grade, HomeroomTeacherID: src.HomeroomTeacherID})
+		return promotionTarget{name: name, grade: grade}
+	}
+
+	hold := make(map[int64]bool, len(req.HoldBack))
+	for _, id := range req.HoldBack {
+		hold[id] = true
+	}
+	seen := make(map[int64]bool)
+
+	for _, c := range from {
+		students, err := s.students.List(ctx, domain.StudentFilter{ClassID: c.ID, Limit: maxListLimit})
+		if err != nil {
+			return domain.PromotionPlan{}, err
+		}
+		if len(students) == 0 {
+			continue
+		}
+
+		for _, st := range students {
+			seen[st.ID] = true
+			it := domain.PromotionItem{
+				StudentID:   st.ID,
+				StudentName: strings.TrimSpace(st.FirstName + " " + st.LastName),
+				FromClassID: c.ID,
+				FromClass:   c.Name,
+				FromGrade:   c.Grade,
+			}
+
+			var t promotionTarget
+			switch {
+			case st.Status != domain.StudentEnrolled && st.Status != domain.StudentSuspended:
+				it.Action, it.Reason = domain.PromotionSkip, "status "+string(st.Status)
+			case hold[st.ID]:
+				// Второгодник переходит в класс нового года с тем же именем и параллелью.
+				it.Action = domain.PromotionHoldBack
+				t = target(c, c.Name, c.Grade)
+			case c.Grade >= req.FinalGrade && st.Status == domain.StudentSuspended:
+				it.Action, it.Reason = domain.PromotionSkip, "suspended students cannot graduate"
+			case c.Grade >= req.FinalGrade:
+				it.Action = domain.PromotionGraduate
+			default:
+				it.Action = domain.PromotionPromote
+				if dst, ok := req.ClassMap[c.ID]; ok {
+					dc := toByID[dst]
+					t = promotionTarget{id: dc.ID, name: dc.Name, grade: dc.Grade}
+				} else {
+					t = target(c, nextClassName(c.Name, c.Grade), c.Grade+1)
+				}
+			}
+			it.ToClassID, it.ToClass, it.ToGrade = t.id, t.name, t.grade
+
+			switch it.Action {
+			case domain.PromotionPromote:
+				plan.Summary.Promoted++
+			case domain.PromotionHoldBack:
+				plan.Summary.HeldBack++
+			case domain.PromotionGraduate:
+				plan.Summary.Graduated++
+			default:
+				plan.Summary.Skipped++
+			}
+			plan.Items = append(plan.Items, it)
+		}
+	}
+
+	for i, id := range req.HoldBack {
+		if !seen[id] {
+			v.Add("hold_back["+strconv.Itoa(i)+"]", fmt.Sprintf("student %d is not in a class of %d", id, req.FromYear))
+		}
+	}
+	if err := v.Err(); err != nil {
+		return domain.PromotionPlan{}, err
+	}
+
+	slices.SortStableFunc(plan.NewClasses, func(a, b domain.PromotionClass) int {
+		if a.Grade != b.Grade {
+			return a.Grade - b.Grade
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return plan, nil
+}
+
+// nextClassName — имя класса на следующий год: ведущий номер параллели увеличивается ("7B" → "8B").
+// Имена без номера параллели ("Blue") переносятся как есть.
+func nextClassName(name string, grade int) string {
+	if rest, ok := strings.CutPrefix(name, strconv.Itoa(grade)); ok {
+		return strconv.Itoa(grade+1) + rest
+	}
+	return name
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+// maxReportComment — ограничение на длину комментария в табеле.
+const maxReportComment = 1000
+
+type ReportCardRepository interface {
+	ListComments(ctx context.Context, termID int64, studentIDs []int64) ([]domain.ReportCardComment, error)
+	SaveComment(ctx context.Context, c domain.ReportCardComment) (domain.ReportCardComment, error)
+	DeleteComment(ctx context.Context, studentID, termID int64, subjectID *int64) error
+}
+
+// ReportCardService собирает табели: средние по предметам, посещаемость и комментарии за период.
+type ReportCardService struct {
+	repo        ReportCardRepository
+	grades      GradeRepository
+	attendance  AttendanceRepository
+	students    StudentRepository
+	classes     ClassRepository
+	assignments AssignmentRepository
+	teachers    TeacherRepository
+	terms       TermRepository
+	now         func() time.Time
+}
+
+func NewReportCardService(repo ReportCardRepository, grades GradeRepository, attendance AttendanceRepository, students StudentRepository,
+	classes ClassRepository, assignments AssignmentRepository, teachers TeacherRepository, terms TermRepository) *ReportCardService {
+	return &ReportCardService{
+		repo: repo, grades: grades, attendance: attendance, students: students,
+		classes: classes, assignments: assignments, teachers: teachers, terms: terms, now: time.Now,
+	}
+}
+
+// StudentCard — табель ученика за период termID. Класс в табеле указывается, если ученик
+// учится в классе того же учебного года.
+func (s *ReportCardService) StudentCard(ctx context.Context, studentID, termID int64) (domain.ReportCard, error) {
+	if termID <= 0 {
+		return domain.ReportCard{}, domainerr.Newf(domainerr.ErrBadInput, "invalid term id")
+	}
+	st, err := s.students.Get(ctx, studentID)
+	if err != nil {
+		return domain.ReportCard{}, err
+	}
+	term, err := s.terms.GetTerm(ctx, termID)
+	if err != nil {
+		return domain.ReportCard{}, err
+	}
+
+	var cls *domain.Class
+	if st.ClassID != nil {
+		c, err := s.classes.Get(ctx, *st.ClassID)
+		if err != nil {
+			return domain.ReportCard{}, err
+		}
+		if c.AcademicYear == term.AcademicYear {
+			cls = &c
+		}
+	}
+
+	att, err := s.attendance.StudentSummary(ctx, st.ID, term.Range())
+	if err != nil {
+		return domain.ReportCard{}, err
+	}
+	comments, err := s.repo.ListComments(ctx, term.ID, []int64{st.ID})
+	if err != nil {
+		return domain.ReportCard{}, err
+	}
+	b, err := s.newBuilder(ctx, cls, term)
+	if err != nil {
+		return domain.ReportCard{}, err
+	}
+
+	return b.card(ctx, st, att, comments)
+}
+
+// ClassCards — табели всех учеников класса за период. Период должен относиться к учебному году класса.
+func (s *ReportCardService) ClassCards(ctx context.Context, classID, termID int64) (domain.Class, []domain.ReportCard, error) {
+	if termID <= 0 {
+		return domain.Class{}, nil, domainerr.Newf(domainerr.ErrBadInput, "invalid term id")
+	}
+	cls, err := s.classes.Get(ctx, classID)
+	if err != nil {
+		return domain.Class{}, nil, err
+	}
+	term, err := s.terms.GetTerm(ctx, termID)
+	if err != nil {
+		return domain.Class{}, nil, err
+	}
+	if term.AcademicYear != cls.AcademicYear {
+		var v domainerr.ValidationError
+		v.Add("term", fmt.Sprintf("term belongs to academic year %d, class to %d", term.AcademicYear, cls.AcademicYear))
+		return domain.Class{}, nil, v.Err()
+	}
+
+	students, err := s.students.List(ctx, domain.StudentFilter{ClassID: cls.ID, Limit: maxListLimit})
+	if err != nil {
+		return domain.Class{}, nil, err
+	}
+	ids := make([]int64, len(students))
+	for i, st := range students {
+		ids[i] = st.ID
+	}
+
+	summaries, err := s.attendance.ClassSummary(ctx, cls.ID, term.Range())
+	if err != nil {
+		return domain.Class{}, nil, err
+	}
+	attByStudent := make(map[int64]domain.AttendanceSummary, len(summaries))
+	for _, a := range summaries {
+		attByStudent[a.StudentID] = a
+	}
+
+	comments, err := s.repo.ListComments(ctx, term.ID, ids)
+	if err != nil {
+		return domain.Class{}, nil, err
+	}
+	commentsByStudent := make(map[int64][]domain.ReportCardComment)
+	for _, c := range comments {
+		commentsByStudent[c.StudentID] = append(commentsByStudent[c.StudentID], c)
+	}
+
+	b, err := s.newBuilder(ctx, &cls, term)
+	if err != nil {
+		return domain.Class{}, nil, err
+	}
+
+	cards := make([]domain.ReportCard, 0, len(students))
+	for _, st := range students {
+		att := attByStudent[st.ID]
+		att.StudentID, att.StudentName = 0, ""
+		card, err := b.card(ctx, st, att, commentsByStudent[st.ID])
+		if err != nil {
+			return domain.Class{}, nil, err
+		}
+		cards = append(cards, card)
+	}
+	sort.SliceStable(cards, func(i, j int) bool { return cards[i].StudentName < cards[j].StudentName })
+
+	return cls, cards, nil
+}
+
+// SaveComment сохраняет комментарий в табеле. Учитель пишет комментарий только по своему
+// предмету в классе ученика, общий комментарий — только классный руководитель.
+func (s *ReportCardService) SaveComment(ctx context.Context, actor domain.Principal, c domain.ReportCardComment) (domain.ReportCardComment, error) {
+	c.Text = strings.TrimSpace(c.Text)
+
+	var v domainerr.ValidationError
+	if c.Text == "" {
+		v.Add("text", "is required")
+	} else if utf8.RuneCountInString(c.Text) > maxReportComment {
+		v.Add("text", fmt.Sprintf("must be at most %d characters", maxReportComment))
+	}
+	if c.SubjectID != nil && *c.SubjectID <= 0 {
+		v.Add("subject_id", "must be a positive id")
+	}
+	if err := v.Err(); err != nil {
+		return domain.ReportCardComment{}, err
+	}
+
+	if err := s.checkCommentAccess(ctx, actor, c.StudentID, c.TermID, c.SubjectID); err != nil {
+		return domain.ReportCardComment{}, err
+	}
+	if actor.ExecID != 0 {
+		c.AuthorID = &actor.ExecID
+	}
+	return s.repo.SaveComment(ctx, c)
+}
+
+func (s *ReportCardService) DeleteComment(ctx context.Context, actor domain.Principal, studentID, termID int64, subjectID *int64) error {
+	if err := s.checkCommentAccess(ctx, actor, studentID, termID, subjectID); err != nil {
+		return err
+	}
+	return s.repo.DeleteComment(ctx, studentID, termID, subjectID)
+}
+
+func (s *ReportCardService) checkCommentAccess(ctx context.Context, actor domain.Principal, studentID, termID int64, subjectID *int64) error {
+	if termID <= 0 {
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid term id")
+	}
+	st, err := s.students.Get(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	term, err := s.terms.GetTerm(ctx, termID)
+	if err != nil {
+		return err
+	}
+	if actor.Role != domain.RoleTeacher {
+		return nil
+	}
+
+	forbidden := domainerr.Newf(domainerr.ErrForbidden, "only the subject teacher or the homeroom teacher may comment")
+	if st.ClassID == nil {
+		return forbidden
+	}
+	cls, err := s.classes.Get(ctx, *st.ClassID)
+	if err != nil {
+		return err
+	}
+	if cls.AcademicYear != term.AcademicYear {
+		return forbidden
+	}
+
+	if subjectID == nil {
+		if cls.HomeroomTeacherID != nil && *cls.HomeroomTeacherID == actor.TeacherID {
+			return nil
+		}
+		return forbidden
+	}
+	asg, err := s.assignments.Get(ctx, cls.ID, *subjectID)
+	if err != nil || asg.TeacherID != actor.TeacherID {
+		return forbidden
+	}
+	return nil
+}
+
+// cardBuilder — общие для класса данные табеля: предметы с учителями и классный руководитель.
+type cardBuilder struct {
+	s        *ReportCardService
+	cls      *domain.Class
+	term     domain.Term
+	homeroom string
+	subjects []domain.Assignment
+	now      time.Time
+}
+
+func (s *ReportCardService) newBuilder(ctx context.Context, cls *domain.Class, term domain.Term) (*cardBuilder, error) {
+	b := &cardBuilder{s: s, cls: cls, term: term, now: s.now()}
+	if cls == nil {
+		return b, nil
+	}
+
+	asg, err := s.assignments.ListByClass(ctx, cls.ID)
+	if err != nil {
+		return nil, err
+	}
+	b.subjects = asg
+
+	if cls.HomeroomTeacherID != nil {
+		t, err := s.teachers.Get(ctx, *cls.HomeroomTeacherID)
+		if err != nil {
+			return nil, err
+		}
+		b.homeroom = t.LastName + " " + t.FirstName
+	}
+	return b, nil
+}
+
+// card — табель ученика: предметы класса и все предметы, по которым у него есть оценки за период.
+func (b *cardBuilder) card(ctx context.Context, st domain.Student, att domain.AttendanceSummary, comments []domain.ReportCardComment) (domain.ReportCard, error) {
+	avgs, err := b.s.grades.StudentAverages(ctx, st.ID, domain.GradeFilter{AcademicYear: b.term.AcademicYear, Term: b.term.Number})
+	if err != nil {
+		return domain.ReportCard{}, err
+	}
+
+	card := domain.ReportCard{
+		StudentID:       st.ID,
+		StudentName:     st.LastName + " " + st.FirstName,
+		HomeroomTeacher: b.homeroom,
+		Term:            b.term,
+		Subjects:        make([]domain.ReportCardSubject, 0, len(b.subjects)),
+		Attendance:      att,
+		GeneratedAt:     b.now,
+	}
+	card.Attendance.Rate = attendanceRate(att)
+	if b.cls != nil {
+		card.ClassID, card.ClassName = b.cls.ID, b.cls.Name
+	}
+
+	bySubject := make(map[int64]int)
+	for _, a := range b.subjects {
+		bySubject[a.SubjectID] = len(card.Subjects)
+		card.Subjects = append(card.Subjects, domain.ReportCardSubject{SubjectID: a.SubjectID, SubjectName: a.SubjectName, TeacherName: a.TeacherName})
+	}
+
+	var sum float64
+	for _, a := range avgs {
+		i, ok := bySubject[a.SubjectID]
+		if !ok {
+			i = len(card.Subjects)
+			bySubject[a.SubjectID] = i
+			card.Subjects = append(card.Subjects, domain.ReportCardSubject{SubjectID: a.SubjectID, SubjectName: a.SubjectName})
+		}
+		avg := a.Average
+		card.Subjects[i].Average = &avg
+		card.Subjects[i].GradesCount = a.GradesCount
+		sum += a.Average
+	}
+	if len(avgs) > 0 {
+		overall := math.Round(sum/float64(len(avgs))*100) / 100
+		card.Average = &overall
+	}
+
+	for _, c := range comments {
+		if c.SubjectID == nil {
+			card.Comment = c.Text
+			continue
+		}
+		if i, ok := bySubject[*c.SubjectID]; ok {
+			card.Subjects[i].Comment = c.Text
+		}
+	}
+
+	sort.SliceStable(card.Subjects, func(i, j int) bool { return card.Subjects[i].SubjectName < card.Subjects[j].SubjectName })
+	return card, nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+type RoomRepository interface {
+	List(ctx context.Context) ([]domain.Room, error)
+	Get(ctx context.Context, id int64) (domain.Room, error)
+	Create(ctx context.Context, rm domain.Room) (domain.Room, error)
+	Update(ctx context.Context, rm domain.Room) (domain.Room, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type RoomService struct {
+	repo RoomRepository
+}
+
+func NewRoomService(repo RoomRepository) *RoomService {
+	return &RoomService{repo: repo}
+}
+
+func (s *RoomService) List(ctx context.Context) ([]domain.Room, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *RoomService) Get(ctx context.Context, id int64) (domain.Room, error) {
+	if id <= 0 {
+		return domain.Room{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Get(ctx, id)
+}
+
+func (s *RoomService) Create(ctx context.Context, rm domain.Room) (domain.Room, error) {
+	normalizeRoom(&rm)
+	if err := validateRoom(rm); err != nil {
+		return domain.Room{}, err
+	}
+	return s.repo.Create(ctx, rm)
+}
+
+func (s *RoomService) Update(ctx context.Context, rm domain.Room) (domain.Room, error) {
+	if rm.ID <= 0 {
+		return domain.Room{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	normalizeRoom(&rm)
+	if err := validateRoom(rm); err != nil {
+		return domain.Room{}, err
+	}
+	return s.repo.Update(ctx, rm)
+}
+
+func (s *RoomService) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func normalizeRoom(rm *domain.Room) {
+	rm.Code = strings.TrimSpace(rm.Code)
+	rm.Name = strings.TrimSpace(rm.Name)
+}
+
+func validateRoom(rm domain.Room) error {
+	var v domainerr.ValidationError
+	if rm.Code == "" {
+		v.Add("code", "is required")
+	}
+	if rm.Capacity < 0 {
+		v.Add("capacity", "must be >= 0")
+	}
+	return v.Err()
+}
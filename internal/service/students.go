@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+type StudentRepository interface {
+	List(ctx context.Context, f domain.StudentFilter) ([]domain.Student, error)
+	Get(ctx context.Context, id int64) (domain.Student, error)
+	Create(ctx context.Context, s domain.Student) (domain.Student, error)
+	Update(ctx context.Context, s domain.Student, prevStatus domain.StudentStatus) (domain.Student, error)
+	Delete(ctx context.Context, id int64) error
+
+	GetPhoto(ctx context.Context, studentID int64) (domain.StudentPhoto, error)
+	SetPhoto(ctx context.Context, p domain.StudentPhoto) (domain.StudentPhoto, string, error)
+	DeletePhoto(ctx context.Context, studentID int64) (string, error)
+}
+
+type StudentService struct {
+	repo    StudentRepository
+	classes ClassRepository
+	storage FileStorage
+	photos  UploadPolicy
+}
+
+func NewStudentService(repo StudentRepository, classes ClassRepository, storage FileStorage, photos UploadPolicy) *StudentService {
+	return &StudentService{repo: repo, classes: classes, storage: storage, photos: photos}
+}
+
+func (s *StudentService) List(ctx context.Context, f domain.StudentFilter) ([]domain.Student, error) {
+	if f.Status != "" && !f.Status.Valid() {
+		return nil, domainerr.Newf(domainerr.ErrBadInput, "unknown status %q", f.Status)
+	}
+	f.Limit, f.Offset = normalizePage(f.Limit, f.Offset)
+	return s.repo.List(ctx, f)
+}
+
+func (s *StudentService) Get(ctx context.Context, id int64) (domain.Student, error) {
+	if id <= 0 {
+		return domain.Student{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Get(ctx, id)
+}
+
+// Create регистрирует ученика. Новый ученик может быть только заявителем или сразу зачисленным.
+func (s *StudentService) Create(ctx context.Context, st domain.Student) (domain.Student, error) {
+	if st.Status == "" {
+		st.Status = domain.StudentApplicant
+	}
+	if st.Status != domain.StudentApplicant && st.Status != domain.StudentEnrolled {
+		return domain.Student{}, domainerr.Newf(domainerr.ErrBadInput, "new student must be %s or %s",
+			domain.StudentApplicant, domain.StudentEnrolled)
+	}
+
+	normalizeStudent(&st)
+	if err := s.applyClass(ctx, &st); err != nil {
+		return domain.Student{}, err
+	}
+	if err := validateStudent(st); err != nil {
+		return domain.Student{}, err
+	}
+	return s.repo.Create(ctx, st)
+}
+
+// Update — полная замена (PUT). Смена статуса проходит через машину состояний.
+func (s *StudentService) Update(ctx context.Context, st domain.Student) (domain.Student, error) {
+	cur, err := s.Get(ctx, st.ID)
+	if err != nil {
+		return domain.Student{}, err
+	}
+	if st.Status == "" {
+		st.Status = cur.Status
+	}
+	return s.save(ctx, cur, st)
+}
+
+// Patch — частичное обновление поверх текущей записи.
+func (s *StudentService) Patch(ctx context.Context, id int64, p domain.StudentPatch) (domain.Student, error) {
+	cur, err := s.Get(ctx, id)
+	if err != nil {
+		return domain.Student{}, err
+	}
+
+	st := cur
+	if p.FirstName != nil {
+		st.FirstName = *p.FirstName
+	}
+	if p.LastName != nil {
+		st.LastName = *p.LastName
+	}
+	if p.Email != nil {
+		st.Email = *p.Email
+	}
+	if p.BirthDate != nil {
+		st.BirthDate = p.BirthDate
+	}
+	if p.Grade != nil {
+		st.Grade = *p.Grade
+	}
+	if p.ClassID.Set {
+		st.ClassID = p.ClassID.Value
+	}
+	if p.Status != nil {
+		st.Status = *p.Status
+	}
+
+	return s.save(ctx, cur, st)
+}
+
+// Transition переводит ученика в новый статус.
+func (s *StudentService) Transition(ctx context.Context, id int64, next domain.StudentStatus) (domain.Student, error) {
+	cur, err := s.Get(ctx, id)
+	if err != nil {
+		return domain.Student{}, err
+	}
+
+	st := cur
+	st.Status = next
+	return s.save(ctx, cur, st)
+}
+
+// Delete удаляет ученика; фотография удаляется из хранилища после записи в БД.
+func (s *StudentService) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	photo, err := s.repo.GetPhoto(ctx, id)
+	if err != nil && !errors.Is(err, domainerr.ErrNotFound) {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	if photo.Key != "" {
+		deleteStored(ctx, s.storage, photo.Key)
+	}
+	return nil
+}
+
+// SetPhoto загружает или заменяет фотографию ученика; прежний файл удаляется из хранилища.
+func (s *StudentService) SetPhoto(ctx context.Context, actor domain.Principal, id int64, photo Upload) (domain.StudentPhoto, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return domain.StudentPhoto{}, err
+	}
+	prepared, err := prepareUploads(s.photos, []Upload{photo}, 1, 0)
+	if err != nil {
+		return domain.StudentPhoto{}, err
+	}
+
+	f, err := putUpload(ctx, s.storage, "students/"+strconv.FormatInt(id, 10), prepared[0])
+	if err != nil {
+		return domain.StudentPhoto{}, err
+	}
+	saved, prev, err := s.repo.SetPhoto(ctx, domain.StudentPhoto{
+		StudentID: id, Key: f.Key, ContentType: f.ContentType, Size: f.Size, UploadedBy: &actor.ExecID,
+	})
+	if err != nil {
+		deleteStored(ctx, s.storage, f.Key)
+		return domain.StudentPhoto{}, err
+	}
+	if prev != "" {
+		deleteStored(ctx, s.storage, prev)
+	}
+	return saved, nil
+}
+
+// PhotoURL — подписанная ссылка на фотографию ученика; нет фотографии — ErrNotFound.
+func (s *StudentService) PhotoURL(ctx context.Context, id int64) (string, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return "", err
+	}
+	p, err := s.repo.GetPhoto(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return s.storage.SignedURL(ctx, p.Key, "", p.ContentType)
+}
+
+func (s *StudentService) DeletePhoto(ctx context.Context, id int64) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	key, err := s.repo.DeletePhoto(ctx, id)
+	if err != nil {
+		return err
+	}
+	deleteStored(ctx, s.storage, key)
+	return nil
+}
+
+func (s *StudentService) save(ctx context.Context, cur, next domain.Student) (domain.Student, error) {
+	if !next.Status.Valid() {
+		return domain.Student{}, domainerr.Newf(domainerr.ErrBadInput, "unknown status %q", next.Status)
+	}
+	if !cur.Status.CanTransitionTo(next.Status) {
+		return domain.Student{}, domainerr.Newf(domainerr.ErrBadInput, "cannot change status from %s to %s",
+			cur.Status, next.Status)
+	}
+	if cur.ClassID != nil && next.ClassID == nil && next.Status.InClass() {
+		return domain.Student{}, domainerr.Newf(domainerr.ErrBadInput, "cannot leave a student without a class while %s",
+			next.Status)
+	}
+
+	normalizeStudent(&next)
+	if err := s.applyClass(ctx, &next); err != nil {
+		return domain.Student{}, err
+	}
+	if err := validateStudent(next); err != nil {
+		return domain.Student{}, err
+	}
+	return s.repo.Update(ctx, next, cur.Status)
+}
+
+// applyClass проверяет класс ученика и берёт из него параллель (grade).
+func (s *StudentService) applyClass(ctx context.Context, st *domain.Student) error {
+	if st.ClassID == nil {
+		return nil
+	}
+
+	c, err := s.classes.Get(ctx, *st.ClassID)
+	if errors.Is(err, domainerr.ErrNotFound) {
+		var v domainerr.ValidationError
+		v.Add("class_id", "class does not exist")
+		return v.Err()
+	}
+	if err != nil {
+		return err
+	}
+
+	st.Grade = c.Grade
+	return nil
+}
+
+func normalizeStudent(s *domain.Student) {
+	s.FirstName = strings.TrimSpace(s.FirstName)
+	s.LastName = strings.TrimSpace(s.LastName)
+	s.Email = strings.ToLower(strings.TrimSpace(s.Email))
+}
+
+func validateStudent(s domain.Student) error {
+	var v domainerr.ValidationError
+	if s.FirstName == "" {
+		v.Add("first_name", "is required")
+	}
+	if s.LastName == "" {
+		v.Add("last_name", "is required")
+	}
+	if s.Email != "" {
+		if _, err := mail.ParseAddress(s.Email); err != nil {
+			v.Add("email", "must be a valid email address")
+		}
+	}
+	if s.Grade < domain.MinGrade || s.Grade > domain.MaxGrade {
+		v.Add("grade", fmt.Sprintf("must be between %d and %d", domain.MinGrade, domain.MaxGrade))
+	}
+	return v.Err()
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+// fakeStudentRepo хранит одного ученика; остальные методы интерфейса не вызываются.
+type fakeStudentRepo struct {
+	StudentRepository
+	st domain.Student
+}
+
+func (r *fakeStudentRepo) Get(_ context.Context, id int64) (domain.Student, error) {
+	if id != r.st.ID {
+		return domain.Student{}, domainerr.ErrNotFound
+	}
+	return r.st, nil
+}
+
+func (r *fakeStudentRepo) Update(_ context.Context, s domain.Student, _ domain.StudentStatus) (domain.Student, error) {
+	r.st = s
+	return s, nil
+}
+
+type fakeClassRepo struct {
+	ClassRepository
+}
+
+func (fakeClassRepo) Get(_ context.Context, id int64) (domain.Class, error) {
+	if id != 7 && id != 8 {
+		return domain.Class{}, domainerr.ErrNotFound
+	}
+	return domain.Class{ID: id, Grade: 5}, nil
+}
+
+func TestStudentPatchClass(t *testing.T) {
+	classID, otherID := int64(7), int64(8)
+
+	tests := []struct {
+		name      string
+		status    domain.StudentStatus
+		body      string
+		wantClass *int64
+		wantErr   error
+	}{
+		{name: "absent class_id keeps the class", status: domain.StudentEnrolled, body: `{"first_name":"Anna"}`, wantClass: &classID},
+		{name: "move to another class", status: domain.StudentEnrolled, body: `{"class_id":8}`, wantClass: &otherID},
+		{name: "unknown class", status: domain.StudentEnrolled, body: `{"class_id":9}`, wantErr: domainerr.ErrBadInput},
+		{name: "applicant leaves the class", status: domain.StudentApplicant, body: `{"class_id":null}`},
+		{name: "withdrawn leaves the class", status: domain.StudentWithdrawn, body: `{"class_id":null}`},
+		{name: "enrolled cannot be left without a class", status: domain.StudentEnrolled, body: `{"class_id":null}`, wantErr: domainerr.ErrBadInput},
+		{name: "suspended cannot be left without a class", status: domain.StudentSuspended, body: `{"class_id":null}`, wantErr: domainerr.ErrBadInput},
+		{name: "withdraw and leave in one patch", status: domain.StudentEnrolled, body: `{"class_id":null,"status":"withdrawn"}`},
+		{name: "invalid transition is checked first", status: domain.StudentGraduated, body: `{"class_id":null,"status":"enrolled"}`, wantErr: domainerr.ErrBadInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeStudentRepo{st: domain.Student{
+				ID: 1, FirstName: "Anna", LastName: "Ivanova", Grade: 5, ClassID: &classID, Status: tt.status,
+			}}
+			svc := NewStudentService(repo, fakeClassRepo{}, nil, UploadPolicy{})
+
+			var p domain.StudentPatch
+			if err := json.Unmarshal([]byte(tt.body), &p); err != nil {
+				t.Fatal(err)
+			}
+			got, err := svc.Patch(context.Background(), 1, p)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				if repo.st.ClassID == nil || *repo.st.ClassID != classID {
+					t.Errorf("stored class changed to %v", repo.st.ClassID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (got.ClassID == nil) != (tt.wantClass == nil) || got.ClassID != nil && *got.ClassID != *tt.wantClass {
+				t.Errorf("class_id = %v, want %v", got.ClassID, tt.wantClass)
+			}
+		})
+	}
+}
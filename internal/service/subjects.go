@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+type SubjectRepository interface {
+	List(ctx context.Context) ([]domain.Subject, error)
+	Get(ctx context.Context, id int64) (domain.Subject, error)
+	Create(ctx context.Context, s domain.Subject) (domain.Subject, error)
+	Update(ctx context.Context, s domain.Subject) (domain.Subject, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type SubjectService struct {
+	repo SubjectRepository
+}
+
+func NewSubjectService(repo SubjectRepository) *SubjectService {
+	return &SubjectService{repo: repo}
+}
+
+func (s *SubjectService) List(ctx context.Context) ([]domain.Subject, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *SubjectService) Get(ctx context.Context, id int64) (domain.Subject, error) {
+	if id <= 0 {
+		return domain.Subject{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Get(ctx, id)
+}
+
+func (s *SubjectService) Create(ctx context.Context, sb domain.Subject) (domain.Subject, error) {
+	normalizeSubject(&sb)
+	if err := validateSubject(sb); err != nil {
+		return domain.Subject{}, err
+	}
+	return s.repo.Create(ctx, sb)
+}
+
+func (s *SubjectService) Update(ctx context.Context, sb domain.Subject) (domain.Subject, error) {
+	if sb.ID <= 0 {
+		return domain.Subject{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	normalizeSubject(&sb)
+	if err := validateSubject(sb); err != nil {
+		return domain.Subject{}, err
+	}
+	return s.repo.Update(ctx, sb)
+}
+
+func (s *SubjectService) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func normalizeSubject(s *domain.Subject) {
+	s.Code = strings.ToLower(strings.TrimSpace(s.Code))
+	s.Name = strings.TrimSpace(s.Name)
+	if s.Credits == 0 {
+		s.Credits = domain.DefaultCredits
+	}
+}
+
+func validateSubject(s domain.Subject) error {
+	var v domainerr.ValidationError
+	if s.Code == "" {
+		v.Add("code", "is required")
+	}
+	if s.Name == "" {
+		v.Add("name", "is required")
+	}
+	if s.Credits < 0 || s.Credits > 100 {
+		v.Add("credits", "must be in (0, 100]")
+	}
+	return v.Err()
+}
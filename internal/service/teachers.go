@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"net/mail"
+	"strings"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+// Лимиты пагинации списков.
+const (
+	defaultListLimit = 50
+	maxListLimit     = 500
+)
+
+type TeacherRepository interface {
+	List(ctx context.Context, f domain.TeacherFilter) ([]domain.Teacher, error)
+	Get(ctx context.Context, id int64) (domain.Teacher, error)
+	Create(ctx context.Context, t domain.Teacher) (domain.Teacher, error)
+	Update(ctx context.Context, t domain.Teacher) (domain.Teacher, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+type TeacherService struct {
+	repo TeacherRepository
+}
+
+func NewTeacherService(repo TeacherRepository) *TeacherService {
+	return &TeacherService{repo: repo}
+}
+
+func (s *TeacherService) List(ctx context.Context, f domain.TeacherFilter) ([]domain.Teacher, error) {
+	f.Limit, f.Offset = normalizePage(f.Limit, f.Offset)
+	return s.repo.List(ctx, f)
+}
+
+func (s *TeacherService) Get(ctx context.Context, id int64) (domain.Teacher, error) {
+	if id <= 0 {
+		return domain.Teacher{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Get(ctx, id)
+}
+
+func (s *TeacherService) Create(ctx context.Context, t domain.Teacher) (domain.Teacher, error) {
+	normalizeTeacher(&t)
+	if err := validateTeacher(t); err != nil {
+		return domain.Teacher{}, err
+	}
+	return s.repo.Create(ctx, t)
+}
+
+// Update — полная замена (PUT).
+func (s *TeacherService) Update(ctx context.Context, t domain.Teacher) (domain.Teacher, error) {
+	if t.ID <= 0 {
+		return domain.Teacher{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	normalizeTeacher(&t)
+	if err := validateTeacher(t); err != nil {
+		return domain.Teacher{}, err
+	}
+	return s.repo.Update(ctx, t)
+}
+
+// Patch — частичное обновление: читаем текущую запись, накладываем изменения, валидируем целиком.
+func (s *TeacherService) Patch(ctx context.Context, id int64, p domain.TeacherPatch) (domain.Teacher, error) {
+	t, err := s.Get(ctx, id)
+	if err != nil {
+		return domain.Teacher{}, err
+	}
+
+	if p.FirstName != nil {
+		t.FirstName = *p.FirstName
+	}
+	if p.LastName != nil {
+		t.LastName = *p.LastName
+	}
+	if p.Email != nil {
+		t.Email = *p.Email
+	}
+	if p.Phone != nil {
+		t.Phone = *p.Phone
+	}
+
+	return s.Update(ctx, t)
+}
+
+func (s *TeacherService) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func normalizeTeacher(t *domain.Teacher) {
+	t.FirstName = strings.TrimSpace(t.FirstName)
+	t.LastName = strings.TrimSpace(t.LastName)
+	t.Email = strings.ToLower(strings.TrimSpace(t.Email))
+	t.Phone = strings.TrimSpace(t.Phone)
+}
+
+func validateTeacher(t domain.Teacher) error {
+	var v domainerr.ValidationError
+	if t.FirstName == "" {
+		v.Add("first_name", "is required")
+	}
+	if t.LastName == "" {
+		v.Add("last_name", "is required")
+	}
+	if _, err := mail.ParseAddress(t.Email); err != nil {
+		v.Add("email", "must be a valid email address")
+	}
+	return v.Err()
+}
+
+func normalizePage(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	if limit > maxListLimit {
+		limit = maxListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+type TermRepository interface {
+	ListYears(ctx context.Context) ([]domain.AcademicYear, error)
+	GetYear(ctx context.Context, year int) (domain.AcademicYear, error)
+	CreateYear(ctx context.Context, y domain.AcademicYear) (domain.AcademicYear, error)
+	UpdateYear(ctx context.Context, y domain.AcademicYear) (domain.AcademicYear, error)
+	DeleteYear(ctx context.Context, year int) error
+
+	ListTerms(ctx context.Context, year int) ([]domain.Term, error)
+	GetTerm(ctx context.Context, id int64) (domain.Term, error)
+	TermByNumber(ctx context.Context, year, number int) (domain.Term, error)
+	TermOn(ctx context.Context, d time.Time) (domain.Term, error)
+	CreateTerm(ctx context.Context, t domain.Term) (domain.Term, error)
+	UpdateTerm(ctx context.Context, t domain.Term) (domain.Term, error)
+	DeleteTerm(ctx context.Context, id int64) error
+	Close(ctx context.Context, id int64, closedBy *int64) (domain.Term, error)
+	Reopen(ctx context.Context, id int64) (domain.Term, error)
+}
+
+type TermService struct {
+	repo TermRepository
+	now  func() time.Time
+}
+
+func NewTermService(repo TermRepository) *TermService {
+	return &TermService{repo: repo, now: time.Now}
+}
+
+func (s *TermService) Years(ctx context.Context) ([]domain.AcademicYear, error) {
+	return s.repo.ListYears(ctx)
+}
+
+func (s *TermService) Year(ctx context.Context, year int) (domain.AcademicYear, error) {
+	if year <= 0 {
+		return domain.AcademicYear{}, domainerr.Newf(domainerr.ErrBadInput, "invalid year")
+	}
+	return s.repo.GetYear(ctx, year)
+}
+
+func (s *TermService) CreateYear(ctx context.Context, y domain.AcademicYear) (domain.AcademicYear, error) {
+	normalizeAcademicYear(&y)
+	if err := validateAcademicYear(y); err != nil {
+		return domain.AcademicYear{}, err
+	}
+	return s.repo.CreateYear(ctx, y)
+}
+
+// UpdateYear меняет название и даты года; периоды года должны остаться внутри новых границ.
+func (s *TermService) UpdateYear(ctx context.Context, y domain.AcademicYear) (domain.AcademicYear, error) {
+	if y.Year <= 0 {
+		return domain.AcademicYear{}, domainerr.Newf(domainerr.ErrBadInput, "invalid year")
+	}
+	normalizeAcademicYear(&y)
+	if err := validateAcademicYear(y); err != nil {
+		return domain.AcademicYear{}, err
+	}
+
+	terms, err := s.repo.ListTerms(ctx, y.Year)
+	if err != nil {
+		return domain.AcademicYear{}, err
+	}
+	var v domainerr.ValidationError
+	for _, t := range terms {
+		if t.StartDate.Before(y.StartDate) || t.EndDate.After(y.EndDate) {
+			v.Add("start_date", fmt.Sprintf("term %d (%s) falls outside the new dates", t.Number, t.Name))
+		}
+	}
+	if err := v.Err(); err != nil {
+		return domain.AcademicYear{}, err
+	}
+
+	return s.repo.UpdateYear(ctx, y)
+}
+
+func (s *TermService) DeleteYear(ctx context.Context, year int) error {
+	if year <= 0 {
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid year")
+	}
+	return s.repo.DeleteYear(ctx, year)
+}
+
+func (s *TermService) Terms(ctx context.Context, year int) ([]domain.Term, error) {
+	if _, err := s.Year(ctx, year); err != nil {
+		return nil, err
+	}
+	return s.repo.ListTerms(ctx, year)
+}
+
+func (s *TermService) Term(ctx context.Context, id int64) (domain.Term, error) {
+	if id <= 0 {
+		return domain.Term{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.GetTerm(ctx, id)
+}
+
+func (s *TermService) CreateTerm(ctx context.Context, t domain.Term) (domain.Term, error) {
+	normalizeTerm(&t)
+	if err := s.validateTerm(ctx, t); err != nil {
+		return domain.Term{}, err
+	}
+	return s.repo.CreateTerm(ctx, t)
+}
+
+// UpdateTerm меняет название и даты периода. Год и номер не меняются.
+func (s *TermService) UpdateTerm(ctx context.Context, t domain.Term) (domain.Term, error) {
+	cur, err := s.Term(ctx, t.ID)
+	if err != nil {
+		return domain.Term{}, err
+	}
+	t.AcademicYear, t.Number = cur.AcademicYear, cur.Number
+
+	normalizeTerm(&t)
+	if err := s.validateTerm(ctx, t); err != nil {
+		return domain.Term{}, err
+	}
+	return s.repo.UpdateTerm(ctx, t)
+}
+
+func (s *TermService) DeleteTerm(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.DeleteTerm(ctx, id)
+}
+
+// Current — период, на который приходится дата on (нулевая — сегодня). Вне периодов — ErrNotFound.
+func (s *TermService) Current(ctx context.Context, on time.Time) (domain.Term, error) {
+	if on.IsZero() {
+		on = s.now()
+	}
+	return s.repo.TermOn(ctx, dateOnly(on))
+}
+
+// Close закрывает период: оценки в нём больше не правятся, пока период не откроют.
+func (s *TermService) Close(ctx context.Context, actor domain.Principal, id int64) (domain.Term, error) {
+	if id <= 0 {
+		return domain.Term{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	var closedBy *int64
+	if actor.ExecID != 0 {
+		closedBy = &actor.ExecID
+	}
+	return s.repo.Close(ctx, id, closedBy)
+}
+
+func (s *TermService) Reopen(ctx context.Context, id int64) (domain.Term, error) {
+	if id <= 0 {
+		return domain.Term{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Reopen(ctx, id)
+}
+
+func (s *TermService) validateTerm(ctx context.Context, t domain.Term) error {
+	var v domainerr.ValidationError
+	if t.Number < domain.MinTerm || t.Number > domain.MaxTerm {
+		v.Add("number", fmt.Sprintf("must be between %d and %d", domain.MinTerm, domain.MaxTerm))
+	}
+	if t.StartDate.IsZero() {
+		v.Add("start_date", "is required")
+	}
+	if t.EndDate.IsZero() {
+		v.Add("end_date", "is required")
+	}
+	if !t.StartDate.IsZero() && t.EndDate.Before(t.StartDate) {
+		v.Add("end_date", "must not be before start_date")
+	}
+
+	y, err := s.repo.GetYear(ctx, t.AcademicYear)
+	switch {
+	case errors.Is(err, domainerr.ErrNotFound):
+		v.Add("academic_year", "not found")
+	case err != nil:
+		return err
+	case !t.StartDate.IsZero() && !t.EndDate.IsZero() && (t.StartDate.Before(y.StartDate) || t.EndDate.After(y.EndDate)):
+		v.Add("start_date", "term must lie within the academic year")
+	}
+	return v.Err()
+}
+
+// termOn — период, на который приходится дата; ok == false, если дата вне периодов
+// или учебный календарь не заведён.
+func termOn(ctx context.Context, terms TermRepository, d time.Time) (domain.Term, bool, error) {
+	t, err := terms.TermOn(ctx, dateOnly(d))
+	if errors.Is(err, domainerr.ErrNotFound) {
+		return domain.Term{}, false, nil
+	}
+	if err != nil {
+		return domain.Term{}, false, err
+	}
+	return t, true, nil
+}
+
+// checkTermOpen запрещает правку оценок в закрытом периоде. Период, не заведённый в календаре, открыт.
+func checkTermOpen(ctx context.Context, terms TermRepository, year, number int) error {
+	t, err := terms.TermByNumber(ctx, year, number)
+	if errors.Is(err, domainerr.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if t.Closed {
+		return domainerr.Newf(domainerr.ErrForbidden, "term %d of %d/%d is closed", number, year, year+1)
+	}
+	return nil
+}
+
+func normalizeAcademicYear(y *domain.AcademicYear) {
+	y.Name = strings.TrimSpace(y.Name)
+	if y.Name == "" && y.Year > 0 {
+		y.Name = strconv.Itoa(y.Year) + "/" + strconv.Itoa(y.Year+1)
+	}
+	y.StartDate, y.EndDate = dateOnly(y.StartDate), dateOnly(y.EndDate)
+}
+
+func validateAcademicYear(y domain.AcademicYear) error {
+	var v domainerr.ValidationError
+	if y.Year < 2000 || y.Year > 2100 {
+		v.Add("year", "must be between 2000 and 2100")
+	}
+	if y.StartDate.IsZero() {
+		v.Add("start_date", "is required")
+	} else if y.StartDate.Year() != y.Year {
+		v.Add("start_date", "must be in the starting year")
+	}
+	if y.EndDate.IsZero() {
+		v.Add("end_date", "is required")
+	} else if !y.StartDate.IsZero() && !y.EndDate.After(y.StartDate) {
+		v.Add("end_date", "must be after start_date")
+	}
+	return v.Err()
+}
+
+func normalizeTerm(t *domain.Term) {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" && t.Number > 0 {
+		t.Name = "Term " + strconv.Itoa(t.Number)
+	}
+	t.StartDate, t.EndDate = dateOnly(t.StartDate), dateOnly(t.EndDate)
+}
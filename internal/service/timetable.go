@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+type TimetableRepository interface {
+	ListByClass(ctx context.Context, classID int64, f domain.TimetableFilter) ([]domain.TimetableSlot, error)
+	ListByTeacher(ctx context.Context, teacherID int64, f domain.TimetableFilter) ([]domain.TimetableSlot, error)
+	ListByRoom(ctx context.Context, roomID int64, f domain.TimetableFilter) ([]domain.TimetableSlot, error)
+	Clashes(ctx context.Context, s domain.TimetableSlot) ([]domain.TimetableSlot, error)
+	Get(ctx context.Context, id int64) (domain.TimetableSlot, error)
+	Create(ctx context.Context, s domain.TimetableSlot) (domain.TimetableSlot, error)
+	Update(ctx context.Context, s domain.TimetableSlot) (domain.TimetableSlot, error)
+	Delete(ctx context.Context, id int64) error
+	ListAll(ctx context.Context, f domain.TimetableFilter) ([]domain.TimetableSlot, error)
+
+	ListUnavailability(ctx context.Context, teacherIDs []int64) (map[int64][]domain.LessonTime, error)
+	SetUnavailability(ctx context.Context, teacherID int64, times []domain.LessonTime) error
+}
+
+type TimetableService struct {
+	repo        TimetableRepository
+	classes     ClassRepository
+	assignments AssignmentRepository
+	teachers    TeacherRepository
+	rooms       RoomRepository
+	students    StudentRepository
+}
+
+func NewTimetableService(repo TimetableRepository, classes ClassRepository, assignments AssignmentRepository, teachers TeacherRepository, rooms RoomRepository, students StudentRepository) *TimetableService {
+	return &TimetableService{repo: repo, classes: classes, assignments: assignments, teachers: teachers, rooms: rooms, students: students}
+}
+
+func (s *TimetableService) ClassTimetable(ctx context.Context, classID int64, f domain.TimetableFilter) ([]domain.TimetableSlot, error) {
+	f, err := normalizeTimetableFilter(f)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.classes.Get(ctx, classID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByClass(ctx, classID, f)
+}
+
+// StudentTimetable — расписание класса ученика; у ученика без класса расписание пустое.
+func (s *TimetableService) StudentTimetable(ctx context.Context, studentID int64, f domain.TimetableFilter) ([]domain.TimetableSlot, error) {
+	f, err := normalizeTimetableFilter(f)
+	if err != nil {
+		return nil, err
+	}
+	st, err := s.students.Get(ctx, studentID)
+	if err != nil {
+		return nil, err
+	}
+	if st.ClassID == nil {
+		return make([]domain.TimetableSlot, 0), nil
+	}
+	return s.repo.ListByClass(ctx, *st.ClassID, f)
+}
+
+func (s *TimetableService) TeacherTimetable(ctx context.Context, teacherID int64, f domain.TimetableFilter) ([]domain.TimetableSlot, error) {
+	f, err := normalizeTimetableFilter(f)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.teachers.Get(ctx, teacherID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByTeacher(ctx, teacherID, f)
+}
+
+func (s *TimetableService) RoomTimetable(ctx context.Context, roomID int64, f domain.TimetableFilter) ([]domain.TimetableSlot, error) {
+	f, err := normalizeTimetableFilter(f)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.rooms.Get(ctx, roomID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByRoom(ctx, roomID, f)
+}
+
+func (s *TimetableService) Get(ctx context.Context, id int64) (domain.TimetableSlot, error) {
+	if id <= 0 {
+		return domain.TimetableSlot{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Get(ctx, id)
+}
+
+// Create ставит урок в расписание. Если класс, учитель или кабинет уже заняты
+// в этот день и час на пересекающемся периоде — ErrConflict с конфликтующими уроками.
+func (s *TimetableService) Create(ctx context.Context, slot domain.TimetableSlot) (domain.TimetableSlot, error) {
+	slot.ID = 0
+	if err := s.validateSlot(ctx, &slot); err != nil {
+		return domain.TimetableSlot{}, err
+	}
+	if err := s.checkClashes(ctx, slot); err != nil {
+		return domain.TimetableSlot{}, err
+	}
+	return s.repo.Create(ctx, slot)
+}
+
+// Update меняет урок целиком. Чтобы сменить расписание с середины года, закройте старый урок
+// (effective_to) и создайте новый с effective_from на следующий день.
+func (s *TimetableService) Update(ctx context.Context, slot domain.TimetableSlot) (domain.TimetableSlot, error) {
+	if slot.ID <= 0 {
+		return domain.TimetableSlot{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	if _, err := s.repo.Get(ctx, slot.ID); err != nil {
+		return domain.TimetableSlot{}, err
+	}
+	if err := s.validateSlot(ctx, &slot); err != nil {
+		return domain.TimetableSlot{}, err
+	}
+	if err := s.checkClashes(ctx, slot); err != nil {
+		return domain.TimetableSlot{}, err
+	}
+	return s.repo.Update(ctx, slot)
+}
+
+func (s *TimetableService) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Availability — часы, когда учитель не может вести уроки.
+func (s *TimetableService) Availability(ctx context.Context, teacherID int64) (domain.TeacherAvailability, error) {
+	if _, err := s.teachers.Get(ctx, teacherID); err != nil {
+		return domain.TeacherAvailability{}, err
+	}
+	byTeacher, err := s.repo.ListUnavailability(ctx, []int64{teacherID})
+	if err != nil {
+		return domain.TeacherAvailability{}, err
+	}
+
+	times := byTeacher[teacherID]
+	if times == nil {
+		times = make([]domain.LessonTime, 0)
+	}
+	return domain.TeacherAvailability{TeacherID: teacherID, Unavailable: times}, nil
+}
+
+// SetAvailability заменяет список занятых часов учителя.
+func (s *TimetableService) SetAvailability(ctx context.Context, teacherID int64, a domain.TeacherAvailability) (domain.TeacherAvailability, error) {
+	if _, err := s.teachers.Get(ctx, teacherID); err != nil {
+		return domain.TeacherAvailability{}, err
+	}
+
+	var v domainerr.ValidationError
+	seen := make(map[domain.LessonTime]bool, len(a.Unavailable))
+	for i, t := range a.Unavailable {
+		field := "unavailable[" + strconv.Itoa(i) + "]"
+		if t.Day < domain.MinDay || t.Day > domain.MaxDay {
+			v.Add(field+".day", fmt.Sprintf("must be between %d and %d", domain.MinDay, domain.MaxDay))
+		}
+		if t.Period < 1 || t.Period > domain.MaxLesson {
+			v.Add(field+".period", fmt.Sprintf("must be between 1 and %d", domain.MaxLesson))
+		}
+		if seen[t] {
+			v.Add(field, "duplicate day and period")
+		}
+		seen[t] = true
+	}
+	if err := v.Err(); err != nil {
+		return domain.TeacherAvailability{}, err
+	}
+
+	if err := s.repo.SetUnavailability(ctx, teacherID, a.Unavailable); err != nil {
+		return domain.TeacherAvailability{}, err
+	}
+	return s.Availability(ctx, teacherID)
+}
+
+// validateSlot проверяет поля урока. Если учитель не указан, берётся учитель,
+// ведущий предмет в классе по нагрузке.
+func (s *TimetableService) validateSlot(ctx context.Context, slot *domain.TimetableSlot) error {
+	slot.EffectiveFrom = dateOnly(slot.EffectiveFrom)
+	if slot.EffectiveTo != nil {
+		to := dateOnly(*slot.EffectiveTo)
+		slot.EffectiveTo = &to
+	}
+
+	var v domainerr.ValidationError
+	if slot.Day < domain.MinDay || slot.Day > domain.MaxDay {
+		v.Add("day", fmt.Sprintf("must be between %d (Monday) and %d (Sunday)", domain.MinDay, domain.MaxDay))
+	}
+	if slot.Period < 1 || slot.Period > domain.MaxLesson {
+		v.Add("period", fmt.Sprintf("must be between 1 and %d", domain.MaxLesson))
+	}
+	if slot.EffectiveFrom.IsZero() {
+		v.Add("effective_from", "is required")
+	} else if slot.EffectiveTo != nil && slot.EffectiveTo.Before(slot.EffectiveFrom) {
+		v.Add("effective_to", "must not be before effective_from")
+	}
+
+	if err := exists(&v, "class_id", func() error {
+		_, err := s.classes.Get(ctx, slot.ClassID)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	asg, err := s.assignments.Get(ctx, slot.ClassID, slot.SubjectID)
+	switch {
+	case errors.Is(err, domainerr.ErrNotFound):
+		v.Add("subject_id", "subject is not assigned to this class")
+	case err != nil:
+		return err
+	case slot.TeacherID == 0:
+		slot.TeacherID = asg.TeacherID
+	}
+
+	if slot.TeacherID != 0 {
+		if err := exists(&v, "teacher_id", func() error {
+			_, err := s.teachers.Get(ctx, slot.TeacherID)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+	if slot.RoomID != nil {
+		if err := exists(&v, "room_id", func() error {
+			_, err := s.rooms.Get(ctx, *slot.RoomID)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+
+	return v.Err()
+}
+
+// checkClashes возвращает ErrConflict, если урок пересекается с уже стоящими в расписании.
+func (s *TimetableService) checkClashes(ctx context.Context, slot domain.TimetableSlot) error {
+	found, err := s.repo.Clashes(ctx, slot)
+	if err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	clashes := make([]domain.Clash, 0, len(found))
+	for _, other := range found {
+		kind := domain.ClashRoom
+		switch {
+		case other.ClassID == slot.ClassID:
+			kind = domain.ClashClass
+		case other.TeacherID == slot.TeacherID:
+			kind = domain.ClashTeacher
+		}
+		clashes = append(clashes, domain.Clash{Kind: kind, Slot: other})
+	}
+
+	first := clashes[0]
+	return &domainerr.ConflictError{
+		Message: fmt.Sprintf("%s is already booked on day %d period %d by slot %d (class %s, %s)",
+			first.Kind, first.Slot.Day, first.Slot.Period, first.Slot.ID, first.Slot.ClassName, first.Slot.SubjectName),
+		With: clashes,
+	}
+}
+
+// normalizeTimetableFilter: дата On сводится к периоду On..On; без дат — расписание на сегодня.
+func normalizeTimetableFilter(f domain.TimetableFilter) (domain.TimetableFilter, error) {
+	if !f.On.IsZero() {
+		f.From, f.To = f.On, f.On
+	}
+	if f.From.IsZero() && f.To.IsZero() {
+		today := dateOnly(time.Now())
+		f.From, f.To = today, today
+	}
+	if err := validateDateRange(domain.DateRange{From: f.From, To: f.To}); err != nil {
+		return f, err
+	}
+	return f, nil
+}
@@ -0,0 +1,437 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+	log "restapi/internal/logger"
+)
+
+// Параметры генерации по умолчанию и ограничения.
+const (
+	defaultSchoolDays     = 5
+	defaultPeriodsPerDay  = 7
+	defaultSolverTimeout  = 30  // секунды
+	maxSolverTimeout      = 300 // секунды
+	generatorQueueSize    = 8
+	progressSaveInterval  = time.Second
+	generatorFailTimeout  = 5 * time.Second
+	maxGeneratorClassList = 500
+)
+
+type TimetableDraftRepository interface {
+	Create(ctx context.Context, d domain.TimetableDraft) (domain.TimetableDraft, error)
+	Get(ctx context.Context, id int64) (domain.TimetableDraft, error)
+	List(ctx context.Context, limit, offset int) ([]domain.TimetableDraft, error)
+	Start(ctx context.Context, id int64) (domain.TimetableDraft, error)
+	SetProgress(ctx context.Context, id int64, progress int) error
+	Finish(ctx context.Context, id int64, slots []domain.TimetableSlot, message string) error
+	Fail(ctx context.Context, id int64, message string) error
+	Slots(ctx context.Context, id, classID int64) ([]domain.TimetableSlot, error)
+	Publish(ctx context.Context, id int64) (domain.TimetableDraft, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// TimetableGenerator строит черновики расписания в фоне: одна задача за раз, остальные ждут в очереди.
+// Ход выполнения пишется в черновик (status, progress), его и читает клиент.
+type TimetableGenerator struct {
+	drafts      TimetableDraftRepository
+	timetable   TimetableRepository
+	classes     ClassRepository
+	assignments AssignmentRepository
+	rooms       RoomRepository
+
+	jobs   chan int64
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewTimetableGenerator запускает фоновый обработчик задач. Остановка — Close.
+func NewTimetableGenerator(drafts TimetableDraftRepository, timetable TimetableRepository, classes ClassRepository, assignments AssignmentRepository, rooms RoomRepository) *TimetableGenerator {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &TimetableGenerator{
+		drafts:      drafts,
+		timetable:   timetable,
+		classes:     classes,
+		assignments: assignments,
+		rooms:       rooms,
+		jobs:        make(chan int64, generatorQueueSize),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	g.wg.Add(1)
+	go g.worker()
+	return g
+}
+
+// Close прерывает текущую задачу (она помечается failed) и останавливает обработчик.
+func (g *TimetableGenerator) Close() {
+	g.cancel()
+	g.wg.Wait()
+}
+
+// Generate ставит задачу генерации в очередь и сразу возвращает черновик в статусе queued.
+func (g *TimetableGenerator) Generate(ctx context.Context, actor domain.Principal, req domain.GenerateTimetable) (domain.TimetableDraft, error) {
+	if req.AcademicYear == 0 {
+		req.AcademicYear = CurrentAcademicYear(time.Now())
+	}
+	if req.Days == 0 {
+		req.Days = defaultSchoolDays
+	}
+	if req.Periods == 0 {
+		req.Periods = defaultPeriodsPerDay
+	}
+	if req.TimeLimit == 0 {
+		req.TimeLimit = defaultSolverTimeout
+	}
+
+	var v domainerr.ValidationError
+	if req.EffectiveFrom.IsZero() {
+		v.Add("effective_from", "is required")
+	}
+	if req.Days < domain.MinDay || req.Days > domain.MaxDay {
+		v.Add("days", fmt.Sprintf("must be between %d and %d", domain.MinDay, domain.MaxDay))
+	}
+	if req.Periods < 1 || req.Periods > domain.MaxLesson {
+		v.Add("periods", fmt.Sprintf("must be between 1 and %d", domain.MaxLesson))
+	}
+	if req.TimeLimit < 1 || req.TimeLimit > maxSolverTimeout {
+		v.Add("time_limit_seconds", fmt.Sprintf("must be between 1 and %d", maxSolverTimeout))
+	}
+	if err := v.Err(); err != nil {
+		return domain.TimetableDraft{}, err
+	}
+
+	classIDs, err := g.resolveClasses(ctx, req)
+	if err != nil {
+		return domain.TimetableDraft{}, err
+	}
+
+	d := domain.TimetableDraft{
+		AcademicYear:  req.AcademicYear,
+		ClassIDs:      classIDs,
+		EffectiveFrom: dateOnly(req.EffectiveFrom),
+		Days:          req.Days,
+		Periods:       req.Periods,
+		TimeLimit:     req.TimeLimit,
+	}
+	if actor.ExecID != 0 {
+		d.CreatedBy = &actor.ExecID
+	}
+
+	d, err = g.drafts.Create(ctx, d)
+	if err != nil {
+		return domain.TimetableDraft{}, err
+	}
+
+	select {
+	case g.jobs <- d.ID:
+		return d, nil
+	default:
+		if err := g.drafts.Fail(ctx, d.ID, "generator queue is full"); err != nil {
+			log.Warn("fail timetable draft", "draft_id", d.ID, "err", err)
+		}
+		return domain.TimetableDraft{}, domainerr.Newf(domainerr.ErrConflict, "too many timetable generation jobs queued, try later")
+	}
+}
+
+func (g *TimetableGenerator) Draft(ctx context.Context, id int64) (domain.TimetableDraft, error) {
+	if id <= 0 {
+		return domain.TimetableDraft{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return g.drafts.Get(ctx, id)
+}
+
+func (g *TimetableGenerator) Drafts(ctx context.Context, limit, offset int) ([]domain.TimetableDraft, error) {
+	limit, offset = normalizePage(limit, offset)
+	return g.drafts.List(ctx, limit, offset)
+}
+
+// DraftSlots — уроки черновика; classID == 0 — по всем классам.
+func (g *TimetableGenerator) DraftSlots(ctx context.Context, id, classID int64) ([]domain.TimetableSlot, error) {
+	if _, err := g.Draft(ctx, id); err != nil {
+		return nil, err
+	}
+	return g.drafts.Slots(ctx, id, classID)
+}
+
+// Publish заменяет расписание классов черновика начиная с его effective_from.
+func (g *TimetableGenerator) Publish(ctx context.Context, id int64) (domain.TimetableDraft, error) {
+	if id <= 0 {
+		return domain.TimetableDraft{}, domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return g.drafts.Publish(ctx, id)
+}
+
+func (g *TimetableGenerator) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid id")
+	}
+	return g.drafts.Delete(ctx, id)
+}
+
+// resolveClasses возвращает классы задачи: указанные (все из учебного года req) или все классы года.
+func (g *TimetableGenerator) resolveClasses(ctx context.Context, req domain.GenerateTimetable) ([]int64, error) {
+	var v domainerr.ValidationError
+
+	if len(req.ClassIDs) == 0 {
+		list, err := g.classes.List(ctx, domain.ClassFilter{AcademicYear: req.AcademicYear, Limit: maxGeneratorClassList})
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]int64, 0, len(list))
+		for _, c := range list {
+			ids = append(ids, c.ID)
+		}
+		if len(ids) == 0 {
+			v.Add("academic_year", "no classes in this academic year")
+		}
+		return ids, v.Err()
+	}
+
+	seen := make(map[int64]bool, len(req.ClassIDs))
+	for _, id := range req.ClassIDs {
+		if seen[id] {
+			v.Add("class_ids", fmt.Sprintf("class %d listed twice", id))
+			continue
+		}
+		seen[id] = true
+
+		c, err := g.classes.Get(ctx, id)
+		switch {
+		case errors.Is(err, domainerr.ErrNotFound):
+			v.Add("class_ids", fmt.Sprintf("class %d does not exist", id))
+		case err != nil:
+			return nil, err
+		case c.AcademicYear != req.AcademicYear:
+			v.Add("class_ids", fmt.Sprintf("class %d belongs to academic year %d", id, c.AcademicYear))
+		}
+	}
+	return req.ClassIDs, v.Err()
+}
+
+func (g *TimetableGenerator) worker() {
+	defer g.wg.Done()
+	for {
+		select {
+		case <-g.ctx.Done():
+			return
+		case id := <-g.jobs:
+			g.run(id)
+		}
+	}
+}
+
+func (g *TimetableGenerator) run(id int64) {
+	d, err := g.drafts.Start(g.ctx, id)
+	if err != nil {
+		// Черновик удалили, пока он стоял в очереди.
+		log.Warn("start timetable draft", "draft_id", id, "err", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(g.ctx, time.Duration(d.TimeLimit)*time.Second)
+	defer cancel()
+
+	started := time.Now()
+	slots, err := g.solve(ctx, d)
+	if err == nil {
+		msg := fmt.Sprintf("%d lessons placed in %s", len(slots), time.Since(started).Round(time.Millisecond))
+		if err = g.drafts.Finish(g.ctx, id, slots, msg); err == nil {
+			log.Info("timetable draft ready", "draft_id", id, "lessons", len(slots))
+			return
+		}
+	}
+
+	msg := err.Error()
+	switch {
+	case g.ctx.Err() != nil:
+		msg = "interrupted by server shutdown"
+	case errors.Is(err, context.DeadlineExceeded):
+		msg = fmt.Sprintf("no timetable found within %ds time limit", d.TimeLimit)
+	}
+
+	// Контекст генератора может быть уже отменён — отметку о сбое пишем отдельным.
+	failCtx, cancelFail := context.WithTimeout(context.Background(), generatorFailTimeout)
+	defer cancelFail()
+	if err := g.drafts.Fail(failCtx, id, msg); err != nil {
+		log.Warn("fail timetable draft", "draft_id", id, "err", err)
+	}
+	log.Info("timetable draft failed", "draft_id", id, "reason", msg)
+}
+
+// solve собирает задачу по данным БД и запускает решатель.
+func (g *TimetableGenerator) solve(ctx context.Context, d domain.TimetableDraft) ([]domain.TimetableSlot, error) {
+	p, err := g.buildProblem(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCapacity(p); err != nil {
+		return nil, err
+	}
+
+	var lastSave time.Time
+	progress := func(placed, total int) {
+		if time.Since(lastSave) < progressSaveInterval {
+			return
+		}
+		lastSave = time.Now()
+		// 100 — только у готового черновика.
+		pct := min(placed*100/total, 99)
+		if err := g.drafts.SetProgress(ctx, d.ID, pct); err != nil {
+			log.Warn("save timetable draft progress", "draft_id", d.ID, "err", err)
+		}
+	}
+
+	return solveTimetable(ctx, p, progress)
+}
+
+func (g *TimetableGenerator) buildProblem(ctx context.Context, d domain.TimetableDraft) (*solverProblem, error) {
+	p := &solverProblem{
+		days:           d.Days,
+		periods:        d.Periods,
+		teacherBlocked: make(map[int64][]bool),
+		roomBlocked:    make(map[int64][]bool),
+	}
+
+	sizes, err := g.classes.CountStudents(ctx, d.ClassIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	inScope := make(map[int64]bool, len(d.ClassIDs))
+	teacherSet := make(map[int64]bool)
+	for _, classID := range d.ClassIDs {
+		inScope[classID] = true
+
+		asgs, err := g.assignments.ListByClass(ctx, classID)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range asgs {
+			if a.HoursPerWeek <= 0 {
+				continue
+			}
+			p.groups = append(p.groups, solverGroup{
+				classID:   a.ClassID,
+				subjectID: a.SubjectID,
+				teacherID: a.TeacherID,
+				size:      sizes[a.ClassID],
+				count:     a.HoursPerWeek,
+			})
+			teacherSet[a.TeacherID] = true
+		}
+	}
+	if len(p.groups) == 0 {
+		return nil, errors.New("selected classes have no subject hours assigned")
+	}
+
+	rooms, err := g.rooms.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Меньшие кабинеты раньше — крупные остаются большим классам; без вместимости — в конце.
+	sort.SliceStable(rooms, func(i, j int) bool {
+		ci, cj := rooms[i].Capacity, rooms[j].Capacity
+		if (ci == 0) != (cj == 0) {
+			return cj == 0
+		}
+		return ci < cj
+	})
+	p.rooms = rooms
+
+	teacherIDs := make([]int64, 0, len(teacherSet))
+	for id := range teacherSet {
+		teacherIDs = append(teacherIDs, id)
+	}
+	unavailable, err := g.timetable.ListUnavailability(ctx, teacherIDs)
+	if err != nil {
+		return nil, err
+	}
+	for teacherID, times := range unavailable {
+		for _, t := range times {
+			p.block(p.teacherBlocked, teacherID, t.Day, t.Period)
+		}
+	}
+
+	// Уроки других классов, действующие на дату начала, занимают учителей и кабинеты.
+	existing, err := g.timetable.ListAll(ctx, domain.TimetableFilter{From: d.EffectiveFrom, To: d.EffectiveFrom})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range existing {
+		if inScope[s.ClassID] {
+			continue
+		}
+		p.block(p.teacherBlocked, s.TeacherID, s.Day, s.Period)
+		if s.RoomID != nil {
+			p.block(p.roomBlocked, *s.RoomID, s.Day, s.Period)
+		}
+	}
+
+	return p, nil
+}
+
+// block помечает ячейку занятой; часы вне сетки задачи игнорируются.
+func (p *solverProblem) block(m map[int64][]bool, id int64, day, period int) {
+	if day < 1 || day > p.days || period < 1 || period > p.periods {
+		return
+	}
+	if m[id] == nil {
+		m[id] = make([]bool, p.cells())
+	}
+	m[id][(day-1)*p.periods+period-1] = true
+}
+
+// checkCapacity отсекает заведомо нерешаемые задачи с понятной причиной,
+// чтобы не тратить время перебора.
+func checkCapacity(p *solverProblem) error {
+	classHours := make(map[int64]int)
+	teacherHours := make(map[int64]int)
+	for _, g := range p.groups {
+		classHours[g.classID] += g.count
+		teacherHours[g.teacherID] += g.count
+	}
+
+	for id, h := range classHours {
+		if h > p.cells() {
+			return fmt.Errorf("class %d needs %d lessons a week but the grid has only %d", id, h, p.cells())
+		}
+	}
+	if len(p.rooms) > 0 {
+		for _, g := range p.groups {
+			if !roomFits(p.rooms, g.size) {
+				return fmt.Errorf("no room can seat class %d (%d students)", g.classID, g.size)
+			}
+		}
+	}
+	for id, h := range teacherHours {
+		free := p.cells()
+		for _, blocked := range p.teacherBlocked[id] {
+			if blocked {
+				free--
+			}
+		}
+		if h > free {
+			return fmt.Errorf("teacher %d needs %d lessons a week but is available for only %d", id, h, free)
+		}
+	}
+	return nil
+}
+
+func roomFits(rooms []domain.Room, size int) bool {
+	for _, rm := range rooms {
+		if rm.Capacity == 0 || rm.Capacity >= size {
+			return true
+		}
+	}
+	return false
+}
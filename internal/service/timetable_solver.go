@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"restapi/internal/domain"
+)
+
+// errNoTimetable — перебор исчерпан: расписания без пересечений при заданных ограничениях нет.
+var errNoTimetable = errors.New("no clash-free timetable satisfies the constraints")
+
+// solverGroup — одинаковые уроки: предмет в классе у одного учителя, count часов в неделю.
+type solverGroup struct {
+	classID   int64
+	subjectID int64
+	teacherID int64
+	size      int // учеников в классе, для подбора кабинета
+	count     int
+}
+
+// solverProblem — входные данные генератора. Ячейка расписания — индекс (day-1)*periods + (period-1).
+type solverProblem struct {
+	days    int
+	periods int
+	groups  []solverGroup
+	rooms   []domain.Room // пусто — уроки без кабинетов
+
+	// Занятые ячейки вне задачи: недоступность учителей и уроки других классов.
+	teacherBlocked map[int64][]bool
+	roomBlocked    map[int64][]bool
+}
+
+func (p *solverProblem) cells() int { return p.days * p.periods }
+
+type solverOption struct {
+	cell    int
+	roomID  *int64
+	penalty int
+}
+
+type solverPlacement struct {
+	group int
+	solverOption
+}
+
+type subjectDay struct {
+	classID, subjectID int64
+	day                int
+}
+
+// solver — перебор с возвратом: на каждом шаге берётся группа с наименьшим числом допустимых
+// ячеек (MRV), ячейки перебираются от «дешёвых» (предмет ещё не стоит в этот день, ранний урок).
+// Уроки одной группы взаимозаменяемы, поэтому ставятся по возрастанию ячейки — это отсекает симметрию.
+type solver struct {
+	p     *solverProblem
+	ctx   context.Context
+	total int
+
+	classBusy   map[int64][]bool
+	teacherBusy map[int64][]bool
+	roomBusy    map[int64][]bool
+	perDay      map[subjectDay]int
+	placed      [][]int // по группам: занятые ячейки по возрастанию
+
+	stack []solverPlacement
+	best  int
+	nodes int
+	err   error
+
+	progress func(placed, total int)
+}
+
+// solveTimetable ищет расписание без пересечений. progress вызывается периодически
+// с лучшим достигнутым числом расставленных уроков.
+func solveTimetable(ctx context.Context, p *solverProblem, progress func(placed, total int)) ([]domain.TimetableSlot, error) {
+	s := &solver{
+		p:           p,
+		ctx:         ctx,
+		classBusy:   make(map[int64][]bool),
+		teacherBusy: make(map[int64][]bool),
+		roomBusy:    make(map[int64][]bool),
+		perDay:      make(map[subjectDay]int),
+		placed:      make([][]int, len(p.groups)),
+		progress:    progress,
+	}
+	for _, g := range p.groups {
+		s.total += g.count
+		if s.classBusy[g.classID] == nil {
+			s.classBusy[g.classID] = make([]bool, p.cells())
+		}
+		if s.teacherBusy[g.teacherID] == nil {
+			s.teacherBusy[g.teacherID] = make([]bool, p.cells())
+		}
+	}
+	for _, rm := range p.rooms {
+		s.roomBusy[rm.ID] = make([]bool, p.cells())
+	}
+	s.stack = make([]solverPlacement, 0, s.total)
+
+	if !s.search() {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, errNoTimetable
+	}
+
+	slots := make([]domain.TimetableSlot, 0, len(s.stack))
+	for _, pl := range s.stack {
+		g := p.groups[pl.group]
+		slots = append(slots, domain.TimetableSlot{
+			ClassID:   g.classID,
+			SubjectID: g.subjectID,
+			TeacherID: g.teacherID,
+			RoomID:    pl.roomID,
+			Day:       pl.cell/p.periods + 1,
+			Period:    pl.cell%p.periods + 1,
+		})
+	}
+	return slots, nil
+}
+
+func (s *solver) search() bool {
+	if len(s.stack) == s.total {
+		return true
+	}
+
+	s.nodes++
+	if s.nodes%512 == 0 {
+		if err := s.ctx.Err(); err != nil {
+			s.err = err
+			return false
+		}
+		if s.progress != nil {
+			s.progress(s.best, s.total)
+		}
+	}
+
+	gi, opts := s.mostConstrained()
+	if gi < 0 {
+		return false
+	}
+
+	for _, o := range opts {
+		s.place(gi, o)
+		if s.search() {
+			return true
+		}
+		s.unplace()
+		if s.err != nil {
+			return false
+		}
+	}
+	return false
+}
+
+// mostConstrained возвращает группу с наименьшим числом допустимых ячеек и эти ячейки.
+// -1 — тупик: какой-то группе не хватает ячеек на оставшиеся уроки.
+func (s *solver) mostConstrained() (int, []solverOption) {
+	best := -1
+	var bestOpts []solverOption
+
+	for gi, g := range s.p.groups {
+		left := g.count - len(s.placed[gi])
+		if left == 0 {
+			continue
+		}
+		opts := s.options(gi)
+		if len(opts) < left {
+			return -1, nil
+		}
+		if best < 0 || len(opts)-left < len(bestOpts)-(s.p.groups[best].count-len(s.placed[best])) {
+			best, bestOpts = gi, opts
+		}
+	}
+
+	sort.SliceStable(bestOpts, func(i, j int) bool { return bestOpts[i].penalty < bestOpts[j].penalty })
+	return best, bestOpts
+}
+
+func (s *solver) options(gi int) []solverOption {
+	g := s.p.groups[gi]
+	start := 0
+	if n := len(s.placed[gi]); n > 0 {
+		start = s.placed[gi][n-1] + 1
+	}
+
+	classBusy := s.classBusy[g.classID]
+	teacherBusy := s.teacherBusy[g.teacherID]
+	teacherBlocked := s.p.teacherBlocked[g.teacherID]
+
+	var opts []solverOption
+	for cell := start; cell < s.p.cells(); cell++ {
+		if classBusy[cell] || teacherBusy[cell] || (teacherBlocked != nil && teacherBlocked[cell]) {
+			continue
+		}
+		roomID, ok := s.pickRoom(g.size, cell)
+		if !ok {
+			continue
+		}
+
+		// Один и тот же предмет дважды в день — дорого; при прочих равных — ранний урок.
+		day := cell / s.p.periods
+		penalty := s.perDay[subjectDay{g.classID, g.subjectID, day}]*s.p.periods*2 + cell%s.p.periods
+		opts = append(opts, solverOption{cell: cell, roomID: roomID, penalty: penalty})
+	}
+	return opts
+}
+
+// pickRoom — самый маленький свободный кабинет, куда помещается класс. Кабинеты отсортированы
+// по вместимости; вместимость 0 (не указана) подходит любому классу.
+func (s *solver) pickRoom(size, cell int) (*int64, bool) {
+	if len(s.p.rooms) == 0 {
+		return nil, true
+	}
+	for i := range s.p.rooms {
+		rm := &s.p.rooms[i]
+		if rm.Capacity > 0 && rm.Capacity < size {
+			continue
+		}
+		if s.roomBusy[rm.ID][cell] {
+			continue
+		}
+		if blocked := s.p.roomBlocked[rm.ID]; blocked != nil && blocked[cell] {
+			continue
+		}
+		return &rm.ID, true
+	}
+	return nil, false
+}
+
+func (s *solver) place(gi int, o solverOption) {
+	g := s.p.groups[gi]
+	s.classBusy[g.classID][o.cell] = true
+	s.teacherBusy[g.teacherID][o.cell] = true
+	if o.roomID != nil {
+		s.roomBusy[*o.roomID][o.cell] = true
+	}
+	s.perDay[subjectDay{g.classID, g.subjectID, o.cell / s.p.periods}]++
+	s.placed[gi] = append(s.placed[gi], o.cell)
+
+	s.stack = append(s.stack, solverPlacement{group: gi, solverOption: o})
+	if len(s.stack) > s.best {
+		s.best = len(s.stack)
+	}
+}
+
+func (s *solver) unplace() {
+	pl := s.stack[len(s.stack)-1]
+	s.stack = s.stack[:len(s.stack)-1]
+
+	g := s.p.groups[pl.group]
+	s.classBusy[g.classID][pl.cell] = false
+	s.teacherBusy[g.teacherID][pl.cell] = false
+	if pl.roomID != nil {
+		s.roomBusy[*pl.roomID][pl.cell] = false
+	}
+	s.perDay[subjectDay{g.classID, g.subjectID, pl.cell / s.p.periods}]--
+	s.placed[pl.group] = s.placed[pl.group][:len(s.placed[pl.group])-1]
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"restapi/internal/domain"
+)
+
+// blocked — занятость на cells ячеек, где заняты перечисленные.
+func blocked(cells int, busy ...int) []bool {
+	out := make([]bool, cells)
+	for _, c := range busy {
+		out[c] = true
+	}
+	return out
+}
+
+// checkSlots проверяет решение: все уроки расставлены, нет пересечений классов, учителей и кабинетов,
+// занятые извне ячейки не использованы, класс помещается в кабинет.
+func checkSlots(t *testing.T, p *solverProblem, slots []domain.TimetableSlot) {
+	t.Helper()
+
+	total := 0
+	for _, g := range p.groups {
+		total += g.count
+	}
+	if len(slots) != total {
+		t.Fatalf("got %d slots, want %d", len(slots), total)
+	}
+
+	type key struct {
+		id   int64
+		cell int
+	}
+	classes, teachers, rooms := map[key]bool{}, map[key]bool{}, map[key]bool{}
+	sizes := map[int64]int{}
+	for _, g := range p.groups {
+		sizes[g.classID] = g.size
+	}
+	capacity := map[int64]int{}
+	for _, rm := range p.rooms {
+		capacity[rm.ID] = rm.Capacity
+	}
+
+	for _, sl := range slots {
+		if sl.Day < 1 || sl.Day > p.days || sl.Period < 1 || sl.Period > p.periods {
+			t.Fatalf("slot out of grid: %+v", sl)
+		}
+		cell := (sl.Day-1)*p.periods + sl.Period - 1
+		if classes[key{sl.ClassID, cell}] {
+			t.Errorf("class %d double-booked at cell %d", sl.ClassID, cell)
+		}
+		classes[key{sl.ClassID, cell}] = true
+		if teachers[key{sl.TeacherID, cell}] {
+			t.Errorf("teacher %d double-booked at cell %d", sl.TeacherID, cell)
+		}
+		teachers[key{sl.TeacherID, cell}] = true
+		if b := p.teacherBlocked[sl.TeacherID]; b != nil && b[cell] {
+			t.Errorf("teacher %d is unavailable at cell %d", sl.TeacherID, cell)
+		}
+
+		if len(p.rooms) == 0 {
+			if sl.RoomID != nil {
+				t.Errorf("unexpected room %d", *sl.RoomID)
+			}
+			continue
+		}
+		if sl.RoomID == nil {
+			t.Fatalf("slot without room: %+v", sl)
+		}
+		room := *sl.RoomID
+		if rooms[key{room, cell}] {
+			t.Errorf("room %d double-booked at cell %d", room, cell)
+		}
+		rooms[key{room, cell}] = true
+		if b := p.roomBlocked[room]; b != nil && b[cell] {
+			t.Errorf("room %d is blocked at cell %d", room, cell)
+		}
+		if c := capacity[room]; c > 0 && c < sizes[sl.ClassID] {
+			t.Errorf("class %d (%d students) does not fit room %d (%d seats)", sl.ClassID, sizes[sl.ClassID], room, c)
+		}
+	}
+}
+
+func TestSolveTimetable(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       solverProblem
+		wantErr error
+		check   func(t *testing.T, slots []domain.TimetableSlot)
+	}{
+		{
+			name: "small feasible grid",
+			p: solverProblem{
+				days: 2, periods: 3,
+				groups: []solverGroup{
+					{classID: 1, subjectID: 10, teacherID: 100, count: 3},
+					{classID: 1, subjectID: 11, teacherID: 101, count: 2},
+					{classID: 2, subjectID: 10, teacherID: 100, count: 3},
+					{classID: 2, subjectID: 12, teacherID: 102, count: 3},
+				},
+			},
+		},
+		{
+			name: "same subject spread over days",
+			p: solverProblem{
+				days: 2, periods: 3,
+				groups: []solverGroup{{classID: 1, subjectID: 10, teacherID: 100, count: 2}},
+			},
+			check: func(t *testing.T, slots []domain.TimetableSlot) {
+				if slots[0].Day == slots[1].Day {
+					t.Errorf("both lessons on day %d, want different days", slots[0].Day)
+				}
+			},
+		},
+		{
+			name: "teacher unavailability",
+			p: solverProblem{
+				days: 1, periods: 3,
+				groups:         []solverGroup{{classID: 1, subjectID: 10, teacherID: 100, count: 1}},
+				teacherBlocked: map[int64][]bool{100: blocked(3, 0, 1)},
+			},
+			check: func(t *testing.T, slots []domain.TimetableSlot) {
+				if slots[0].Period != 3 {
+					t.Errorf("got period %d, want 3", slots[0].Period)
+				}
+			},
+		},
+		{
+			name: "teacher unavailability shared by two classes",
+			p: solverProblem{
+				days: 1, periods: 3,
+				groups: []solverGroup{
+					{classID: 1, subjectID: 10, teacherID: 100, count: 1},
+					{classID: 2, subjectID: 10, teacherID: 100, count: 1},
+				},
+				teacherBlocked: map[int64][]bool{100: blocked(3, 1)},
+			},
+		},
+		{
+			name: "smallest room that fits",
+			p: solverProblem{
+				days: 1, periods: 1,
+				groups: []solverGroup{{classID: 1, subjectID: 10, teacherID: 100, size: 20, count: 1}},
+				rooms:  []domain.Room{{ID: 1, Capacity: 10}, {ID: 2, Capacity: 25}, {ID: 3, Capacity: 40}},
+			},
+			check: func(t *testing.T, slots []domain.TimetableSlot) {
+				if got := *slots[0].RoomID; got != 2 {
+					t.Errorf("got room %d, want 2", got)
+				}
+			},
+		},
+		{
+			name: "next room when the smallest is blocked",
+			p: solverProblem{
+				days: 1, periods: 1,
+				groups:      []solverGroup{{classID: 1, subjectID: 10, teacherID: 100, size: 20, count: 1}},
+				rooms:       []domain.Room{{ID: 1, Capacity: 10}, {ID: 2, Capacity: 25}, {ID: 3, Capacity: 40}},
+				roomBlocked: map[int64][]bool{2: blocked(1, 0)},
+			},
+			check: func(t *testing.T, slots []domain.TimetableSlot) {
+				if got := *slots[0].RoomID; got != 3 {
+					t.Errorf("got room %d, want 3", got)
+				}
+			},
+		},
+		{
+			name: "room without capacity fits any class",
+			p: solverProblem{
+				days: 1, periods: 1,
+				groups: []solverGroup{{classID: 1, subjectID: 10, teacherID: 100, size: 35, count: 1}},
+				rooms:  []domain.Room{{ID: 1, Capacity: 30}, {ID: 2}},
+			},
+			check: func(t *testing.T, slots []domain.TimetableSlot) {
+				if got := *slots[0].RoomID; got != 2 {
+					t.Errorf("got room %d, want 2", got)
+				}
+			},
+		},
+		{
+			name: "two classes share one big room",
+			p: solverProblem{
+				days: 1, periods: 2,
+				groups: []solverGroup{
+					{classID: 1, subjectID: 10, teacherID: 100, size: 30, count: 1},
+					{classID: 2, subjectID: 11, teacherID: 101, size: 30, count: 1},
+				},
+				rooms: []domain.Room{{ID: 1, Capacity: 20}, {ID: 2, Capacity: 30}},
+			},
+		},
+		{
+			name: "class too big for every room",
+			p: solverProblem{
+				days: 1, periods: 2,
+				groups: []solverGroup{{classID: 1, subjectID: 10, teacherID: 100, size: 40, count: 1}},
+				rooms:  []domain.Room{{ID: 1, Capacity: 20}, {ID: 2, Capacity: 30}},
+			},
+			wantErr: errNoTimetable,
+		},
+		{
+			name: "more lessons than cells",
+			p: solverProblem{
+				days: 1, periods: 2,
+				groups: []solverGroup{{classID: 1, subjectID: 10, teacherID: 100, count: 3}},
+			},
+			wantErr: errNoTimetable,
+		},
+		{
+			name: "teacher overbooked across classes",
+			p: solverProblem{
+				days: 1, periods: 2,
+				groups: []solverGroup{
+					{classID: 1, subjectID: 10, teacherID: 100, count: 2},
+					{classID: 2, subjectID: 10, teacherID: 100, count: 1},
+				},
+			},
+			wantErr: errNoTimetable,
+		},
+		{
+			name: "teacher unavailable all week",
+			p: solverProblem{
+				days: 1, periods: 2,
+				groups:         []solverGroup{{classID: 1, subjectID: 10, teacherID: 100, count: 1}},
+				teacherBlocked: map[int64][]bool{100: blocked(2, 0, 1)},
+			},
+			wantErr: errNoTimetable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slots, err := solveTimetable(context.Background(), &tt.p, nil)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got err %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			checkSlots(t, &tt.p, slots)
+			if tt.check != nil {
+				tt.check(t, slots)
+			}
+		})
+	}
+}
+
+func TestSolveTimetableCancelled(t *testing.T) {
+	// Задача решается без возвратов, но узлов больше, чем между проверками контекста.
+	p := &solverProblem{days: 5, periods: 8}
+	for c := int64(1); c <= 20; c++ {
+		for s := int64(0); s < 4; s++ {
+			p.groups = append(p.groups, solverGroup{classID: c, subjectID: s, teacherID: c*10 + s, count: 10})
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls int
+	_, err := solveTimetable(ctx, p, func(placed, total int) { calls++ })
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got err %v, want context.Canceled", err)
+	}
+	if calls != 0 {
+		t.Errorf("progress called %d times after cancellation", calls)
+	}
+}
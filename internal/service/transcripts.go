@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+)
+
+type TranscriptRepository interface {
+	Courses(ctx context.Context, studentID int64) ([]domain.TranscriptCourse, error)
+	Issue(ctx context.Context, t domain.Transcript) error
+	ListIssued(ctx context.Context, studentID int64) ([]domain.IssuedTranscript, error)
+	Get(ctx context.Context, hash string) (domain.Transcript, error)
+}
+
+// TranscriptService составляет выписки об успеваемости за завершённые учебные годы и выдаёт
+// официальные выписки с хешем для проверки.
+type TranscriptService struct {
+	repo     TranscriptRepository
+	scales   GradingScaleRepository
+	students StudentRepository
+	terms    TermRepository
+	now      func() time.Time
+}
+
+func NewTranscriptService(repo TranscriptRepository, scales GradingScaleRepository, students StudentRepository, terms TermRepository) *TranscriptService {
+	return &TranscriptService{repo: repo, scales: scales, students: students, terms: terms, now: time.Now}
+}
+
+// Preview — выписка без выдачи (без хеша). scale — код шкалы, пустой — шкала по умолчанию.
+func (s *TranscriptService) Preview(ctx context.Context, studentID int64, scale string) (domain.Transcript, error) {
+	t, err := s.build(ctx, studentID, scale)
+	if err != nil {
+		return domain.Transcript{}, err
+	}
+	t.IssuedAt = s.now().UTC()
+	return t, nil
+}
+
+// Issue выдаёт официальную выписку: документ сохраняется, его SHA-256 становится кодом проверки.
+func (s *TranscriptService) Issue(ctx context.Context, actor domain.Principal, studentID int64, scale string) (domain.Transcript, error) {
+	t, err := s.build(ctx, studentID, scale)
+	if err != nil {
+		return domain.Transcript{}, err
+	}
+	if len(t.Years) == 0 {
+		return domain.Transcript{}, domainerr.Newf(domainerr.ErrConflict, "student has no completed courses")
+	}
+
+	t.IssuedAt = s.now().UTC()
+	if actor.ExecID != 0 {
+		t.IssuedBy = &actor.ExecID
+	}
+	if t.Hash, err = transcriptHash(t); err != nil {
+		return domain.Transcript{}, err
+	}
+	if err := s.repo.Issue(ctx, t); err != nil {
+		return domain.Transcript{}, err
+	}
+	return t, nil
+}
+
+func (s *TranscriptService) Issued(ctx context.Context, studentID int64) ([]domain.IssuedTranscript, error) {
+	if _, err := s.students.Get(ctx, studentID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListIssued(ctx, studentID)
+}
+
+// Get — выданная выписка ученика по хешу.
+func (s *TranscriptService) Get(ctx context.Context, studentID int64, hash string) (domain.Transcript, error) {
+	t, err := s.repo.Get(ctx, strings.ToLower(hash))
+	if err != nil {
+		return domain.Transcript{}, err
+	}
+	if t.StudentID != studentID {
+		return domain.Transcript{}, fmt.Errorf("transcript: %w", domainerr.ErrNotFound)
+	}
+	return t, nil
+}
+
+// Verify проверяет выписку по хешу: она должна быть выдана и не изменена после выдачи.
+func (s *TranscriptService) Verify(ctx context.Context, hash string) (domain.TranscriptVerification, error) {
+	hash = strings.ToLower(hash)
+	if b, err := hex.DecodeString(hash); err != nil || len(b) != sha256.Size {
+		return domain.TranscriptVerification{}, fmt.Errorf("transcript: %w", domainerr.ErrNotFound)
+	}
+
+	t, err := s.repo.Get(ctx, hash)
+	if err != nil {
+		return domain.TranscriptVerification{}, err
+	}
+	sum, err := transcriptHash(t)
+	if err != nil {
+		return domain.TranscriptVerification{}, err
+	}
+	return domain.TranscriptVerification{Valid: sum == hash, Transcript: t}, nil
+}
+
+// build собирает выписку: курсы только завершённых учебных годов, оценки по шкале, GPA.
+func (s *TranscriptService) build(ctx context.Context, studentID int64, scaleCode string) (domain.Transcript, error) {
+	st, err := s.students.Get(ctx, studentID)
+	if err != nil {
+		return domain.Transcript{}, err
+	}
+	scale, err := scaleByCode(ctx, s.scales, "scale", scaleCode)
+	if err != nil {
+		return domain.Transcript{}, err
+	}
+	courses, err := s.repo.Courses(ctx, st.ID)
+	if err != nil {
+		return domain.Transcript{}, err
+	}
+	completed, err := s.completedYears(ctx)
+	if err != nil {
+		return domain.Transcript{}, err
+	}
+
+	t := domain.Transcript{
+		StudentID:   st.ID,
+		StudentName: st.LastName + " " + st.FirstName,
+		BirthDate:   st.BirthDate,
+		Status:      st.Status,
+		Scale:       domain.TranscriptScale{Code: scale.Code, Name: scale.Name, MaxPoints: scale.MaxPoints, Bands: scale.Bands},
+		Years:       make([]domain.TranscriptYear, 0),
+	}
+
+	var totalPoints float64
+	for _, c := range courses {
+		if !completed[c.AcademicYear] {
+			continue
+		}
+		g := scale.Convert(c.Percent)
+		c.Grade, c.Points = g.Label, g.Points
+
+		if n := len(t.Years); n == 0 || t.Years[n-1].AcademicYear != c.AcademicYear {
+			t.Years = append(t.Years, domain.TranscriptYear{AcademicYear: c.AcademicYear, ClassName: c.ClassName})
+		}
+		y := &t.Years[len(t.Years)-1]
+		y.Courses = append(y.Courses, c)
+		y.Credits += c.Credits
+		y.GPA += c.Points * c.Credits
+
+		t.TotalCredits += c.Credits
+		totalPoints += c.Points * c.Credits
+	}
+
+	for i := range t.Years {
+		y := &t.Years[i]
+		y.GPA = weightedMean(y.GPA, y.Credits)
+	}
+	t.GPA = weightedMean(totalPoints, t.TotalCredits)
+	return t, nil
+}
+
+// completedYears — учебные годы, которые закончились по календарю или у которых закрыты все периоды.
+func (s *TranscriptService) completedYears(ctx context.Context) (map[int]bool, error) {
+	years, err := s.terms.ListYears(ctx)
+	if err != nil {
+		return nil, err
+	}
+	today := dateOnly(s.now())
+
+	out := make(map[int]bool, len(years))
+	for _, y := range years {
+		if y.EndDate.Before(today) {
+			out[y.Year] = true
+			continue
+		}
+		terms, err := s.terms.ListTerms(ctx, y.Year)
+		if err != nil {
+			return nil, err
+		}
+		closed := len(terms) > 0
+		for _, t := range terms {
+			closed = closed && t.Closed
+		}
+		out[y.Year] = closed
+	}
+	return out, nil
+}
+
+func weightedMean(sum, weight float64) float64 {
+	if weight == 0 {
+		return 0
+	}
+	return math.Round(sum/weight*100) / 100
+}
+
+// transcriptHash — SHA-256 канонического JSON выписки без самого хеша.
+func transcriptHash(t domain.Transcript) (string, error) {
+	t.Hash = ""
+	b, err := json.Marshal(t)
+	if err != nil {
+		return "", fmt.Errorf("transcript hash: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
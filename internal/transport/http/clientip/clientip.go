@@ -0,0 +1,138 @@
+// Package clientip определяет адрес клиента за обратными прокси. Заголовкам прокси верим только
+// от доверенных адресов: иначе любой клиент подставит себе чужой IP в X-Forwarded-For.
+package clientip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+type ctxKey struct{}
+
+func NewContext(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, ip)
+}
+
+// FromContext возвращает адрес клиента или "", если middleware ClientIP не отработал.
+func FromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(ctxKey{}).(string)
+	return ip
+}
+
+// Resolver находит адрес клиента по цепочке прокси.
+type Resolver struct {
+	trusted []netip.Prefix
+}
+
+// NewResolver принимает доверенные сети прокси в виде CIDR или отдельных адресов.
+// Пустой список — заголовки прокси игнорируются, адрес берётся из соединения.
+func NewResolver(trusted []string) (*Resolver, error) {
+	r := &Resolver{}
+	for _, s := range trusted {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			addr, aerr := netip.ParseAddr(s)
+			if aerr != nil {
+				return nil, fmt.Errorf("clientip: invalid trusted proxy %q", s)
+			}
+			p = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		r.trusted = append(r.trusted, p.Masked())
+	}
+	return r, nil
+}
+
+// ClientIP — адрес клиента. Если соединение пришло от доверенного прокси, цепочка из Forwarded
+// (RFC 7239), иначе X-Forwarded-For, иначе X-Real-IP просматривается справа налево: клиент —
+// первый недоверенный адрес. Нечитаемое звено обрывает цепочку на последнем доверенном прокси.
+func (res *Resolver) ClientIP(r *http.Request) string {
+	peer, ok := parseHost(r.RemoteAddr)
+	if !ok {
+		return r.RemoteAddr
+	}
+	if !res.isTrusted(peer) {
+		return peer.String()
+	}
+
+	var chain []string
+	switch {
+	case r.Header.Get("Forwarded") != "":
+		chain = forwardedFor(r.Header.Values("Forwarded"))
+	case r.Header.Get("X-Forwarded-For") != "":
+		chain = splitList(r.Header.Values("X-Forwarded-For"))
+	case r.Header.Get("X-Real-IP") != "":
+		chain = []string{strings.TrimSpace(r.Header.Get("X-Real-IP"))}
+	}
+
+	client := peer
+	for i := len(chain) - 1; i >= 0; i-- {
+		addr, ok := parseHost(chain[i])
+		if !ok {
+			break
+		}
+		client = addr
+		if !res.isTrusted(addr) {
+			break
+		}
+	}
+	return client.String()
+}
+
+func (res *Resolver) isTrusted(addr netip.Addr) bool {
+	for _, p := range res.trusted {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHost разбирает адрес с портом или без, в том числе "[::1]:8080"; IPv4-in-IPv6 сводится к IPv4.
+func parseHost(s string) (netip.Addr, bool) {
+	s = strings.TrimSpace(s)
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap().WithZone(""), true
+}
+
+// forwardedFor — значения for= из заголовков Forwarded по порядку прокси. Пары без for= и
+// скрытые узлы ("unknown", "_hidden") попадают в цепочку как есть и обрывают её.
+func forwardedFor(values []string) []string {
+	var out []string
+	for _, elem := range splitList(values) {
+		node := ""
+		for _, pair := range strings.Split(elem, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if ok && strings.EqualFold(name, "for") {
+				node = strings.Trim(value, `"`)
+			}
+		}
+		out = append(out, node)
+	}
+	return out
+}
+
+// splitList разбирает заголовки-списки через запятую (несколько строк заголовка — один список).
+func splitList(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			out = append(out, strings.TrimSpace(part))
+		}
+	}
+	return out
+}
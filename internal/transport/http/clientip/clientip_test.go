@@ -0,0 +1,155 @@
+package clientip
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestResolverClientIP(t *testing.T) {
+	res, err := NewResolver([]string{"10.0.0.0/8", " 192.168.1.1 ", "::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		remote string
+		header map[string][]string
+		want   string
+	}{
+		{
+			name:   "untrusted peer spoofs X-Forwarded-For",
+			remote: "203.0.113.5:4000",
+			header: map[string][]string{"X-Forwarded-For": {"1.1.1.1"}},
+			want:   "203.0.113.5",
+		},
+		{
+			name:   "untrusted peer spoofs Forwarded and X-Real-IP",
+			remote: "203.0.113.5:4000",
+			header: map[string][]string{"Forwarded": {"for=1.1.1.1"}, "X-Real-Ip": {"1.1.1.2"}},
+			want:   "203.0.113.5",
+		},
+		{
+			name:   "trusted peer without proxy headers",
+			remote: "10.0.0.2:4000",
+			want:   "10.0.0.2",
+		},
+		{
+			name:   "trusted chain resolved right to left",
+			remote: "10.0.0.2:4000",
+			header: map[string][]string{"X-Forwarded-For": {"1.1.1.1, 198.51.100.7, 192.168.1.1, 10.0.0.9"}},
+			want:   "198.51.100.7",
+		},
+		{
+			name:   "chain split across header lines",
+			remote: "10.0.0.2:4000",
+			header: map[string][]string{"X-Forwarded-For": {"198.51.100.7", "10.0.0.9"}},
+			want:   "198.51.100.7",
+		},
+		{
+			name:   "whole chain trusted",
+			remote: "10.0.0.2:4000",
+			header: map[string][]string{"X-Forwarded-For": {"10.0.0.3, 10.0.0.4"}},
+			want:   "10.0.0.3",
+		},
+		{
+			name:   "Forwarded with quoted IPv6 and port",
+			remote: "10.0.0.2:4000",
+			header: map[string][]string{"Forwarded": {`for=192.0.2.60;proto=http, For="[2001:db8:cafe::17]:4711";by=10.0.0.1`}},
+			want:   "2001:db8:cafe::17",
+		},
+		{
+			name:   "Forwarded with quoted IPv4 and port",
+			remote: "10.0.0.2:4000",
+			header: map[string][]string{"Forwarded": {`for="192.0.2.60:8080"`}},
+			want:   "192.0.2.60",
+		},
+		{
+			name:   "Forwarded wins over X-Forwarded-For",
+			remote: "10.0.0.2:4000",
+			header: map[string][]string{"Forwarded": {"for=192.0.2.60"}, "X-Forwarded-For": {"198.51.100.7"}},
+			want:   "192.0.2.60",
+		},
+		{
+			name:   "malformed entry breaks the chain",
+			remote: "10.0.0.2:4000",
+			header: map[string][]string{"X-Forwarded-For": {"198.51.100.7, not-an-ip, 10.0.0.9"}},
+			want:   "10.0.0.9",
+		},
+		{
+			name:   "hidden Forwarded node breaks the chain",
+			remote: "10.0.0.2:4000",
+			header: map[string][]string{"Forwarded": {"for=198.51.100.7, for=_hidden, for=10.0.0.9"}},
+			want:   "10.0.0.9",
+		},
+		{
+			name:   "malformed entry next to the peer",
+			remote: "10.0.0.2:4000",
+			header: map[string][]string{"X-Forwarded-For": {"198.51.100.7, unknown"}},
+			want:   "10.0.0.2",
+		},
+		{
+			name:   "X-Real-IP fallback",
+			remote: "10.0.0.2:4000",
+			header: map[string][]string{"X-Real-Ip": {" 198.51.100.8 "}},
+			want:   "198.51.100.8",
+		},
+		{
+			name:   "trusted IPv6 peer, IPv4-mapped client",
+			remote: "[::1]:4000",
+			header: map[string][]string{"X-Forwarded-For": {"::ffff:192.0.2.1"}},
+			want:   "192.0.2.1",
+		},
+		{
+			name:   "peer without port",
+			remote: "203.0.113.5",
+			want:   "203.0.113.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remote
+			for k, vs := range tt.header {
+				for _, v := range vs {
+					r.Header.Add(k, v)
+				}
+			}
+			if got := res.ClientIP(r); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolverNoTrustedProxies(t *testing.T) {
+	res, err := NewResolver(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "10.0.0.2:4000"
+	r.Header.Set("X-Forwarded-For", "198.51.100.7")
+	if got := res.ClientIP(r); got != "10.0.0.2" {
+		t.Errorf("got %s, want 10.0.0.2", got)
+	}
+}
+
+func TestNewResolverInvalid(t *testing.T) {
+	for _, s := range []string{"10.0.0.0/33", "proxy.local", "10.0.0"} {
+		if _, err := NewResolver([]string{s}); err == nil {
+			t.Errorf("%q: want error", s)
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != "" {
+		t.Errorf("empty context: got %q", got)
+	}
+	if got := FromContext(NewContext(context.Background(), "192.0.2.1")); got != "192.0.2.1" {
+		t.Errorf("got %q, want 192.0.2.1", got)
+	}
+}
@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"net/http"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+	"restapi/internal/service"
+	"restapi/internal/transport/http/middlewares"
+)
+
+type AttendanceHandler struct {
+	svc       *service.AttendanceService
+	maxUpload int64 // предел тела multipart-запроса, байт
+}
+
+func NewAttendanceHandler(svc *service.AttendanceService, maxUpload int64) *AttendanceHandler {
+	return &AttendanceHandler{svc: svc, maxUpload: maxUpload}
+}
+
+// Reasons — GET /absence-reasons
+func (h *AttendanceHandler) Reasons(w http.ResponseWriter, r *http.Request) {
+	list, err := h.svc.Reasons(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// RecordRollCall — POST /classes/{id}/attendance
+// 201 — новая перекличка, 200 — перезапись (overwrite), 409 — перекличка уже есть.
+func (h *AttendanceHandler) RecordRollCall(w http.ResponseWriter, r *http.Request) {
+	classID, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+
+	var in domain.RollCallInput
+	if err := decodeJSON(w, r, &in); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	res, created, err := h.svc.RecordRollCall(r.Context(), actor, classID, in)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, res)
+}
+
+// ClassDay — GET /classes/{id}/attendance?date=YYYY-MM-DD
+func (h *AttendanceHandler) ClassDay(w http.ResponseWriter, r *http.Request) {
+	classID, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	date, err := queryDate(r, "date")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	list, err := h.svc.ClassDay(r.Context(), classID, date)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// ClassSummary — GET /classes/{id}/attendance/summary?from=&to= (по умолчанию — текущий период)
+func (h *AttendanceHandler) ClassSummary(w http.ResponseWriter, r *http.Request) {
+	classID, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	p, err := dateRange(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	res, err := h.svc.ClassSummary(r.Context(), classID, p)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+// StudentAttendance — GET /students/{id}/attendance?from=&to= (по умолчанию — текущий период)
+func (h *AttendanceHandler) StudentAttendance(w http.ResponseWriter, r *http.Request) {
+	studentID, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	p, err := dateRange(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	res, err := h.svc.StudentAttendance(r.Context(), studentID, p)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+// Excuses — GET /students/{id}/excuses
+func (h *AttendanceHandler) Excuses(w http.ResponseWriter, r *http.Request) {
+	studentID, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	list, err := h.svc.Excuses(r.Context(), studentID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// SubmitExcuse — POST /students/{id}/excuses
+func (h *AttendanceHandler) SubmitExcuse(w http.ResponseWriter, r *http.Request) {
+	studentID, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+
+	var e domain.ExcuseNote
+	if err := decodeJSON(w, r, &e); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	created, err := h.svc.SubmitExcuse(r.Context(), actor, studentID, e)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// AddExcuseFiles — POST /students/{id}/excuses/{excuseId}/files (multipart, поле files)
+func (h *AttendanceHandler) AddExcuseFiles(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	studentID, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	excuseID, err := pathID(r, "excuseId")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	form, err := parseUpload(w, r, h.maxUpload)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	defer form.close()
+
+	files, err := h.svc.AddExcuseFiles(r.Context(), actor, studentID, excuseID, form.files)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, files)
+}
+
+// ExcuseFile — GET /students/{id}/excuses/{excuseId}/files/{fileId}: редирект на подписанную ссылку.
+func (h *AttendanceHandler) ExcuseFile(w http.ResponseWriter, r *http.Request) {
+	studentID, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	excuseID, err := pathID(r, "excuseId")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	fileID, err := pathID(r, "fileId")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	url, err := h.svc.ExcuseFileURL(r.Context(), studentID, excuseID, fileID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	redirectToFile(w, r, url)
+}
+
+// ReviewExcuse — POST /excuses/{id}/review
+func (h *AttendanceHandler) ReviewExcuse(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+
+	var review domain.ExcuseReview
+	if err := decodeJSON(w, r, &review); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	res, err := h.svc.ReviewExcuse(r.Context(), actor, id, review)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func dateRange(r *http.Request) (domain.DateRange, error) {
+	var (
+		p   domain.DateRange
+		err error
+	)
+	if p.From, err = queryDate(r, "from"); err != nil {
+		return p, err
+	}
+	if p.To, err = queryDate(r, "to"); err != nil {
+		return p, err
+	}
+	return p, nil
+}
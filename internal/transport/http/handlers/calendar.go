@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+	"restapi/internal/service"
+	"restapi/internal/transport/http/ical"
+	"restapi/internal/transport/http/middlewares"
+)
+
+type CalendarHandler struct {
+	svc       *service.CalendarService
+	auth      *service.AuthService
+	guardians *service.GuardianService
+}
+
+func NewCalendarHandler(svc *service.CalendarService, auth *service.AuthService, guardians *service.GuardianService) *CalendarHandler {
+	return &CalendarHandler{svc: svc, auth: auth, guardians: guardians}
+}
+
+// ClassCalendar — GET /classes/{id}/calendar.ics
+func (h *CalendarHandler) ClassCalendar(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "class-", h.svc.ClassEvents)
+}
+
+// TeacherCalendar — GET /teachers/{id}/calendar.ics
+func (h *CalendarHandler) TeacherCalendar(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "teacher-", h.svc.TeacherEvents)
+}
+
+// StudentCalendar — GET /students/{id}/calendar.ics
+func (h *CalendarHandler) StudentCalendar(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "student-", h.svc.StudentEvents)
+}
+
+func (h *CalendarHandler) serve(w http.ResponseWriter, r *http.Request, name string, events func(context.Context, int64) ([]domain.CalendarEvent, error)) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	evs, err := events(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", ical.ContentType)
+	w.Header().Set("Content-Disposition", `inline; filename="`+name+itoa(id)+`.ics"`)
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	_ = ical.Encode(w, ical.Calendar{
+		Name:     "Timetable: " + name + itoa(id),
+		Location: h.svc.Location(),
+		Events:   evs,
+	})
+}
+
+// IssueToken — POST /execs/me/calendar-token. Выдаёт новую персональную ссылку, прежняя перестаёт работать.
+func (h *CalendarHandler) IssueToken(w http.ResponseWriter, r *http.Request) {
+	p, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+
+	token, err := h.auth.IssueCalendarToken(r.Context(), p.ExecID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	feeds, err := h.feedPaths(r.Context(), p, token)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, domain.CalendarToken{Token: token, Feeds: feeds})
+}
+
+// RevokeToken — DELETE /execs/me/calendar-token
+func (h *CalendarHandler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	p, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+
+	if err := h.auth.RevokeCalendarToken(r.Context(), p.ExecID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// feedPaths — личные ленты учётки (у представителя — ленты детей); ленты классов доступны с тем же токеном.
+func (h *CalendarHandler) feedPaths(ctx context.Context, p domain.Principal, token string) ([]string, error) {
+	feeds := make([]string, 0, 2)
+	if p.TeacherID != 0 {
+		feeds = append(feeds, "/teachers/"+itoa(p.TeacherID)+"/calendar.ics?token="+token)
+	}
+	if p.StudentID != 0 {
+		feeds = append(feeds, "/students/"+itoa(p.StudentID)+"/calendar.ics?token="+token)
+	}
+	if p.GuardianID != 0 {
+		wards, err := h.guardians.Wards(ctx, p.GuardianID)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range wards {
+			feeds = append(feeds, "/students/"+itoa(w.ID)+"/calendar.ics?token="+token)
+		}
+	}
+	return feeds, nil
+}
@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"net/http"
+
+	"restapi/internal/domain"
+	"restapi/internal/service"
+)
+
+type ClassesHandler struct {
+	svc      *service.ClassService
+	students *service.StudentService
+}
+
+func NewClassesHandler(svc *service.ClassService, students *service.StudentService) *ClassesHandler {
+	return &ClassesHandler{svc: svc, students: students}
+}
+
+// List — GET /classes?academic_year=&grade=&limit=&offset=
+func (h *ClassesHandler) List(w http.ResponseWriter, r *http.Request) {
+	var (
+		f   domain.ClassFilter
+		err error
+	)
+	if f.AcademicYear, err = queryInt(r, "academic_year"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if f.Grade, err = queryInt(r, "grade"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if f.Limit, err = queryInt(r, "limit"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if f.Offset, err = queryInt(r, "offset"); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	classes, err := h.svc.List(r.Context(), f)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, classes)
+}
+
+// Get — GET /classes/{id}
+func (h *ClassesHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	c, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c)
+}
+
+// Create — POST /classes
+func (h *ClassesHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var c domain.Class
+	if err := decodeJSON(w, r, &c); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	created, err := h.svc.Create(r.Context(), c)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/classes/"+itoa(created.ID))
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// Update — PUT /classes/{id}
+func (h *ClassesHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var c domain.Class
+	if err := decodeJSON(w, r, &c); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	c.ID = id
+
+	updated, err := h.svc.Update(r.Context(), c)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// Delete — DELETE /classes/{id}
+func (h *ClassesHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Students — GET /classes/{id}/students
+func (h *ClassesHandler) Students(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if _, err := h.svc.Get(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	students, err := h.students.List(r.Context(), domain.StudentFilter{ClassID: id, Limit: 500})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, students)
+}
+
+// Subjects — GET /classes/{id}/subjects
+func (h *ClassesHandler) Subjects(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	list, err := h.svc.Subjects(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+type assignRequest struct {
+	SubjectID    int64 `json:"subject_id"`
+	TeacherID    int64 `json:"teacher_id"`
+	HoursPerWeek int   `json:"hours_per_week"`
+}
+
+// Assign — POST /classes/{id}/subjects {"subject_id", "teacher_id", "hours_per_week"}
+func (h *ClassesHandler) Assign(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req assignRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	a, err := h.svc.Assign(r.Context(), domain.Assignment{
+		ClassID:      id,
+		SubjectID:    req.SubjectID,
+		TeacherID:    req.TeacherID,
+		HoursPerWeek: req.HoursPerWeek,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/classes/"+itoa(id)+"/subjects/"+itoa(a.SubjectID))
+	writeJSON(w, http.StatusCreated, a)
+}
+
+// Reassign — PUT /classes/{id}/subjects/{subjectId} {"teacher_id", "hours_per_week"}
+func (h *ClassesHandler) Reassign(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	subjectID, err := pathID(r, "subjectId")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req assignRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	a, err := h.svc.Reassign(r.Context(), domain.Assignment{
+		ClassID:      id,
+		SubjectID:    subjectID,
+		TeacherID:    req.TeacherID,
+		HoursPerWeek: req.HoursPerWeek,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, a)
+}
+
+// Unassign — DELETE /classes/{id}/subjects/{subjectId}
+func (h *ClassesHandler) Unassign(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	subjectID, err := pathID(r, "subjectId")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.Unassign(r.Context(), id, subjectID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TeacherLoad — GET /teachers/{id}/assignments?academic_year=
+func (h *ClassesHandler) TeacherLoad(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	year, err := queryInt(r, "academic_year")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	load, err := h.svc.TeacherLoad(r.Context(), id, year)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, load)
+}
@@ -1,11 +1,200 @@
 package handlers
 
 import (
-	"fmt"
 	"net/http"
+	"strings"
+	"time"
+
+	"restapi/internal/config"
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+	"restapi/internal/service"
+	"restapi/internal/transport/http/middlewares"
 )
 
-func ExecsHandler(w http.ResponseWriter, r *http.Request) {
-	w.Write([]byte("Hello, execs Path!"))
-	fmt.Println("Hello, execs Path!")
+type ExecsHandler struct {
+	svc        *service.ExecService
+	auth       *service.AuthService
+	cfg        config.Auth
+	loginLimit *middlewares.RateLimiter // попытки входа в одну учётку
+}
+
+func NewExecsHandler(svc *service.ExecService, auth *service.AuthService, cfg config.Auth, loginLimit *middlewares.RateLimiter) *ExecsHandler {
+	return &ExecsHandler{svc: svc, auth: auth, cfg: cfg, loginLimit: loginLimit}
+}
+
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type loginResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Login — POST /execs/login. Токен отдаём и в HttpOnly-cookie (браузер), и в теле (API-клиенты).
+func (h *ExecsHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Лимит на IP не остановит подбор пароля к одной учётке с многих адресов — считаем попытки и по имени.
+	if req.Username != "" && !h.loginLimit.Check(w, r, strings.ToLower(req.Username)) {
+		return
+	}
+
+	token, sess, err := h.auth.Login(r.Context(), req.Username, req.Password, service.LoginMeta{
+		UserAgent: r.UserAgent(),
+		IP:        middlewares.ClientIPOf(r),
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     h.cfg.CookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  sess.CreatedAt.Add(h.cfg.SessionMaxLifetime),
+		HttpOnly: true,
+		Secure:   h.cfg.CookieSecure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	writeJSON(w, http.StatusOK, loginResponse{Token: token, ExpiresAt: sess.ExpiresAt})
+}
+
+// Logout — POST /execs/logout
+func (h *ExecsHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	if err := h.auth.Logout(r.Context(), middlewares.SessionToken(r, h.cfg.CookieName)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     h.cfg.CookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   h.cfg.CookieSecure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Me — GET /execs/me
+func (h *ExecsHandler) Me(w http.ResponseWriter, r *http.Request) {
+	p, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+
+	e, err := h.svc.Get(r.Context(), p.ExecID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, e)
+}
+
+// List — GET /execs?search=&limit=&offset=
+func (h *ExecsHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	offset, err := queryInt(r, "offset")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	execs, err := h.svc.List(r.Context(), r.URL.Query().Get("search"), limit, offset)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, execs)
+}
+
+// Get — GET /execs/{id}
+func (h *ExecsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	e, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, e)
+}
+
+// Create — POST /execs
+func (h *ExecsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var in domain.ExecInput
+	if err := decodeJSON(w, r, &in); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	created, err := h.svc.Create(r.Context(), in)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/execs/"+itoa(created.ID))
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// Patch — PATCH /execs/{id}
+func (h *ExecsHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var p domain.ExecPatch
+	if err := decodeJSON(w, r, &p); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	updated, err := h.svc.Patch(r.Context(), id, p)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// Delete — DELETE /execs/{id}
+func (h *ExecsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"time"
+
+	domainerr "restapi/internal/domain/errors"
+	log "restapi/internal/logger"
+	"restapi/internal/service"
+)
+
+// multipartMemory — часть multipart-тела, которая держится в памяти; остальное net/http
+// сбрасывает во временные файлы. Лимиты на сам файл проверяет сервис.
+const multipartMemory = 1 << 20
+
+// SignedFiles — хранилище, которое отдаёт файлы через API по подписанным ссылкам (локальный диск).
+type SignedFiles interface {
+	Verify(key string, q url.Values) (name, contentType string, err error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+type FilesHandler struct {
+	files SignedFiles
+}
+
+func NewFilesHandler(files SignedFiles) *FilesHandler {
+	return &FilesHandler{files: files}
+}
+
+// Download — GET /files/{key...}?exp=&name=&type=&sig=: файл по подписанной ссылке, без авторизации.
+func (h *FilesHandler) Download(w http.ResponseWriter, r *http.Request) {
+	key := r.PathValue("key")
+	name, contentType, err := h.files.Verify(key, r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	rc, err := h.files.Open(r.Context(), key)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	defer rc.Close()
+
+	hdr := w.Header()
+	hdr.Set("Content-Type", contentType)
+	// Без имени (фотографии) файл показывается в браузере, с именем — скачивается.
+	if name != "" {
+		disposition := mime.FormatMediaType("attachment", map[string]string{"filename": name})
+		if disposition == "" {
+			disposition = "attachment"
+		}
+		hdr.Set("Content-Disposition", disposition)
+	}
+	hdr.Set("X-Content-Type-Options", "nosniff")
+	hdr.Set("Cache-Control", "private, no-store")
+
+	// Файл с диска поддерживает Seek: ServeContent сам выставит длину и обработает Range.
+	if rs, ok := rc.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, "", time.Time{}, rs)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	// После заголовков ошибку чтения можно только залогировать — ответ обрывается.
+	if _, err := io.Copy(w, rc); err != nil {
+		log.Warn("serve file", "key", key, "err", err)
+	}
+}
+
+// redirectToFile отправляет клиента за файлом по подписанной ссылке. Ссылка временная — не кешируем.
+func redirectToFile(w http.ResponseWriter, r *http.Request, url string) {
+	w.Header().Set("Cache-Control", "no-store")
+	http.Redirect(w, r, url, http.StatusFound)
+}
+
+// uploadForm — разобранный multipart-запрос; close закрывает файлы и удаляет временные.
+type uploadForm struct {
+	form  *multipart.Form
+	files []service.Upload
+	open  []multipart.File
+}
+
+func isMultipart(r *http.Request) bool {
+	mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return mt == "multipart/form-data"
+}
+
+// parseUpload разбирает multipart/form-data не больше limit байт: файлы берутся из поля files.
+func parseUpload(w http.ResponseWriter, r *http.Request, limit int64) (*uploadForm, error) {
+	return parseUploadField(w, r, limit, "files")
+}
+
+func parseUploadField(w http.ResponseWriter, r *http.Request, limit int64, field string) (*uploadForm, error) {
+	if !isMultipart(r) {
+		return nil, domainerr.Newf(domainerr.ErrBadInput, "want multipart/form-data")
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, err
+		}
+		return nil, domainerr.Newf(domainerr.ErrBadInput, "invalid multipart body: %v", err)
+	}
+
+	u := &uploadForm{form: r.MultipartForm}
+	for _, fh := range r.MultipartForm.File[field] {
+		f, err := fh.Open()
+		if err != nil {
+			u.close()
+			return nil, fmt.Errorf("open upload %q: %w", fh.Filename, err)
+		}
+		u.open = append(u.open, f)
+		u.files = append(u.files, service.Upload{
+			Name: fh.Filename, ContentType: fh.Header.Get("Content-Type"), Size: fh.Size, Body: f,
+		})
+	}
+	return u, nil
+}
+
+func (u *uploadForm) value(name string) string {
+	if v := u.form.Value[name]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (u *uploadForm) close() {
+	for _, f := range u.open {
+		_ = f.Close()
+	}
+	if err := u.form.RemoveAll(); err != nil {
+		log.Warn("remove multipart temp files", "err", err)
+	}
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"net/http"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+	"restapi/internal/service"
+	"restapi/internal/transport/http/middlewares"
+)
+
+type GradesHandler struct {
+	svc *service.GradeService
+}
+
+func NewGradesHandler(svc *service.GradeService) *GradesHandler {
+	return &GradesHandler{svc: svc}
+}
+
+// Categories — GET /grade-categories
+func (h *GradesHandler) Categories(w http.ResponseWriter, r *http.Request) {
+	list, err := h.svc.Categories(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// CreateCategory — POST /grade-categories
+func (h *GradesHandler) CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var c domain.GradeCategory
+	if err := decodeJSON(w, r, &c); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	c.ID = 0
+
+	created, err := h.svc.SaveCategory(r.Context(), c)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// UpdateCategory — PUT /grade-categories/{id}
+func (h *GradesHandler) UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var c domain.GradeCategory
+	if err := decodeJSON(w, r, &c); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	c.ID = id
+
+	updated, err := h.svc.SaveCategory(r.Context(), c)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// RecordBatch — POST /classes/{id}/grades
+func (h *GradesHandler) RecordBatch(w http.ResponseWriter, r *http.Request) {
+	classID, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+
+	var b domain.GradeBatch
+	if err := decodeJSON(w, r, &b); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	res, err := h.svc.RecordBatch(r.Context(), actor, classID, b)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	status := http.StatusOK
+	if b.AssessmentID == 0 {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, res)
+}
+
+// ClassGrades — GET /classes/{id}/grades?academic_year=&term=&subject_id= (по умолчанию — текущий период)
+func (h *GradesHandler) ClassGrades(w http.ResponseWriter, r *http.Request) {
+	classID, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	f, err := gradeFilter(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	grades, err := h.svc.ClassGrades(r.Context(), classID, f)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, grades)
+}
+
+// Assessments — GET /classes/{id}/assessments?term=&subject_id=
+func (h *GradesHandler) Assessments(w http.ResponseWriter, r *http.Request) {
+	classID, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	f, err := gradeFilter(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	list, err := h.svc.Assessments(r.Context(), classID, f)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// Rankings — GET /classes/{id}/rankings?term=&subject_id= (по умолчанию — текущий период)
+func (h *GradesHandler) Rankings(w http.ResponseWriter, r *http.Request) {
+	classID, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	f, err := gradeFilter(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	list, err := h.svc.ClassRanking(r.Context(), classID, f)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// DeleteAssessment — DELETE /assessments/{id}
+func (h *GradesHandler) DeleteAssessment(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+
+	if err := h.svc.DeleteAssessment(r.Context(), actor, id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// StudentGrades — GET /students/{id}/grades?academic_year=&term=&subject_id= (по умолчанию — текущий период)
+func (h *GradesHandler) StudentGrades(w http.ResponseWriter, r *http.Request) {
+	studentID, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	f, err := gradeFilter(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+
+	res, err := h.svc.StudentGrades(r.Context(), actor, studentID, f)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+func gradeFilter(r *http.Request) (domain.GradeFilter, error) {
+	var (
+		f   domain.GradeFilter
+		err error
+	)
+	if f.AcademicYear, err = queryInt(r, "academic_year"); err != nil {
+		return f, err
+	}
+	if f.Term, err = queryInt(r, "term"); err != nil {
+		return f, err
+	}
+	subjectID, err := queryInt(r, "subject_id")
+	if err != nil {
+		return f, err
+	}
+	f.SubjectID = int64(subjectID)
+	return f, nil
+}
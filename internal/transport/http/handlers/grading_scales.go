@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+
+	"restapi/internal/domain"
+	"restapi/internal/service"
+)
+
+type GradingScalesHandler struct {
+	svc *service.GradingScaleService
+}
+
+func NewGradingScalesHandler(svc *service.GradingScaleService) *GradingScalesHandler {
+	return &GradingScalesHandler{svc: svc}
+}
+
+// List — GET /grading-scales
+func (h *GradingScalesHandler) List(w http.ResponseWriter, r *http.Request) {
+	scales, err := h.svc.List(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, scales)
+}
+
+// Get — GET /grading-scales/{id}
+func (h *GradingScalesHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s)
+}
+
+// Create — POST /grading-scales
+func (h *GradingScalesHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var s domain.GradingScale
+	if err := decodeJSON(w, r, &s); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	created, err := h.svc.Create(r.Context(), s)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/grading-scales/"+itoa(created.ID))
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// Update — PUT /grading-scales/{id}
+func (h *GradingScalesHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var s domain.GradingScale
+	if err := decodeJSON(w, r, &s); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	s.ID = id
+
+	updated, err := h.svc.Update(r.Context(), s)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// Delete — DELETE /grading-scales/{id}
+func (h *GradingScalesHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Convert — GET /grading-scales/convert?from=letter&to=five_point&value=B
+func (h *GradingScalesHandler) Convert(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	c, err := h.svc.Convert(r.Context(), q.Get("from"), q.Get("to"), q.Get("value"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c)
+}
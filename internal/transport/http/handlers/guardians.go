@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"net/http"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+	"restapi/internal/service"
+	"restapi/internal/transport/http/middlewares"
+)
+
+type GuardiansHandler struct {
+	svc *service.GuardianService
+}
+
+func NewGuardiansHandler(svc *service.GuardianService) *GuardiansHandler {
+	return &GuardiansHandler{svc: svc}
+}
+
+// List — GET /guardians?search=&limit=&offset=
+func (h *GuardiansHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	offset, err := queryInt(r, "offset")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	guardians, err := h.svc.List(r.Context(), domain.GuardianFilter{
+		Search: r.URL.Query().Get("search"),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, guardians)
+}
+
+// Get — GET /guardians/{id}
+func (h *GuardiansHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	g, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, g)
+}
+
+// Create — POST /guardians
+func (h *GuardiansHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var g domain.Guardian
+	if err := decodeJSON(w, r, &g); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	created, err := h.svc.Create(r.Context(), g)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/guardians/"+itoa(created.ID))
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// Update — PUT /guardians/{id}
+func (h *GuardiansHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var g domain.Guardian
+	if err := decodeJSON(w, r, &g); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	g.ID = id
+
+	updated, err := h.svc.Update(r.Context(), g)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// Delete — DELETE /guardians/{id}
+func (h *GuardiansHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Wards — GET /guardians/{id}/students
+func (h *GuardiansHandler) Wards(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	wards, err := h.svc.Wards(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, wards)
+}
+
+// StudentGuardians — GET /students/{id}/guardians
+func (h *GuardiansHandler) StudentGuardians(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	guardians, err := h.svc.StudentGuardians(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, guardians)
+}
+
+// Link — PUT /students/{id}/guardians/{guardianId}
+func (h *GuardiansHandler) Link(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	guardianID, err := pathID(r, "guardianId")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var l domain.GuardianLink
+	if err := decodeJSON(w, r, &l); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	guardians, err := h.svc.Link(r.Context(), id, guardianID, l)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, guardians)
+}
+
+// Unlink — DELETE /students/{id}/guardians/{guardianId}
+func (h *GuardiansHandler) Unlink(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	guardianID, err := pathID(r, "guardianId")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.Unlink(r.Context(), id, guardianID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Invite — POST /guardians/{id}/invites. Код показывается один раз — его передают представителю.
+func (h *GuardiansHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	inv, err := h.svc.Invite(r.Context(), actor, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, inv)
+}
+
+// Redeem — POST /guardian-invites/redeem. Без сессии: по коду представитель заводит себе учётку.
+func (h *GuardiansHandler) Redeem(w http.ResponseWriter, r *http.Request) {
+	var in domain.InviteRedemption
+	if err := decodeJSON(w, r, &in); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	e, err := h.svc.Redeem(r.Context(), in)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/execs/"+itoa(e.ID))
+	writeJSON(w, http.StatusCreated, e)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+
+	"restapi/internal/domain"
+	"restapi/internal/service"
+)
+
+type HolidaysHandler struct {
+	svc *service.HolidayService
+}
+
+func NewHolidaysHandler(svc *service.HolidayService) *HolidaysHandler {
+	return &HolidaysHandler{svc: svc}
+}
+
+// List — GET /holidays?from=&to=
+func (h *HolidaysHandler) List(w http.ResponseWriter, r *http.Request) {
+	p, err := dateRange(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	holidays, err := h.svc.List(r.Context(), p)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, holidays)
+}
+
+// Get — GET /holidays/{id}
+func (h *HolidaysHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	hd, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, hd)
+}
+
+// Create — POST /holidays
+func (h *HolidaysHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var hd domain.Holiday
+	if err := decodeJSON(w, r, &hd); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	created, err := h.svc.Create(r.Context(), hd)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/holidays/"+itoa(created.ID))
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// Update — PUT /holidays/{id}
+func (h *HolidaysHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var hd domain.Holiday
+	if err := decodeJSON(w, r, &hd); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	hd.ID = id
+
+	updated, err := h.svc.Update(r.Context(), hd)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// Delete — DELETE /holidays/{id}
+func (h *HolidaysHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
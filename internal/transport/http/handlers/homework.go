@@ -0,0 +1,468 @@
+package handlers
+
+import (
+	"net/http"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+	"restapi/internal/service"
+	"restapi/internal/transport/http/middlewares"
+)
+
+type HomeworkHandler struct {
+	svc       *service.HomeworkService
+	maxUpload int64 // предел тела multipart-запроса, байт
+}
+
+func NewHomeworkHandler(svc *service.HomeworkService, maxUpload int64) *HomeworkHandler {
+	return &HomeworkHandler{svc: svc, maxUpload: maxUpload}
+}
+
+// ClassHomework — GET /classes/{id}/homework?subject_id=&from=&to=&limit=&offset= (from/to — по сроку сдачи).
+func (h *HomeworkHandler) ClassHomework(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	f, err := homeworkFilter(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	list, err := h.svc.ClassHomework(r.Context(), id, f)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// StudentHomework — GET /students/{id}/homework: задания класса ученика с его сдачами.
+func (h *HomeworkHandler) StudentHomework(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	f, err := homeworkFilter(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	list, err := h.svc.StudentHomework(r.Context(), id, f)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// Create — POST /classes/{id}/homework
+func (h *HomeworkHandler) Create(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var in domain.HomeworkInput
+	if err := decodeJSON(w, r, &in); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	created, err := h.svc.Create(r.Context(), actor, id, in)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/homework/"+itoa(created.ID))
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// Get — GET /homework/{id}
+func (h *HomeworkHandler) Get(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	hw, err := h.svc.Get(r.Context(), actor, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, hw)
+}
+
+// Update — PUT /homework/{id}
+func (h *HomeworkHandler) Update(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var in domain.HomeworkInput
+	if err := decodeJSON(w, r, &in); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	updated, err := h.svc.Update(r.Context(), actor, id, in)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// Delete — DELETE /homework/{id}
+func (h *HomeworkHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), actor, id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddAttachments — POST /homework/{id}/attachments, multipart/form-data с файлами в поле files.
+func (h *HomeworkHandler) AddAttachments(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	form, err := parseUpload(w, r, h.maxUpload)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	defer form.close()
+	if len(form.files) == 0 {
+		writeError(w, r, domainerr.Newf(domainerr.ErrBadInput, "no files in the files field"))
+		return
+	}
+
+	files, err := h.svc.AddAttachments(r.Context(), actor, id, form.files)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, files)
+}
+
+// Attachment — GET /homework/{id}/attachments/{fileId}: редирект на подписанную ссылку.
+func (h *HomeworkHandler) Attachment(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	fileID, err := pathID(r, "fileId")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	url, err := h.svc.AttachmentURL(r.Context(), actor, id, fileID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	redirectToFile(w, r, url)
+}
+
+// DeleteAttachment — DELETE /homework/{id}/attachments/{fileId}
+func (h *HomeworkHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	fileID, err := pathID(r, "fileId")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.DeleteAttachment(r.Context(), actor, id, fileID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Submissions — GET /homework/{id}/submissions
+func (h *HomeworkHandler) Submissions(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	subs, err := h.svc.Submissions(r.Context(), actor, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, subs)
+}
+
+// MySubmission — GET /homework/{id}/submission: сдача ученика, который делает запрос.
+func (h *HomeworkHandler) MySubmission(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	sub, err := h.svc.MySubmission(r.Context(), actor, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sub)
+}
+
+// Submit — PUT /homework/{id}/submission. Тело — JSON {"text": ...} или multipart/form-data
+// с полем text и файлами в поле files.
+func (h *HomeworkHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var (
+		text    string
+		uploads []service.Upload
+	)
+	if isMultipart(r) {
+		form, err := parseUpload(w, r, h.maxUpload)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		defer form.close()
+		text, uploads = form.value("text"), form.files
+	} else {
+		var body struct {
+			Text string `json:"text"`
+		}
+		if err := decodeJSON(w, r, &body); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		text = body.Text
+	}
+
+	sub, err := h.svc.Submit(r.Context(), actor, id, text, uploads)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sub)
+}
+
+// Submission — GET /submissions/{id}
+func (h *HomeworkHandler) Submission(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	sub, err := h.svc.Submission(r.Context(), actor, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sub)
+}
+
+// SubmissionFile — GET /submissions/{id}/files/{fileId}: редирект на подписанную ссылку.
+func (h *HomeworkHandler) SubmissionFile(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	fileID, err := pathID(r, "fileId")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	url, err := h.svc.SubmissionFileURL(r.Context(), actor, id, fileID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	redirectToFile(w, r, url)
+}
+
+// DeleteSubmissionFile — DELETE /submissions/{id}/files/{fileId}
+func (h *HomeworkHandler) DeleteSubmissionFile(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	fileID, err := pathID(r, "fileId")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.DeleteSubmissionFile(r.Context(), actor, id, fileID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Grade — POST /submissions/{id}/grade
+func (h *HomeworkHandler) Grade(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var g domain.SubmissionGrade
+	if err := decodeJSON(w, r, &g); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	sub, err := h.svc.Grade(r.Context(), actor, id, g)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sub)
+}
+
+func homeworkFilter(r *http.Request) (domain.HomeworkFilter, error) {
+	var f domain.HomeworkFilter
+	subjectID, err := queryInt(r, "subject_id")
+	if err != nil {
+		return f, err
+	}
+	from, err := queryDate(r, "from")
+	if err != nil {
+		return f, err
+	}
+	to, err := queryDate(r, "to")
+	if err != nil {
+		return f, err
+	}
+	if f.Limit, err = queryInt(r, "limit"); err != nil {
+		return f, err
+	}
+	if f.Offset, err = queryInt(r, "offset"); err != nil {
+		return f, err
+	}
+
+	f.SubjectID, f.DueFrom = int64(subjectID), from
+	if !to.IsZero() {
+		f.DueTo = to.AddDate(0, 0, 1) // to включительно
+	}
+	return f, nil
+}
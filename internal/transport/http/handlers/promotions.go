@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+	"restapi/internal/service"
+	"restapi/internal/transport/http/middlewares"
+)
+
+type PromotionsHandler struct {
+	svc *service.PromotionService
+}
+
+func NewPromotionsHandler(svc *service.PromotionService) *PromotionsHandler {
+	return &PromotionsHandler{svc: svc}
+}
+
+// List — GET /promotions
+func (h *PromotionsHandler) List(w http.ResponseWriter, r *http.Request) {
+	runs, err := h.svc.Runs(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, runs)
+}
+
+// Get — GET /promotions/{id}
+func (h *PromotionsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	run, err := h.svc.Run(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, run)
+}
+
+// Promote — POST /promotions. С dry_run=true возвращает план без изменений (200),
+// иначе выполняет перевод (201).
+func (h *PromotionsHandler) Promote(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+
+	var req domain.PromotionRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	plan, err := h.svc.Promote(r.Context(), actor, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if plan.DryRun {
+		writeJSON(w, http.StatusOK, plan)
+		return
+	}
+	w.Header().Set("Location", "/promotions/"+itoa(plan.ID))
+	writeJSON(w, http.StatusCreated, plan)
+}
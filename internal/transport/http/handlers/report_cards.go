@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+	"restapi/internal/service"
+	"restapi/internal/transport/http/middlewares"
+	"restapi/internal/transport/http/pdf"
+)
+
+type ReportCardsHandler struct {
+	svc      *service.ReportCardService
+	branding pdf.Branding
+}
+
+func NewReportCardsHandler(svc *service.ReportCardService, branding pdf.Branding) *ReportCardsHandler {
+	return &ReportCardsHandler{svc: svc, branding: branding}
+}
+
+// StudentCard — GET /students/{id}/report-cards/{term} (JSON) и /students/{id}/report-cards/{term}.pdf.
+// {term} — id учебного периода.
+func (h *ReportCardsHandler) StudentCard(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	termID, asPDF, err := pathFile(r, "term", ".pdf")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	card, err := h.svc.StudentCard(r.Context(), id, termID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !asPDF {
+		writeJSON(w, http.StatusOK, card)
+		return
+	}
+
+	// Документ собирается целиком до ответа: ошибку вёрстки ещё можно отдать как problem+json.
+	// Content-Length выставляет net/http: явный заголовок разошёлся бы с телом под gzip.
+	var buf bytes.Buffer
+	if err := pdf.ReportCard(&buf, h.branding, card); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", pdf.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="report-card-%d-term-%d.pdf"`, id, termID))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(w)
+}
+
+// ClassCards — GET /classes/{id}/report-cards/{term} (JSON) и /classes/{id}/report-cards/{term}.zip —
+// архив с PDF-табелем на каждого ученика.
+func (h *ReportCardsHandler) ClassCards(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	termID, asZIP, err := pathFile(r, "term", ".zip")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	cls, cards, err := h.svc.ClassCards(r.Context(), id, termID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !asZIP {
+		writeJSON(w, http.StatusOK, cards)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="report-cards-%s-term-%d.zip"`, fileSafe(cls.Name), termID))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+
+	// Архив пишется потоком: после заголовков ошибку можно только оборвать ответ.
+	zw := zip.NewWriter(w)
+	for _, card := range cards {
+		f, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     fmt.Sprintf("%s-%d.pdf", fileSafe(card.StudentName), card.StudentID),
+			Method:   zip.Deflate,
+			Modified: card.GeneratedAt.In(time.UTC),
+		})
+		if err != nil {
+			return
+		}
+		if err := pdf.ReportCard(f, h.branding, card); err != nil {
+			return
+		}
+	}
+	_ = zw.Close()
+}
+
+// SaveComment — PUT /students/{id}/report-cards/{term}/comment. Без subject_id — общий комментарий.
+func (h *ReportCardsHandler) SaveComment(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	termID, err := pathID(r, "term")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var c domain.ReportCardComment
+	if err := decodeJSON(w, r, &c); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	c.StudentID, c.TermID = id, termID
+
+	saved, err := h.svc.SaveComment(r.Context(), actor, c)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, saved)
+}
+
+// DeleteComment — DELETE /students/{id}/report-cards/{term}/comment?subject_id=
+func (h *ReportCardsHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	termID, err := pathID(r, "term")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var subjectID *int64
+	if s := r.URL.Query().Get("subject_id"); s != "" {
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || v <= 0 {
+			writeError(w, r, domainerr.Newf(domainerr.ErrBadInput, "invalid subject_id"))
+			return
+		}
+		subjectID = &v
+	}
+
+	if err := h.svc.DeleteComment(r.Context(), actor, id, termID, subjectID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pathFile разбирает параметр пути вида "12" или "12.ext": id и признак запрошенного файла.
+func pathFile(r *http.Request, name, ext string) (int64, bool, error) {
+	s, isFile := strings.CutSuffix(r.PathValue(name), ext)
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false, domainerr.Newf(domainerr.ErrBadInput, "invalid %s", name)
+	}
+	return id, isFile, nil
+}
+
+// fileSafe готовит строку для имени файла: пробелы — в «_», разделители путей и кавычки убираются.
+func fileSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ' ':
+			return '_'
+		case strings.ContainsRune(`/\:*?"<>|`, r), r < 0x20:
+			return -1
+		}
+		return r
+	}, s)
+}
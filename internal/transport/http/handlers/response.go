@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	domainerr "restapi/internal/domain/errors"
+	log "restapi/internal/logger"
+	"restapi/internal/transport/http/problem"
+)
+
+// maxBodyBytes — ограничение на размер JSON-тела запроса.
+const maxBodyBytes = 1 << 20
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if v == nil {
+		return
+	}
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Error("write json", "err", err)
+	}
+}
+
+// writeError отвечает problem+json (RFC 9457) по доменной ошибке.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	problem.Write(w, r, err)
+}
+
+// decodeJSON читает тело в v; неизвестные поля и мусор после объекта — ErrBadInput.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return err
+		}
+		return domainerr.Newf(domainerr.ErrBadInput, "invalid json: %v", err)
+	}
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		return domainerr.Newf(domainerr.ErrBadInput, "body must contain a single json object")
+	}
+	return nil
+}
+
+// pathID достаёт числовой параметр пути ({id} и т.п.).
+func pathID(r *http.Request, name string) (int64, error) {
+	id, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, domainerr.Newf(domainerr.ErrBadInput, "invalid %s", name)
+	}
+	return id, nil
+}
+
+// queryInt читает необязательный целочисленный query-параметр.
+func queryInt(r *http.Request, name string) (int, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, domainerr.Newf(domainerr.ErrBadInput, "invalid %s", name)
+	}
+	return n, nil
+}
+
+// queryDate читает необязательную дату в формате YYYY-MM-DD.
+func queryDate(r *http.Request, name string) (time.Time, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, domainerr.Newf(domainerr.ErrBadInput, "invalid %s, want YYYY-MM-DD", name)
+	}
+	return t, nil
+}
+
+func itoa(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+
+	"restapi/internal/domain"
+	"restapi/internal/service"
+)
+
+type RoomsHandler struct {
+	svc *service.RoomService
+}
+
+func NewRoomsHandler(svc *service.RoomService) *RoomsHandler {
+	return &RoomsHandler{svc: svc}
+}
+
+// List — GET /rooms
+func (h *RoomsHandler) List(w http.ResponseWriter, r *http.Request) {
+	rooms, err := h.svc.List(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rooms)
+}
+
+// Get — GET /rooms/{id}
+func (h *RoomsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	rm, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rm)
+}
+
+// Create — POST /rooms
+func (h *RoomsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var rm domain.Room
+	if err := decodeJSON(w, r, &rm); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	created, err := h.svc.Create(r.Context(), rm)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/rooms/"+itoa(created.ID))
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// Update — PUT /rooms/{id}
+func (h *RoomsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var rm domain.Room
+	if err := decodeJSON(w, r, &rm); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	rm.ID = id
+
+	updated, err := h.svc.Update(r.Context(), rm)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// Delete — DELETE /rooms/{id}
+func (h *RoomsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
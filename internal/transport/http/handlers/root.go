@@ -1,11 +1,16 @@
 package handlers
 
 import (
-	"fmt"
 	"net/http"
+
+	"restapi/internal/transport/http/problem"
 )
 
 func RootHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("Hello, Root Path!"))
-	fmt.Println("Hello, Root Path!")
+}
+
+// NotFoundHandler — ответ для путей, не совпавших ни с одним маршрутом.
+func NotFoundHandler(w http.ResponseWriter, r *http.Request) {
+	problem.WriteStatus(w, r, http.StatusNotFound, "no route for "+r.Method+" "+r.URL.Path)
 }
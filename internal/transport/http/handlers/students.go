@@ -1,11 +1,248 @@
 package handlers
 
 import (
-	"fmt"
 	"net/http"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+	"restapi/internal/service"
+	"restapi/internal/transport/http/middlewares"
 )
 
-func StudentsHandler(w http.ResponseWriter, r *http.Request) {
-	w.Write([]byte("Hello, students Path!"))
-	fmt.Println("Hello, students Path!")
+type StudentsHandler struct {
+	svc       *service.StudentService
+	maxUpload int64 // предел тела multipart-запроса, байт
+}
+
+func NewStudentsHandler(svc *service.StudentService, maxUpload int64) *StudentsHandler {
+	return &StudentsHandler{svc: svc, maxUpload: maxUpload}
+}
+
+// List — GET /students?search=&grade=&class_id=&status=&limit=&offset=
+func (h *StudentsHandler) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	grade, err := queryInt(r, "grade")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	classID, err := queryInt(r, "class_id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	offset, err := queryInt(r, "offset")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	students, err := h.svc.List(r.Context(), domain.StudentFilter{
+		Search:  q.Get("search"),
+		Grade:   grade,
+		ClassID: int64(classID),
+		Status:  domain.StudentStatus(q.Get("status")),
+		Limit:   limit,
+		Offset:  offset,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, students)
+}
+
+// Get — GET /students/{id}
+func (h *StudentsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s)
+}
+
+// Create — POST /students
+func (h *StudentsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var s domain.Student
+	if err := decodeJSON(w, r, &s); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	created, err := h.svc.Create(r.Context(), s)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/students/"+itoa(created.ID))
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// Update — PUT /students/{id}
+func (h *StudentsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var s domain.Student
+	if err := decodeJSON(w, r, &s); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	s.ID = id
+
+	updated, err := h.svc.Update(r.Context(), s)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// Patch — PATCH /students/{id}
+func (h *StudentsHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var p domain.StudentPatch
+	if err := decodeJSON(w, r, &p); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	updated, err := h.svc.Patch(r.Context(), id, p)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// Transition — POST /students/{id}/status {"status": "enrolled"}
+func (h *StudentsHandler) Transition(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var body struct {
+		Status domain.StudentStatus `json:"status"`
+	}
+	if err := decodeJSON(w, r, &body); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	updated, err := h.svc.Transition(r.Context(), id, body.Status)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// Delete — DELETE /students/{id}
+func (h *StudentsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetPhoto — PUT /students/{id}/photo (multipart, поле photo)
+func (h *StudentsHandler) SetPhoto(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	form, err := parseUploadField(w, r, h.maxUpload, "photo")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	defer form.close()
+	if len(form.files) != 1 {
+		writeError(w, r, domainerr.Newf(domainerr.ErrBadInput, "exactly one file is expected in field photo"))
+		return
+	}
+
+	photo, err := h.svc.SetPhoto(r.Context(), actor, id, form.files[0])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, photo)
+}
+
+// Photo — GET /students/{id}/photo: редирект на подписанную ссылку.
+func (h *StudentsHandler) Photo(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	url, err := h.svc.PhotoURL(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	redirectToFile(w, r, url)
+}
+
+// DeletePhoto — DELETE /students/{id}/photo
+func (h *StudentsHandler) DeletePhoto(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.DeletePhoto(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
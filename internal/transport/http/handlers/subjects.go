@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+
+	"restapi/internal/domain"
+	"restapi/internal/service"
+)
+
+type SubjectsHandler struct {
+	svc *service.SubjectService
+}
+
+func NewSubjectsHandler(svc *service.SubjectService) *SubjectsHandler {
+	return &SubjectsHandler{svc: svc}
+}
+
+// List — GET /subjects
+func (h *SubjectsHandler) List(w http.ResponseWriter, r *http.Request) {
+	subjects, err := h.svc.List(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, subjects)
+}
+
+// Get — GET /subjects/{id}
+func (h *SubjectsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	s, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s)
+}
+
+// Create — POST /subjects
+func (h *SubjectsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var s domain.Subject
+	if err := decodeJSON(w, r, &s); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	created, err := h.svc.Create(r.Context(), s)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/subjects/"+itoa(created.ID))
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// Update — PUT /subjects/{id}
+func (h *SubjectsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var s domain.Subject
+	if err := decodeJSON(w, r, &s); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	s.ID = id
+
+	updated, err := h.svc.Update(r.Context(), s)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// Delete — DELETE /subjects/{id}
+func (h *SubjectsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,19 +1,140 @@
 package handlers
 
 import (
-	"fmt"
 	"net/http"
+
+	"restapi/internal/domain"
+	"restapi/internal/service"
 )
 
-func TeachersHandler(w http.ResponseWriter, r *http.Request) {
-	w.Write([]byte("Hello, teachers Path!"))
-	fmt.Println("Hello, teachers Path!")
+type TeachersHandler struct {
+	svc *service.TeacherService
+}
+
+func NewTeachersHandler(svc *service.TeacherService) *TeachersHandler {
+	return &TeachersHandler{svc: svc}
+}
 
-	switch r.Method {
-	case http.MethodGet:
+// List — GET /teachers/?search=&limit=&offset=
+func (h *TeachersHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	offset, err := queryInt(r, "offset")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
 
-	case http.MethodPost:
-		w.Write([]byte("Hello, teachers Path!"))
-		fmt.Println("Hello, teachers Path!")
+	teachers, err := h.svc.List(r.Context(), domain.TeacherFilter{
+		Search: r.URL.Query().Get("search"),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
 	}
+
+	writeJSON(w, http.StatusOK, teachers)
+}
+
+// Get — GET /teachers/{id}
+func (h *TeachersHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	t, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, t)
+}
+
+// Create — POST /teachers/
+func (h *TeachersHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var t domain.Teacher
+	if err := decodeJSON(w, r, &t); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	created, err := h.svc.Create(r.Context(), t)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/teachers/"+itoa(created.ID))
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// Update — PUT /teachers/{id}
+func (h *TeachersHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var t domain.Teacher
+	if err := decodeJSON(w, r, &t); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	t.ID = id
+
+	updated, err := h.svc.Update(r.Context(), t)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// Patch — PATCH /teachers/{id}
+func (h *TeachersHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var p domain.TeacherPatch
+	if err := decodeJSON(w, r, &p); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	updated, err := h.svc.Patch(r.Context(), id, p)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// Delete — DELETE /teachers/{id}
+func (h *TeachersHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
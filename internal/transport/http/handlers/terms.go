@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+	"restapi/internal/service"
+	"restapi/internal/transport/http/middlewares"
+)
+
+type TermsHandler struct {
+	svc *service.TermService
+}
+
+func NewTermsHandler(svc *service.TermService) *TermsHandler {
+	return &TermsHandler{svc: svc}
+}
+
+// Years — GET /academic-years
+func (h *TermsHandler) Years(w http.ResponseWriter, r *http.Request) {
+	years, err := h.svc.Years(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, years)
+}
+
+// Year — GET /academic-years/{year}
+func (h *TermsHandler) Year(w http.ResponseWriter, r *http.Request) {
+	year, err := pathID(r, "year")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	y, err := h.svc.Year(r.Context(), int(year))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, y)
+}
+
+// CreateYear — POST /academic-years
+func (h *TermsHandler) CreateYear(w http.ResponseWriter, r *http.Request) {
+	var y domain.AcademicYear
+	if err := decodeJSON(w, r, &y); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	created, err := h.svc.CreateYear(r.Context(), y)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/academic-years/"+strconv.Itoa(created.Year))
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// UpdateYear — PUT /academic-years/{year}
+func (h *TermsHandler) UpdateYear(w http.ResponseWriter, r *http.Request) {
+	year, err := pathID(r, "year")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var y domain.AcademicYear
+	if err := decodeJSON(w, r, &y); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	y.Year = int(year)
+
+	updated, err := h.svc.UpdateYear(r.Context(), y)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// DeleteYear — DELETE /academic-years/{year}
+func (h *TermsHandler) DeleteYear(w http.ResponseWriter, r *http.Request) {
+	year, err := pathID(r, "year")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.DeleteYear(r.Context(), int(year)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Terms — GET /academic-years/{year}/terms
+func (h *TermsHandler) Terms(w http.ResponseWriter, r *http.Request) {
+	year, err := pathID(r, "year")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	terms, err := h.svc.Terms(r.Context(), int(year))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, terms)
+}
+
+// Current — GET /terms/current?on=YYYY-MM-DD. 404, если дата вне учебных периодов.
+func (h *TermsHandler) Current(w http.ResponseWriter, r *http.Request) {
+	on, err := queryDate(r, "on")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	t, err := h.svc.Current(r.Context(), on)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, t)
+}
+
+// Get — GET /terms/{id}
+func (h *TermsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	t, err := h.svc.Term(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, t)
+}
+
+// Create — POST /terms
+func (h *TermsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var t domain.Term
+	if err := decodeJSON(w, r, &t); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	created, err := h.svc.CreateTerm(r.Context(), t)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/terms/"+itoa(created.ID))
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// Update — PUT /terms/{id}
+func (h *TermsHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var t domain.Term
+	if err := decodeJSON(w, r, &t); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	t.ID = id
+
+	updated, err := h.svc.UpdateTerm(r.Context(), t)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// Delete — DELETE /terms/{id}
+func (h *TermsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.DeleteTerm(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Close — POST /terms/{id}/close
+func (h *TermsHandler) Close(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	t, err := h.svc.Close(r.Context(), actor, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, t)
+}
+
+// Reopen — POST /terms/{id}/reopen
+func (h *TermsHandler) Reopen(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	t, err := h.svc.Reopen(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, t)
+}
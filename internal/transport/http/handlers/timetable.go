@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"restapi/internal/domain"
+	"restapi/internal/service"
+)
+
+type TimetableHandler struct {
+	svc *service.TimetableService
+}
+
+func NewTimetableHandler(svc *service.TimetableService) *TimetableHandler {
+	return &TimetableHandler{svc: svc}
+}
+
+// ClassTimetable — GET /classes/{id}/timetable?on=|from=&to=
+func (h *TimetableHandler) ClassTimetable(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.svc.ClassTimetable)
+}
+
+// StudentTimetable — GET /students/{id}/timetable?on=|from=&to=
+func (h *TimetableHandler) StudentTimetable(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.svc.StudentTimetable)
+}
+
+// TeacherTimetable — GET /teachers/{id}/timetable?on=|from=&to=
+func (h *TimetableHandler) TeacherTimetable(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.svc.TeacherTimetable)
+}
+
+// RoomTimetable — GET /rooms/{id}/timetable?on=|from=&to=
+func (h *TimetableHandler) RoomTimetable(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, h.svc.RoomTimetable)
+}
+
+func (h *TimetableHandler) list(w http.ResponseWriter, r *http.Request,
+	fetch func(ctx context.Context, id int64, f domain.TimetableFilter) ([]domain.TimetableSlot, error)) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	f, err := timetableFilter(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slots, err := fetch(r.Context(), id, f)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, slots)
+}
+
+// Get — GET /timetable/slots/{id}
+func (h *TimetableHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slot, err := h.svc.Get(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, slot)
+}
+
+// Create — POST /timetable/slots
+func (h *TimetableHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var slot domain.TimetableSlot
+	if err := decodeJSON(w, r, &slot); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	created, err := h.svc.Create(r.Context(), slot)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/timetable/slots/"+itoa(created.ID))
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// Update — PUT /timetable/slots/{id}
+func (h *TimetableHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var slot domain.TimetableSlot
+	if err := decodeJSON(w, r, &slot); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	slot.ID = id
+
+	updated, err := h.svc.Update(r.Context(), slot)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// Delete — DELETE /timetable/slots/{id}
+func (h *TimetableHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.svc.Delete(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func timetableFilter(r *http.Request) (domain.TimetableFilter, error) {
+	var (
+		f   domain.TimetableFilter
+		err error
+	)
+	if f.On, err = queryDate(r, "on"); err != nil {
+		return f, err
+	}
+	p, err := dateRange(r)
+	if err != nil {
+		return f, err
+	}
+	f.From, f.To = p.From, p.To
+	return f, nil
+}
+
+// Availability — GET /teachers/{id}/availability
+func (h *TimetableHandler) Availability(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	a, err := h.svc.Availability(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, a)
+}
+
+// SetAvailability — PUT /teachers/{id}/availability
+func (h *TimetableHandler) SetAvailability(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var a domain.TeacherAvailability
+	if err := decodeJSON(w, r, &a); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	saved, err := h.svc.SetAvailability(r.Context(), id, a)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, saved)
+}
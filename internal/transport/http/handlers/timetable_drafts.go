@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+	"restapi/internal/service"
+	"restapi/internal/transport/http/middlewares"
+)
+
+type TimetableDraftsHandler struct {
+	gen *service.TimetableGenerator
+}
+
+func NewTimetableDraftsHandler(gen *service.TimetableGenerator) *TimetableDraftsHandler {
+	return &TimetableDraftsHandler{gen: gen}
+}
+
+// Generate — POST /timetables/generate
+// Отвечает 202: генерация идёт в фоне, статус — по Location.
+func (h *TimetableDraftsHandler) Generate(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+
+	var req domain.GenerateTimetable
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	d, err := h.gen.Generate(r.Context(), actor, req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/timetables/drafts/"+itoa(d.ID))
+	writeJSON(w, http.StatusAccepted, d)
+}
+
+// List — GET /timetables/drafts?limit=&offset=
+func (h *TimetableDraftsHandler) List(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	offset, err := queryInt(r, "offset")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	list, err := h.gen.Drafts(r.Context(), limit, offset)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// Get — GET /timetables/drafts/{id} (статус и прогресс генерации)
+func (h *TimetableDraftsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	d, err := h.gen.Draft(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, d)
+}
+
+// Slots — GET /timetables/drafts/{id}/slots?class_id=
+func (h *TimetableDraftsHandler) Slots(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	classID, err := queryInt(r, "class_id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	slots, err := h.gen.DraftSlots(r.Context(), id, int64(classID))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, slots)
+}
+
+// Publish — POST /timetables/drafts/{id}/publish
+func (h *TimetableDraftsHandler) Publish(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	d, err := h.gen.Publish(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, d)
+}
+
+// Delete — DELETE /timetables/drafts/{id}
+func (h *TimetableDraftsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.gen.Delete(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
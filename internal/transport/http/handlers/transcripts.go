@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	domainerr "restapi/internal/domain/errors"
+	"restapi/internal/service"
+	"restapi/internal/transport/http/middlewares"
+	"restapi/internal/transport/http/pdf"
+)
+
+type TranscriptsHandler struct {
+	svc      *service.TranscriptService
+	branding pdf.Branding
+}
+
+func NewTranscriptsHandler(svc *service.TranscriptService, branding pdf.Branding) *TranscriptsHandler {
+	return &TranscriptsHandler{svc: svc, branding: branding}
+}
+
+// Preview — GET /students/{id}/transcript?scale=letter. Выписка без выдачи и без хеша.
+func (h *TranscriptsHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	t, err := h.svc.Preview(r.Context(), id, r.URL.Query().Get("scale"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, t)
+}
+
+// Issued — GET /students/{id}/transcripts
+func (h *TranscriptsHandler) Issued(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	list, err := h.svc.Issued(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// Issue — POST /students/{id}/transcripts {"scale": "letter"}
+func (h *TranscriptsHandler) Issue(w http.ResponseWriter, r *http.Request) {
+	actor, ok := middlewares.PrincipalFromContext(r.Context())
+	if !ok {
+		writeError(w, r, domainerr.ErrUnauthorized)
+		return
+	}
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var in struct {
+		Scale string `json:"scale"`
+	}
+	if err := decodeJSON(w, r, &in); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	t, err := h.svc.Issue(r.Context(), actor, id, in.Scale)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/students/"+itoa(id)+"/transcripts/"+t.Hash)
+	writeJSON(w, http.StatusCreated, t)
+}
+
+// Get — GET /students/{id}/transcripts/{hash} (JSON) и /students/{id}/transcripts/{hash}.pdf.
+func (h *TranscriptsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "id")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	hash, asPDF := strings.CutSuffix(r.PathValue("hash"), ".pdf")
+
+	t, err := h.svc.Get(r.Context(), id, hash)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !asPDF {
+		writeJSON(w, http.StatusOK, t)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Transcript(&buf, h.branding, t); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", pdf.ContentType)
+	w.Header().Set("Content-Disposition", `inline; filename="transcript-`+itoa(id)+`-`+t.Hash[:12]+`.pdf"`)
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = buf.WriteTo(w)
+}
+
+// Verify — GET /transcripts/verify/{hash}. Публичный: по коду с бумажной выписки
+// любой может убедиться, что она выдана школой и не изменена.
+func (h *TranscriptsHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	v, err := h.svc.Verify(r.Context(), r.PathValue("hash"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, v)
+}
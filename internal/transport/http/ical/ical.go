@@ -0,0 +1,225 @@
+// Package ical кодирует события расписания в iCalendar (RFC 5545).
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"restapi/internal/domain"
+)
+
+const ContentType = "text/calendar; charset=utf-8"
+
+const (
+	prodID      = "-//school-api//timetable//EN"
+	uidDomain   = "school-api"
+	maxLineLen  = 75 // октетов без CRLF (RFC 5545 §3.1)
+	localLayout = "20060102T150405"
+	utcLayout   = "20060102T150405Z"
+)
+
+// Calendar — календарь с событиями в часовом поясе Location.
+type Calendar struct {
+	Name     string
+	Location *time.Location
+	Events   []domain.CalendarEvent
+}
+
+// Encode пишет календарь в w. Время событий — локальное с TZID, описание пояса — VTIMEZONE.
+func Encode(w io.Writer, c Calendar) error {
+	e := &encoder{w: bufio.NewWriter(w)}
+	tzid := c.Location.String()
+
+	e.line("BEGIN", "VCALENDAR")
+	e.line("VERSION", "2.0")
+	e.line("PRODID", prodID)
+	e.line("CALSCALE", "GREGORIAN")
+	e.line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		e.line("X-WR-CALNAME", escape(c.Name))
+	}
+	e.line("X-WR-TIMEZONE", tzid)
+
+	e.timezone(c.Location, firstYear(c.Events))
+
+	for _, ev := range c.Events {
+		e.line("BEGIN", "VEVENT")
+		e.line("UID", ev.UID+"@"+uidDomain)
+		e.line("DTSTAMP", ev.Updated.UTC().Format(utcLayout))
+		e.line("DTSTART;TZID="+tzid, ev.Start.In(c.Location).Format(localLayout))
+		e.line("DTEND;TZID="+tzid, ev.End.In(c.Location).Format(localLayout))
+
+		rrule := "FREQ=WEEKLY;BYDAY=" + byDay(ev.Start.In(c.Location).Weekday())
+		if ev.Until != nil {
+			// При DTSTART с TZID значение UNTIL обязано быть в UTC (RFC 5545 §3.3.10).
+			rrule += ";UNTIL=" + ev.Until.UTC().Format(utcLayout)
+		}
+		e.line("RRULE", rrule)
+
+		for _, ex := range ev.ExDates {
+			e.line("EXDATE;TZID="+tzid, ex.In(c.Location).Format(localLayout))
+		}
+
+		e.line("SUMMARY", escape(ev.Summary))
+		if ev.Location != "" {
+			e.line("LOCATION", escape(ev.Location))
+		}
+		if ev.Description != "" {
+			e.line("DESCRIPTION", escape(ev.Description))
+		}
+		e.line("END", "VEVENT")
+	}
+
+	e.line("END", "VCALENDAR")
+
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line пишет свойство name:value с переносом длинных строк (folding) и CRLF.
+func (e *encoder) line(name, value string) {
+	if e.err != nil {
+		return
+	}
+	s := name + ":" + value
+
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > maxLineLen {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	b.WriteString("\r\n")
+
+	_, e.err = e.w.WriteString(b.String())
+}
+
+// timezone пишет VTIMEZONE для loc. Правила перехода на летнее время восстанавливаются
+// по фактическим переходам в году year и повторяются ежегодно.
+func (e *encoder) timezone(loc *time.Location, year int) {
+	e.line("BEGIN", "VTIMEZONE")
+	e.line("TZID", loc.String())
+
+	transitions := yearTransitions(loc, year)
+	if len(transitions) == 0 {
+		name, offset := time.Date(year, time.January, 1, 0, 0, 0, 0, loc).Zone()
+		e.line("BEGIN", "STANDARD")
+		e.line("DTSTART", "19700101T000000")
+		e.line("TZOFFSETFROM", formatOffset(offset))
+		e.line("TZOFFSETTO", formatOffset(offset))
+		e.line("TZNAME", name)
+		e.line("END", "STANDARD")
+	}
+
+	for _, t := range transitions {
+		component := "STANDARD"
+		if t.isDST {
+			component = "DAYLIGHT"
+		}
+		// DTSTART — стенное время до перехода (в старом смещении).
+		local := t.at.Add(time.Duration(t.from) * time.Second).UTC()
+
+		e.line("BEGIN", component)
+		e.line("DTSTART", local.Format(localLayout))
+		e.line("RRULE", fmt.Sprintf("FREQ=YEARLY;BYMONTH=%d;BYDAY=%s", int(local.Month()), nthWeekday(local)))
+		e.line("TZOFFSETFROM", formatOffset(t.from))
+		e.line("TZOFFSETTO", formatOffset(t.to))
+		e.line("TZNAME", t.name)
+		e.line("END", component)
+	}
+
+	e.line("END", "VTIMEZONE")
+}
+
+type transition struct {
+	at       time.Time // момент перехода (UTC)
+	from, to int       // смещения, секунды
+	name     string
+	isDST    bool
+}
+
+// yearTransitions находит переходы смещения loc в году year: сначала по дням, затем до секунды.
+func yearTransitions(loc *time.Location, year int) []transition {
+	var out []transition
+
+	prev := time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	_, prevOff := prev.Zone()
+	for day := 1; day <= 366; day++ {
+		cur := prev.Add(24 * time.Hour)
+		_, curOff := cur.Zone()
+		if curOff != prevOff {
+			lo, hi := prev, cur
+			for hi.Sub(lo) > time.Second {
+				mid := lo.Add(hi.Sub(lo) / 2)
+				if _, off := mid.Zone(); off == prevOff {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			name, _ := hi.Zone()
+			out = append(out, transition{at: hi.UTC(), from: prevOff, to: curOff, name: name, isDST: hi.IsDST()})
+		}
+		prev, prevOff = cur, curOff
+		if prev.Year() > year {
+			break
+		}
+	}
+	return out
+}
+
+func firstYear(events []domain.CalendarEvent) int {
+	year := time.Now().Year()
+	for _, ev := range events {
+		if y := ev.Start.Year(); y < year {
+			year = y
+		}
+	}
+	return year
+}
+
+var weekdays = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+func byDay(d time.Weekday) string {
+	return weekdays[d]
+}
+
+// nthWeekday — «n-й такой день недели в месяце», для последней недели — -1 (например, -1SU).
+func nthWeekday(t time.Time) string {
+	daysInMonth := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if t.Day()+7 > daysInMonth {
+		return "-1" + byDay(t.Weekday())
+	}
+	return fmt.Sprintf("%d%s", (t.Day()-1)/7+1, byDay(t.Weekday()))
+}
+
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escape экранирует TEXT-значение (RFC 5545 §3.3.11).
+func escape(s string) string {
+	return escaper.Replace(s)
+}
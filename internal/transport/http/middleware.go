@@ -0,0 +1,126 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"restapi/internal/config"
+	"restapi/internal/infrastructure/redis"
+	"restapi/internal/transport/http/clientip"
+	"restapi/internal/transport/http/middlewares"
+
+	goredis "github.com/redis/go-redis/v9"
+	"golang.org/x/time/rate"
+)
+
+// Имена звеньев глобальной цепочки для MW_ORDER.
+const (
+	mwRequestID       = "request_id"
+	mwResponseTime    = "response_time"
+	mwSecurityHeaders = "security_headers"
+	mwCors            = "cors"
+	mwRateLimit       = "rate_limit"
+	mwCompression     = "compression"
+)
+
+// buildGlobalStack собирает глобальную цепочку по config.Middlewares.
+func buildGlobalStack(cfg *config.Config, limits middlewares.LimitStore) (middlewares.Stack, error) {
+	mc := cfg.Middlewares
+
+	proxies, err := clientip.NewResolver(cfg.App.HTTP.TrustedProxies)
+	if err != nil {
+		return middlewares.Stack{}, err
+	}
+
+	// Адрес клиента нужен остальным звеньям (лимиты, логи), поэтому ClientIP всегда первое и не входит в MW_ORDER.
+	var (
+		mws  = []middlewares.Middleware{middlewares.ClientIP(proxies)}
+		seen = make(map[string]bool)
+	)
+
+	for _, raw := range mc.Order {
+		name := strings.TrimSpace(strings.ToLower(raw))
+		if name == "" {
+			continue
+		}
+		if seen[name] {
+			return middlewares.Stack{}, fmt.Errorf("middleware %q listed twice in MW_ORDER", name)
+		}
+		seen[name] = true
+
+		switch name {
+		case mwRequestID:
+			if mc.RequestIDEnabled {
+				mws = append(mws, middlewares.RequestID)
+			}
+		case mwResponseTime:
+			if mc.ResponseTimeEnabled {
+				mws = append(mws, middlewares.ResponseTimeMiddleware)
+			}
+		case mwSecurityHeaders:
+			if mc.SecurityHeadersEnabled {
+				mws = append(mws, middlewares.SecurityHeaders)
+			}
+		case mwCors:
+			if !mc.CorsEnabled {
+				continue
+			}
+			cors, err := middlewares.NewCors(middlewares.CorsOptions{
+				Origins:          cfg.Cors.Origins,
+				Methods:          cfg.Cors.Methods,
+				Headers:          cfg.Cors.Headers,
+				ExposedHeaders:   cfg.Cors.ExposedHeaders,
+				AllowCredentials: cfg.Cors.AllowCredentials,
+				MaxAge:           cfg.Cors.MaxAge,
+			})
+			if err != nil {
+				return middlewares.Stack{}, err
+			}
+			mws = append(mws, cors)
+		case mwRateLimit:
+			if !mc.RateLimitEnabled {
+				continue
+			}
+			rl, err := middlewares.NewRateLimiter(limits, middlewares.Policy{
+				Name:  "global",
+				Limit: middlewares.Limit{Rate: rate.Limit(cfg.RateLimit.RPS), Burst: cfg.RateLimit.Burst},
+				Key:   middlewares.KeyByClientIP,
+			}, cfg.RateLimit.FailOpen)
+			if err != nil {
+				return middlewares.Stack{}, fmt.Errorf("rate limiter: %w", err)
+			}
+			mws = append(mws, rl.Middleware)
+		case mwCompression:
+			if mc.CompressionEnabled {
+				mws = append(mws, middlewares.Compression)
+			}
+		default:
+			return middlewares.Stack{}, fmt.Errorf("unknown middleware %q in MW_ORDER", name)
+		}
+	}
+
+	return middlewares.NewStack(mws...), nil
+}
+
+// newLimitStore выбирает хранилище лимитов по RATE_LIMIT_BACKEND. closers останавливают
+// cleanup-горутину MemoryStore — их вызывает Server.Shutdown.
+func newLimitStore(cfg *config.Config, rdb *goredis.Client) (_ middlewares.LimitStore, closers []func(), err error) {
+	if cfg.RateLimit.Backend == "redis" {
+		if rdb == nil {
+			return nil, nil, errors.New("redis client is required for RATE_LIMIT_BACKEND=redis")
+		}
+		return redis.NewRateLimitStore(rdb, cfg.Redis.KeyPrefix), nil, nil
+	}
+	store, err := middlewares.NewMemoryStore(cfg.RateLimit.TTL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return store, []func(){store.Close}, nil
+}
+
+func runClosers(closers []func()) {
+	for _, c := range closers {
+		c()
+	}
+}
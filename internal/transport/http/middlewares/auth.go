@@ -0,0 +1,104 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+	"restapi/internal/transport/http/problem"
+)
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (domain.Principal, domain.Session, error)
+	AuthenticateFeedToken(ctx context.Context, token string) (domain.Principal, error)
+}
+
+type principalKey struct{}
+
+// Auth пропускает запрос дальше только с действующей сессией; иначе 401.
+type Auth struct {
+	authn      Authenticator
+	cookieName string
+}
+
+func NewAuth(authn Authenticator, cookieName string) (*Auth, error) {
+	if authn == nil {
+		return nil, errors.New("authenticator is nil")
+	}
+	if cookieName == "" {
+		return nil, errors.New("cookie name is empty")
+	}
+	return &Auth{authn: authn, cookieName: cookieName}, nil
+}
+
+func (a *Auth) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, _, err := a.authn.Authenticate(r.Context(), SessionToken(r, a.cookieName))
+		if err != nil {
+			switch {
+			case errors.Is(err, domainerr.ErrSessionExpired):
+				a.clearCookie(w)
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token", error_description="session expired"`)
+			case errors.Is(err, domainerr.ErrUnauthorized):
+				w.Header().Set("WWW-Authenticate", `Bearer`)
+			}
+			problem.Write(w, r, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+	})
+}
+
+// FeedMiddleware — для подписок (iCalendar): календарные клиенты не шлют cookie и заголовки,
+// поэтому принимается секрет из персональной ссылки (?token=). Без него — обычная сессия.
+func (a *Auth) FeedMiddleware(next http.Handler) http.Handler {
+	session := a.Middleware(next)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Query().Get("token")
+		if token == "" {
+			session.ServeHTTP(w, r)
+			return
+		}
+
+		p, err := a.authn.AuthenticateFeedToken(r.Context(), token)
+		if err != nil {
+			problem.Write(w, r, err)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+	})
+}
+
+func (a *Auth) clearCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{Name: a.cookieName, Value: "", Path: "/", MaxAge: -1, HttpOnly: true})
+}
+
+// SessionToken берёт токен из заголовка Authorization: Bearer, иначе из cookie.
+func SessionToken(r *http.Request, cookieName string) string {
+	if h := r.Header.Get("Authorization"); h != "" {
+		if token, ok := strings.CutPrefix(h, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+		return ""
+	}
+	if c, err := r.Cookie(cookieName); err == nil {
+		return c.Value
+	}
+	return ""
+}
+
+func WithPrincipal(ctx context.Context, p domain.Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext возвращает субъект, положенный Auth-middleware.
+func PrincipalFromContext(ctx context.Context) (domain.Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(domain.Principal)
+	return p, ok
+}
@@ -0,0 +1,152 @@
+package middlewares
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"restapi/internal/domain"
+	domainerr "restapi/internal/domain/errors"
+	"restapi/internal/transport/http/problem"
+)
+
+// Rule — правило доступа к маршруту. Возвращает true, если субъекту можно выполнить запрос.
+// Ошибка означает сбой проверки (например, БД недоступна) и даёт 500, а не 403.
+type Rule func(r *http.Request, p domain.Principal) (bool, error)
+
+// Authorize пропускает запрос, если сработало хотя бы одно правило; иначе 403.
+// superadmin проходит всегда. Должен стоять после Auth.
+func Authorize(rules ...Rule) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, ok := PrincipalFromContext(r.Context())
+			if !ok {
+				problem.Write(w, r, domainerr.ErrUnauthorized)
+				return
+			}
+
+			if p.Role == domain.RoleSuperadmin {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			for _, rule := range rules {
+				allowed, err := rule(r, p)
+				if err != nil {
+					problem.Write(w, r, fmt.Errorf("authorize: %w", err))
+					return
+				}
+				if allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			problem.Write(w, r, domainerr.ErrForbidden)
+		})
+	}
+}
+
+// AllowRoles — доступ по роли.
+func AllowRoles(roles ...domain.Role) Rule {
+	return func(_ *http.Request, p domain.Principal) (bool, error) {
+		for _, role := range roles {
+			if p.Role == role {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// AllowAuthenticated — доступ любому вошедшему.
+func AllowAuthenticated() Rule {
+	return func(_ *http.Request, _ domain.Principal) (bool, error) {
+		return true, nil
+	}
+}
+
+// AllowOwnStudent — ученик работает только со своей записью: параметр пути param == его student_id.
+func AllowOwnStudent(param string) Rule {
+	return func(r *http.Request, p domain.Principal) (bool, error) {
+		return p.Role == domain.RoleStudent && p.StudentID != 0 && pathIDEquals(r, param, p.StudentID), nil
+	}
+}
+
+// AllowOwnTeacher — учитель работает только со своим профилем: параметр пути param == его teacher_id.
+func AllowOwnTeacher(param string) Rule {
+	return func(r *http.Request, p domain.Principal) (bool, error) {
+		return p.Role == domain.RoleTeacher && p.TeacherID != 0 && pathIDEquals(r, param, p.TeacherID), nil
+	}
+}
+
+// AllowOwnGuardian — представитель работает только со своей карточкой: параметр пути param == его guardian_id.
+func AllowOwnGuardian(param string) Rule {
+	return func(r *http.Request, p domain.Principal) (bool, error) {
+		return p.Role == domain.RoleGuardian && p.GuardianID != 0 && pathIDEquals(r, param, p.GuardianID), nil
+	}
+}
+
+func pathIDEquals(r *http.Request, param string, id int64) bool {
+	v, err := strconv.ParseInt(r.PathValue(param), 10, 64)
+	return err == nil && v == id
+}
+
+// ClassMembership — проверка, ведёт ли учитель класс (реализуется сервисом классов).
+type ClassMembership interface {
+	TeachesClass(ctx context.Context, teacherID, classID int64) (bool, error)
+}
+
+// AllowClassTeacher — учитель допускается только к классам, где он ведёт предмет или руководит.
+// param — имя параметра пути с id класса.
+func AllowClassTeacher(param string, m ClassMembership) Rule {
+	return func(r *http.Request, p domain.Principal) (bool, error) {
+		if p.Role != domain.RoleTeacher || p.TeacherID == 0 {
+			return false, nil
+		}
+		classID, err := strconv.ParseInt(r.PathValue(param), 10, 64)
+		if err != nil {
+			return false, nil
+		}
+		return m.TeachesClass(r.Context(), p.TeacherID, classID)
+	}
+}
+
+// StudentMembership — проверка, ведёт ли учитель класс ученика.
+type StudentMembership interface {
+	TeachesStudent(ctx context.Context, teacherID, studentID int64) (bool, error)
+}
+
+// AllowStudentTeacher — учитель видит только учеников своих классов. param — параметр пути с id ученика.
+func AllowStudentTeacher(param string, m StudentMembership) Rule {
+	return func(r *http.Request, p domain.Principal) (bool, error) {
+		if p.Role != domain.RoleTeacher || p.TeacherID == 0 {
+			return false, nil
+		}
+		studentID, err := strconv.ParseInt(r.PathValue(param), 10, 64)
+		if err != nil {
+			return false, nil
+		}
+		return m.TeachesStudent(r.Context(), p.TeacherID, studentID)
+	}
+}
+
+// GuardianMembership — проверка, связан ли представитель с учеником.
+type GuardianMembership interface {
+	IsGuardianOf(ctx context.Context, guardianID, studentID int64) (bool, error)
+}
+
+// AllowGuardianOf — представитель видит только своих детей. param — параметр пути с id ученика.
+func AllowGuardianOf(param string, m GuardianMembership) Rule {
+	return func(r *http.Request, p domain.Principal) (bool, error) {
+		if p.Role != domain.RoleGuardian || p.GuardianID == 0 {
+			return false, nil
+		}
+		studentID, err := strconv.ParseInt(r.PathValue(param), 10, 64)
+		if err != nil {
+			return false, nil
+		}
+		return m.IsGuardianOf(r.Context(), p.GuardianID, studentID)
+	}
+}
@@ -0,0 +1,34 @@
+package middlewares
+
+import "net/http"
+
+type Middleware func(http.Handler) http.Handler
+
+// Stack — неизменяемый стек middleware. Первый элемент оборачивает все остальные (самый внешний).
+type Stack struct {
+	mws []Middleware
+}
+
+func NewStack(mws ...Middleware) Stack {
+	return Stack{mws: append([]Middleware(nil), mws...)}
+}
+
+// Append возвращает новый стек с добавленными в конец (внутренними) middleware; исходный не меняется.
+func (s Stack) Append(mws ...Middleware) Stack {
+	out := make([]Middleware, 0, len(s.mws)+len(mws))
+	out = append(out, s.mws...)
+	out = append(out, mws...)
+	return Stack{mws: out}
+}
+
+// Then оборачивает h всеми middleware стека.
+func (s Stack) Then(h http.Handler) http.Handler {
+	for i := len(s.mws) - 1; i >= 0; i-- {
+		h = s.mws[i](h)
+	}
+	return h
+}
+
+func (s Stack) ThenFunc(h http.HandlerFunc) http.Handler {
+	return s.Then(h)
+}
@@ -0,0 +1,16 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"restapi/internal/transport/http/clientip"
+)
+
+// ClientIP кладёт в контекст адрес клиента с учётом доверенных прокси — его берут лимиты, логи и сессии.
+func ClientIP(res *clientip.Resolver) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(clientip.NewContext(r.Context(), res.ClientIP(r))))
+		})
+	}
+}
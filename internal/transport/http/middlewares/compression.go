@@ -2,42 +2,133 @@ package middlewares
 
 import (
 	"compress/gzip"
-	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 )
 
+// Compression сжимает ответ gzip, если клиент его принимает. Решение принимается в момент WriteHeader:
+// ответы без тела (1xx, 204, 304, HEAD), уже закодированные и уже сжатые форматы уходят как есть.
+// Content-Length обработчика относится к несжатому телу, поэтому при сжатии он удаляется.
 func Compression(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Check if the client accepts gzip encoding
-		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		// Ответ зависит от Accept-Encoding независимо от того, сжали ли именно этот.
+		w.Header().Add("Vary", "Accept-Encoding")
+		if r.Method == http.MethodHead || !acceptsGzip(r.Header.Get("Accept-Encoding")) {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Create a gzip writer
-		w.Header().Set("Content-Encoding", "gzip")
-
-		gz := gzip.NewWriter(w)
-		defer gz.Close()
+		gzw := &gzipResponseWriter{ResponseWriter: w}
+		defer gzw.close()
+		next.ServeHTTP(gzw, r)
+	})
+}
 
-		// wrap the response writer
-		gzw := &gzipResponseWriter{
-			ResponseWriter: w,
-			writer:         gz,
+// acceptsGzip разбирает Accept-Encoding: gzip или * без q=0.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
 		}
-		next.ServeHTTP(gzw, r)
+		q, ok := strings.CutPrefix(strings.TrimSpace(params), "q=")
+		if !ok {
+			return true
+		}
+		v, err := strconv.ParseFloat(q, 64)
+		return err == nil && v > 0
+	}
+	return false
+}
 
-		fmt.Println("Sent response with gzip encoding from middleware")
-	})
+// compressedTypes — форматы, которые уже сжаты: повторный gzip только тратит CPU.
+var compressedTypes = []string{
+	"application/pdf", "application/zip", "application/gzip", "application/x-gzip",
+	"font/woff", "font/woff2", "image/", "video/", "audio/",
+}
+
+func shouldCompress(status int, h http.Header) bool {
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	ct := strings.ToLower(h.Get("Content-Type"))
+	if strings.HasPrefix(ct, "image/svg+xml") {
+		return true
+	}
+	for _, t := range compressedTypes {
+		if strings.HasPrefix(ct, t) {
+			return false
+		}
+	}
+	return true
 }
 
-// Response writer
+// gzipResponseWriter включает gzip при первом WriteHeader, если ответ стоит сжимать.
 type gzipResponseWriter struct {
 	http.ResponseWriter
-	writer *gzip.Writer
+	gz          *gzip.Writer
+	wroteHeader bool
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	// 1xx не завершают ответ — пропускаем их, не принимая решения.
+	if status >= 100 && status < http.StatusOK {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.wroteHeader = true
+
+	h := w.Header()
+	if shouldCompress(status, h) {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", "gzip")
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		// net/http определил бы тип по уже сжатым байтам — определяем по исходным.
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.gz == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.gz.Write(b)
+}
+
+// Flush отдаёт клиенту уже сжатую часть ответа.
+func (w *gzipResponseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap нужен http.ResponseController.
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
 }
 
-func (gz *gzipResponseWriter) Write(b []byte) (int, error) {
-	return gz.writer.Write(b)
+func (w *gzipResponseWriter) close() {
+	if w.gz != nil {
+		_ = w.gz.Close()
+	}
 }
@@ -0,0 +1,116 @@
+package middlewares
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCompression(t *testing.T) {
+	body := strings.Repeat(`{"name":"value"}`, 100)
+
+	tests := []struct {
+		name           string
+		method         string
+		acceptEncoding string
+		status         int
+		header         map[string]string
+		wantGzip       bool
+	}{
+		{name: "json is compressed", acceptEncoding: "gzip, deflate, br", header: map[string]string{"Content-Type": "application/json"}, wantGzip: true},
+		{name: "content type is sniffed from plain body", acceptEncoding: "gzip", wantGzip: true},
+		{name: "client without gzip", acceptEncoding: "deflate, br", header: map[string]string{"Content-Type": "application/json"}},
+		{name: "gzip refused with q=0", acceptEncoding: "gzip;q=0, *", header: map[string]string{"Content-Type": "application/json"}},
+		{name: "wildcard", acceptEncoding: "*", header: map[string]string{"Content-Type": "application/json"}, wantGzip: true},
+		{name: "pdf is already compressed", acceptEncoding: "gzip", header: map[string]string{"Content-Type": "application/pdf"}},
+		{name: "png is already compressed", acceptEncoding: "gzip", header: map[string]string{"Content-Type": "image/png"}},
+		{name: "svg is text", acceptEncoding: "gzip", header: map[string]string{"Content-Type": "image/svg+xml"}, wantGzip: true},
+		{name: "already encoded", acceptEncoding: "gzip", header: map[string]string{"Content-Type": "text/plain", "Content-Encoding": "br"}},
+		{name: "no content", acceptEncoding: "gzip", status: http.StatusNoContent},
+		{name: "not modified", acceptEncoding: "gzip", status: http.StatusNotModified},
+		{name: "head", method: http.MethodHead, acceptEncoding: "gzip", header: map[string]string{"Content-Type": "application/json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			withBody := status != http.StatusNoContent && status != http.StatusNotModified
+
+			h := Compression(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				if withBody {
+					w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+				}
+				w.WriteHeader(status)
+				if withBody && r.Method != http.MethodHead {
+					_, _ = io.WriteString(w, body)
+				}
+			}))
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			srv := httptest.NewServer(h)
+			defer srv.Close()
+
+			req, err := http.NewRequest(method, srv.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			// Свой Transport: стандартный сам распаковал бы gzip и скрыл заголовки.
+			resp, err := (&http.Transport{DisableCompression: true}).RoundTrip(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != status {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, status)
+			}
+			if got := resp.Header.Values("Vary"); len(got) != 1 || got[0] != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", got)
+			}
+			if got := resp.Header.Get("Content-Encoding") == "gzip"; got != tt.wantGzip {
+				t.Fatalf("gzip = %v, want %v", got, tt.wantGzip)
+			}
+
+			raw, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+			// Content-Length несжатого тела под gzip обрывал загрузку на клиенте.
+			if cl := resp.Header.Get("Content-Length"); cl != "" && method != http.MethodHead && cl != strconv.Itoa(len(raw)) {
+				t.Errorf("Content-Length = %s, body is %d bytes", cl, len(raw))
+			}
+			got := raw
+			if tt.wantGzip {
+				zr, err := gzip.NewReader(bytes.NewReader(raw))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got, err = io.ReadAll(zr); err != nil {
+					t.Fatalf("gunzip: %v", err)
+				}
+			}
+			want := body
+			if !withBody || method == http.MethodHead {
+				want = ""
+			}
+			if string(got) != want {
+				t.Errorf("body = %d bytes, want %d", len(got), len(want))
+			}
+		})
+	}
+}
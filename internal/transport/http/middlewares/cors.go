@@ -1,48 +1,247 @@
 package middlewares
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"restapi/internal/transport/http/problem"
 )
 
-var allowedOrigins = []string{"http://localhost:300", "http://localhost:301"}
+// CorsOptions — политика CORS. Origins — точные origin ("https://app.example.com"), шаблоны
+// поддоменов ("https://*.example.com") или "*" — любой.
+type CorsOptions struct {
+	Origins          []string
+	Methods          []string
+	Headers          []string // "*" — любые заголовки запроса
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
 
-func Cors(next http.Handler) http.Handler {
+// originPattern — разобранный элемент Origins; для шаблона host хранит суффикс ".example.com[:port]".
+type originPattern struct {
+	scheme   string
+	host     string
+	wildcard bool
+}
+
+func (p originPattern) match(scheme, host string) bool {
+	if scheme != p.scheme {
+		return false
+	}
+	if !p.wildcard {
+		return host == p.host
+	}
+	sub, ok := strings.CutSuffix(host, p.host)
+	return ok && sub != "" && !strings.ContainsAny(sub, ":/")
+}
+
+type cors struct {
+	anyOrigin   bool
+	origins     []originPattern
+	methods     []string
+	anyHeader   bool
+	headers     []string // в нижнем регистре
+	allowMethod string
+	allowHeader string
+	expose      string
+	credentials bool
+	maxAge      string
+}
+
+// NewCors собирает middleware по политике. Запросы без Origin (curl, сервер-сервер) проходят
+// как есть; preflight отвечается 204 без вызова хендлера; чужому origin заголовки CORS не выдаются.
+func NewCors(o CorsOptions) (Middleware, error) {
+	c := &cors{credentials: o.AllowCredentials}
+
+	for _, s := range o.Origins {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if s == "*" {
+			c.anyOrigin = true
+			continue
+		}
+		p, err := parseOriginPattern(s)
+		if err != nil {
+			return nil, err
+		}
+		c.origins = append(c.origins, p)
+	}
+	if !c.anyOrigin && len(c.origins) == 0 {
+		return nil, errors.New("cors: no allowed origins")
+	}
+	// Браузер не принимает credentials вместе с "*", а отражать любой origin с cookie — дыра.
+	if c.anyOrigin && c.credentials {
+		return nil, errors.New(`cors: origin "*" cannot be combined with credentials`)
+	}
+
+	for _, m := range o.Methods {
+		if m = strings.ToUpper(strings.TrimSpace(m)); m != "" {
+			c.methods = append(c.methods, m)
+		}
+	}
+	if len(c.methods) == 0 {
+		return nil, errors.New("cors: no allowed methods")
+	}
+	c.allowMethod = strings.Join(c.methods, ", ")
+
+	var headers []string
+	for _, h := range o.Headers {
+		switch h = strings.TrimSpace(h); h {
+		case "":
+		case "*":
+			c.anyHeader = true
+		default:
+			headers = append(headers, h)
+			c.headers = append(c.headers, strings.ToLower(h))
+		}
+	}
+	c.allowHeader = strings.Join(headers, ", ")
+
+	var exposed []string
+	for _, h := range o.ExposedHeaders {
+		if h = strings.TrimSpace(h); h != "" {
+			exposed = append(exposed, h)
+		}
+	}
+	c.expose = strings.Join(exposed, ", ")
+
+	if o.MaxAge > 0 {
+		c.maxAge = strconv.FormatInt(int64(o.MaxAge/time.Second), 10)
+	}
+
+	return c.middleware, nil
+}
+
+// parseOriginPattern разбирает "scheme://host[:port]", где host может начинаться с "*.".
+func parseOriginPattern(s string) (originPattern, error) {
+	scheme, host, ok := strings.Cut(strings.ToLower(s), "://")
+	if !ok || scheme == "" || host == "" || strings.ContainsAny(host, "/?#") {
+		return originPattern{}, fmt.Errorf("cors: invalid origin %q, want scheme://host[:port]", s)
+	}
+	if rest, ok := strings.CutPrefix(host, "*."); ok {
+		if rest == "" || strings.Contains(rest, "*") {
+			return originPattern{}, fmt.Errorf("cors: invalid origin pattern %q", s)
+		}
+		return originPattern{scheme: scheme, host: "." + rest, wildcard: true}, nil
+	}
+	if strings.Contains(host, "*") {
+		return originPattern{}, fmt.Errorf("cors: wildcard is allowed only as the first label: %q", s)
+	}
+	return originPattern{scheme: scheme, host: host}, nil
+}
+
+func (c *cors) middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		origin := r.Header.Get("Origin")
 		h := w.Header()
 
-		if isAllowedOrigin(origin) {
-			h.Set("Access-Control-Allow-Origin", origin)
-		} else {
-			http.Error(w, "Not allowed by CORS", http.StatusForbidden)
+		// Ответ зависит от Origin — кеши не должны отдать его другому сайту.
+		if !c.anyOrigin {
+			h.Add("Vary", "Origin")
+		}
+		if origin == "" {
+			next.ServeHTTP(w, r)
 			return
 		}
 
-		h.Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		h.Set("Access-Control-Expose-Headers", "Authorization")
-		h.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		h.Set("Access-Control-Allow-Credentials", "true")
-		h.Set("Access-Control-Max-Age", "3600")
-
-		fmt.Println("r.Method", r.Method)
-		fmt.Println(" http.MethodOptions", http.MethodOptions)
-
-		if r.Method == http.MethodOptions {
-
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if preflight {
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+			c.preflight(w, r, origin)
 			return
 		}
 
+		if c.allowOrigin(origin) {
+			c.writeOrigin(h, origin)
+			if c.expose != "" {
+				h.Set("Access-Control-Expose-Headers", c.expose)
+			}
+		}
 		next.ServeHTTP(w, r)
 	})
 }
 
-func isAllowedOrigin(origin string) bool {
-	for _, allowedOrigin := range allowedOrigins {
-		if allowedOrigin == origin {
-			return true
+// preflight отвечает на OPTIONS с Access-Control-Request-Method: 204 с разрешениями или 403.
+func (c *cors) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	if !c.allowOrigin(origin) {
+		problem.WriteStatus(w, r, http.StatusForbidden, "origin not allowed by CORS")
+		return
+	}
+	if !slices.Contains(c.methods, r.Header.Get("Access-Control-Request-Method")) {
+		problem.WriteStatus(w, r, http.StatusForbidden, "method not allowed by CORS")
+		return
+	}
+	requested := splitList(r.Header.Values("Access-Control-Request-Headers"))
+	if !c.anyHeader {
+		for _, name := range requested {
+			if !slices.Contains(c.headers, strings.ToLower(name)) {
+				problem.WriteStatus(w, r, http.StatusForbidden, "header "+name+" not allowed by CORS")
+				return
+			}
 		}
 	}
 
+	h := w.Header()
+	c.writeOrigin(h, origin)
+	h.Set("Access-Control-Allow-Methods", c.allowMethod)
+	switch {
+	case c.anyHeader && len(requested) > 0:
+		// С credentials "*" в Allow-Headers браузер понимает буквально — отражаем запрошенные.
+		h.Set("Access-Control-Allow-Headers", strings.Join(requested, ", "))
+	case c.allowHeader != "":
+		h.Set("Access-Control-Allow-Headers", c.allowHeader)
+	}
+	if c.maxAge != "" {
+		h.Set("Access-Control-Max-Age", c.maxAge)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *cors) writeOrigin(h http.Header, origin string) {
+	if c.anyOrigin {
+		h.Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+	h.Set("Access-Control-Allow-Origin", origin)
+	if c.credentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func (c *cors) allowOrigin(origin string) bool {
+	if c.anyOrigin {
+		return true
+	}
+	scheme, host, ok := strings.Cut(strings.ToLower(origin), "://")
+	if !ok {
+		return false
+	}
+	for _, p := range c.origins {
+		if p.match(scheme, host) {
+			return true
+		}
+	}
 	return false
 }
+
+// splitList разбирает заголовок-список через запятую, пропуская пустые элементы.
+func splitList(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,244 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestOriginPatternMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		origin  string
+		want    bool
+	}{
+		{"https://*.example.com", "https://a.example.com", true},
+		{"https://*.example.com", "https://a.b.example.com", true},
+		{"https://*.example.com", "https://A.Example.COM", true},
+		{"https://*.example.com", "https://example.com", false},
+		{"https://*.example.com", "https://evilexample.com", false},
+		{"https://*.example.com", "https://a.example.com.evil.com", false},
+		{"https://*.example.com", "http://a.example.com", false},
+		{"https://*.example.com", "https://a.example.com:8443", false},
+		{"https://*.example.com:8443", "https://a.example.com:8443", true},
+		{"https://*.example.com:8443", "https://a.example.com", false},
+		{"https://*.example.com:8443", "https://a.example.com:9443", false},
+		{"http://localhost:3000", "http://localhost:3000", true},
+		{"http://localhost:3000", "http://localhost:300", false},
+		{"http://localhost:3000", "https://localhost:3000", false},
+		{"http://localhost:3000", "http://localhost", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.origin, func(t *testing.T) {
+			mw, err := NewCors(CorsOptions{Origins: []string{tt.pattern}, Methods: []string{"GET"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Origin", tt.origin)
+			w := httptest.NewRecorder()
+			mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(w, r)
+
+			if got := w.Header().Get("Access-Control-Allow-Origin") != ""; got != tt.want {
+				t.Errorf("allowed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCorsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		o    CorsOptions
+	}{
+		{"credentials with any origin", CorsOptions{Origins: []string{"*"}, Methods: []string{"GET"}, AllowCredentials: true}},
+		{"no origins", CorsOptions{Methods: []string{"GET"}}},
+		{"no methods", CorsOptions{Origins: []string{"https://example.com"}}},
+		{"no scheme", CorsOptions{Origins: []string{"example.com"}, Methods: []string{"GET"}}},
+		{"path in origin", CorsOptions{Origins: []string{"https://example.com/app"}, Methods: []string{"GET"}}},
+		{"wildcard in the middle", CorsOptions{Origins: []string{"https://a.*.com"}, Methods: []string{"GET"}}},
+		{"bare wildcard label", CorsOptions{Origins: []string{"https://*."}, Methods: []string{"GET"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCors(tt.o); err == nil {
+				t.Error("want error")
+			}
+		})
+	}
+
+	// Без credentials "*" допустим.
+	if _, err := NewCors(CorsOptions{Origins: []string{"*"}, Methods: []string{"GET"}}); err != nil {
+		t.Errorf("any origin without credentials: %v", err)
+	}
+}
+
+func TestCorsMiddleware(t *testing.T) {
+	opts := CorsOptions{
+		Origins:          []string{"http://localhost:3000", "https://*.example.com"},
+		Methods:          []string{"GET", "post"},
+		Headers:          []string{"Content-Type", "Authorization"},
+		ExposedHeaders:   []string{"RateLimit-Remaining", "Retry-After"},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		header     map[string]string
+		wantStatus int
+		wantNext   bool
+		wantHeader map[string]string // "" — заголовка быть не должно
+		wantVary   []string
+	}{
+		{
+			name:       "no origin passes through",
+			method:     http.MethodGet,
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+			wantVary:   []string{"Origin"},
+		},
+		{
+			name:       "allowed origin",
+			method:     http.MethodGet,
+			header:     map[string]string{"Origin": "https://app.example.com"},
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantHeader: map[string]string{
+				"Access-Control-Allow-Origin":      "https://app.example.com",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Expose-Headers":    "RateLimit-Remaining, Retry-After",
+			},
+			wantVary: []string{"Origin"},
+		},
+		{
+			name:       "foreign origin gets no CORS headers",
+			method:     http.MethodPost,
+			header:     map[string]string{"Origin": "https://evilexample.com"},
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": "", "Access-Control-Allow-Credentials": ""},
+			wantVary:   []string{"Origin"},
+		},
+		{
+			name:   "preflight",
+			method: http.MethodOptions,
+			header: map[string]string{
+				"Origin":                         "http://localhost:3000",
+				"Access-Control-Request-Method":  "POST",
+				"Access-Control-Request-Headers": "content-type, Authorization",
+			},
+			wantStatus: http.StatusNoContent,
+			wantHeader: map[string]string{
+				"Access-Control-Allow-Origin":      "http://localhost:3000",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Allow-Methods":     "GET, POST",
+				"Access-Control-Allow-Headers":     "Content-Type, Authorization",
+				"Access-Control-Max-Age":           "3600",
+			},
+			wantVary: []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:       "preflight from foreign origin",
+			method:     http.MethodOptions,
+			header:     map[string]string{"Origin": "https://example.com", "Access-Control-Request-Method": "GET"},
+			wantStatus: http.StatusForbidden,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+			wantVary:   []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"},
+		},
+		{
+			name:       "preflight with method not allowed",
+			method:     http.MethodOptions,
+			header:     map[string]string{"Origin": "http://localhost:3000", "Access-Control-Request-Method": "DELETE"},
+			wantStatus: http.StatusForbidden,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": ""},
+		},
+		{
+			name:   "preflight with header not allowed",
+			method: http.MethodOptions,
+			header: map[string]string{
+				"Origin":                         "http://localhost:3000",
+				"Access-Control-Request-Method":  "GET",
+				"Access-Control-Request-Headers": "X-Custom",
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name:       "OPTIONS without request method is not a preflight",
+			method:     http.MethodOptions,
+			header:     map[string]string{"Origin": "http://localhost:3000"},
+			wantStatus: http.StatusOK,
+			wantNext:   true,
+			wantHeader: map[string]string{"Access-Control-Allow-Origin": "http://localhost:3000", "Access-Control-Allow-Methods": ""},
+		},
+	}
+
+	mw, err := NewCors(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			r := httptest.NewRequest(tt.method, "/students", nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if called != tt.wantNext {
+				t.Errorf("next called = %v, want %v", called, tt.wantNext)
+			}
+			for k, want := range tt.wantHeader {
+				if got := w.Header().Get(k); got != want {
+					t.Errorf("%s = %q, want %q", k, got, want)
+				}
+			}
+			if tt.wantVary != nil && !slices.Equal(w.Header().Values("Vary"), tt.wantVary) {
+				t.Errorf("Vary = %q, want %q", w.Header().Values("Vary"), tt.wantVary)
+			}
+		})
+	}
+}
+
+func TestCorsAnyOrigin(t *testing.T) {
+	mw, err := NewCors(CorsOptions{Origins: []string{"*"}, Methods: []string{"GET"}, Headers: []string{"*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	r := httptest.NewRequest(http.MethodOptions, "/", nil)
+	r.Header.Set("Origin", "https://anything.test")
+	r.Header.Set("Access-Control-Request-Method", "GET")
+	r.Header.Set("Access-Control-Request-Headers", "X-Custom")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Allow-Origin = %q, want *", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Headers"); got != "X-Custom" {
+		t.Errorf("Allow-Headers = %q, want X-Custom", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Allow-Credentials = %q, want none", got)
+	}
+}
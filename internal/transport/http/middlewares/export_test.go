@@ -0,0 +1,4 @@
+package middlewares
+
+// NewMemoryStoreWithClock открывает внешним тестам конструктор с подменой часов.
+var NewMemoryStoreWithClock = newMemoryStore
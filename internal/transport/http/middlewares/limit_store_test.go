@@ -0,0 +1,20 @@
+package middlewares_test
+
+import (
+	"testing"
+	"time"
+
+	"restapi/internal/transport/http/middlewares"
+	"restapi/internal/transport/http/middlewares/limitstoretest"
+)
+
+func TestMemoryStore(t *testing.T) {
+	limitstoretest.Run(t, func(t *testing.T, clock *limitstoretest.Clock) middlewares.LimitStore {
+		s, err := middlewares.NewMemoryStoreWithClock(time.Hour, clock.Now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(s.Close)
+		return s
+	})
+}
@@ -0,0 +1,114 @@
+// Package limitstoretest — общий контракт хранилищ лимитов (middlewares.LimitStore): память и Redis
+// должны принимать одинаковые решения на одной последовательности запросов.
+package limitstoretest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"restapi/internal/transport/http/middlewares"
+)
+
+// Clock — часы, которые двигает тест.
+type Clock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func NewClock() *Clock {
+	return &Clock{t: time.Date(2026, 9, 1, 8, 0, 0, 0, time.UTC)}
+}
+
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+// Run проверяет решения хранилища. newStore создаёт пустое хранилище, которое берёт время из clock.
+func Run(t *testing.T, newStore func(t *testing.T, clock *Clock) middlewares.LimitStore) {
+	t.Run("allow", func(t *testing.T) { testAllow(t, newStore) })
+	t.Run("keys are independent", func(t *testing.T) { testKeys(t, newStore) })
+}
+
+func testAllow(t *testing.T, newStore func(t *testing.T, clock *Clock) middlewares.LimitStore) {
+	l := middlewares.Limit{Rate: 1, Burst: 3}
+
+	type step struct {
+		advance time.Duration
+		want    middlewares.Decision
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "burst then deny",
+			steps: []step{
+				{want: middlewares.Decision{Allowed: true, Remaining: 2, Reset: time.Second}},
+				{want: middlewares.Decision{Allowed: true, Remaining: 1, Reset: 2 * time.Second}},
+				{want: middlewares.Decision{Allowed: true, Remaining: 0, Reset: 3 * time.Second}},
+				{want: middlewares.Decision{RetryAfter: time.Second, Reset: 3 * time.Second}},
+				{advance: 500 * time.Millisecond, want: middlewares.Decision{RetryAfter: 500 * time.Millisecond, Reset: 2500 * time.Millisecond}},
+			},
+		},
+		{
+			name: "token refills at rate",
+			steps: []step{
+				{want: middlewares.Decision{Allowed: true, Remaining: 2, Reset: time.Second}},
+				{want: middlewares.Decision{Allowed: true, Remaining: 1, Reset: 2 * time.Second}},
+				{want: middlewares.Decision{Allowed: true, Remaining: 0, Reset: 3 * time.Second}},
+				{advance: time.Second, want: middlewares.Decision{Allowed: true, Remaining: 0, Reset: 3 * time.Second}},
+				{want: middlewares.Decision{RetryAfter: time.Second, Reset: 3 * time.Second}},
+			},
+		},
+		{
+			name: "idle client gets full burst back",
+			steps: []step{
+				{want: middlewares.Decision{Allowed: true, Remaining: 2, Reset: time.Second}},
+				{want: middlewares.Decision{Allowed: true, Remaining: 1, Reset: 2 * time.Second}},
+				{advance: time.Minute, want: middlewares.Decision{Allowed: true, Remaining: 2, Reset: time.Second}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := NewClock()
+			s := newStore(t, clock)
+			for i, st := range tt.steps {
+				clock.Advance(st.advance)
+				got, err := s.Allow(context.Background(), "k", l)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != st.want {
+					t.Errorf("step %d: got %+v, want %+v", i, got, st.want)
+				}
+			}
+		})
+	}
+}
+
+func testKeys(t *testing.T, newStore func(t *testing.T, clock *Clock) middlewares.LimitStore) {
+	s := newStore(t, NewClock())
+	l := middlewares.Limit{Rate: 1, Burst: 1}
+
+	if d, _ := s.Allow(context.Background(), "a", l); !d.Allowed {
+		t.Fatal("a: first request denied")
+	}
+	if d, _ := s.Allow(context.Background(), "a", l); d.Allowed {
+		t.Fatal("a: second request allowed")
+	}
+	if d, _ := s.Allow(context.Background(), "b", l); !d.Allowed {
+		t.Fatal("b: first request denied")
+	}
+}
@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"restapi/internal/transport/http/clientip"
+)
+
+// KeyByClientIP — адрес клиента из middleware ClientIP (с учётом доверенных прокси);
+// без него — адрес соединения.
+func KeyByClientIP(r *http.Request) string {
+	return ClientIPOf(r)
+}
+
+// ClientIPOf — адрес клиента для логов и сессий: из контекста (ClientIP), иначе из RemoteAddr без порта.
+func ClientIPOf(r *http.Request) string {
+	if ip := clientip.FromContext(r.Context()); ip != "" {
+		return ip
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// KeyByPrincipal — учётка из сессии (или ссылки iCal-ленты). Годится только после Auth:
+// без принципала ключ пустой.
+func KeyByPrincipal(r *http.Request) string {
+	p, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		return ""
+	}
+	return "exec:" + strconv.FormatInt(p.ExecID, 10)
+}
+
+// KeyByRoute — шаблон маршрута ServeMux ("GET /students/{id}"), а не путь: у всех учеников один ключ.
+// Заполнен только внутри маршрута, поэтому в глобальной цепочке ключ пустой.
+func KeyByRoute(r *http.Request) string {
+	return r.Pattern
+}
+
+// CombineKeys склеивает ключи; если любой из них пустой, пуст и результат.
+func CombineKeys(keyers ...Keyer) Keyer {
+	return func(r *http.Request) string {
+		parts := make([]string, len(keyers))
+		for i, k := range keyers {
+			if parts[i] = k(r); parts[i] == "" {
+				return ""
+			}
+		}
+		return strings.Join(parts, "|")
+	}
+}
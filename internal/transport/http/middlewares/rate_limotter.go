@@ -3,104 +3,247 @@ package middlewares
 import (
 	"context"
 	"errors"
-	"net"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	log "restapi/internal/logger"
+	"restapi/internal/transport/http/problem"
+
 	"golang.org/x/time/rate"
 )
 
 type Keyer func(*http.Request) string
 
+// Limit — квота token bucket: Rate запросов в секунду в среднем и всплеск до Burst.
+type Limit struct {
+	Rate  rate.Limit
+	Burst int
+}
+
+// Decision — итог проверки квоты; из него строятся заголовки RateLimit-* и Retry-After.
+type Decision struct {
+	Allowed    bool
+	Remaining  int           // сколько запросов ещё пройдёт без ожидания
+	Reset      time.Duration // через сколько квота восстановится полностью
+	RetryAfter time.Duration // при отказе — через сколько пройдёт следующий запрос
+}
+
+// LimitStore — хранилище состояния лимитов. MemoryStore держит его в памяти процесса,
+// Redis-реализация (infrastructure/redis) — общим для всех реплик.
+type LimitStore interface {
+	// Allow списывает один запрос клиента key, если он укладывается в квоту.
+	Allow(ctx context.Context, key string, l Limit) (Decision, error)
+}
+
+// Policy — именованная квота и способ различать клиентов. Имя входит в ключ хранилища,
+// поэтому у каждой политики свои счётчики даже для одного и того же клиента.
+type Policy struct {
+	Name  string
+	Limit Limit
+	Key   Keyer // nil — политика только для Check из хендлера, Middleware с ней не работает
+}
+
+// RateLimiter применяет одну политику; на группы маршрутов вешаются разные экземпляры.
+type RateLimiter struct {
+	store    LimitStore
+	policy   Policy
+	failOpen bool // пропускать запросы, если хранилище недоступно
+
+	// storeDown — последняя проверка упала на хранилище. Пишем в лог только смену состояния,
+	// а не каждый запрос: при отказе Redis иначе лог забивается ровно тогда, когда он нужнее всего.
+	storeDown atomic.Bool
+}
+
+func NewRateLimiter(store LimitStore, p Policy, failOpen bool) (*RateLimiter, error) {
+	if store == nil {
+		return nil, errors.New("limit store is nil")
+	}
+	if p.Name == "" {
+		return nil, errors.New("policy name is empty")
+	}
+	if p.Limit.Rate <= 0 {
+		return nil, fmt.Errorf("policy %s: rate must be > 0", p.Name)
+	}
+	if p.Limit.Burst <= 0 {
+		return nil, fmt.Errorf("policy %s: burst must be > 0", p.Name)
+	}
+
+	return &RateLimiter{store: store, policy: p, failOpen: failOpen}, nil
+}
+
+func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
+	if rl.policy.Key == nil {
+		panic("rate limit policy " + rl.policy.Name + " has no keyer")
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		k := rl.policy.Key(r)
+		if k == "" {
+			problem.WriteStatus(w, r, http.StatusBadRequest, "bad client key")
+			return
+		}
+		if !rl.Check(w, r, k) {
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Check списывает запрос клиента key по политике. Если квота исчерпана (или хранилище недоступно
+// при fail-closed), сам отвечает 429/503 и возвращает false. Хендлеры вызывают его для ключей,
+// которых нет до разбора тела (например, имя пользователя при входе).
+func (rl *RateLimiter) Check(w http.ResponseWriter, r *http.Request, key string) bool {
+	d, err := rl.store.Allow(r.Context(), rl.policy.Name+":"+key, rl.policy.Limit)
+	if err != nil {
+		if rl.storeDown.CompareAndSwap(false, true) {
+			log.Warn("rate limit store unavailable", "policy", rl.policy.Name, "fail_open", rl.failOpen, "err", err)
+		}
+		if !rl.failOpen {
+			problem.WriteStatus(w, r, http.StatusServiceUnavailable, "rate limiter is unavailable")
+			return false
+		}
+		return true
+	}
+	if rl.storeDown.CompareAndSwap(true, false) {
+		log.Info("rate limit store recovered", "policy", rl.policy.Name)
+	}
+
+	rl.writeHeaders(w.Header(), d)
+	if !d.Allowed {
+		w.Header().Set("Retry-After", strconv.FormatInt(ceilSeconds(d.RetryAfter), 10))
+		problem.WriteStatus(w, r, http.StatusTooManyRequests, "too many requests")
+		return false
+	}
+	return true
+}
+
+// writeHeaders пишет RateLimit-Limit/Remaining/Reset (IETF draft-ietf-httpapi-ratelimit-headers).
+// На запрос действуют несколько политик (общая на IP, на учётку) — в ответе остаётся та,
+// у которой меньше осталось запросов, а при отказе — отказавшая.
+func (rl *RateLimiter) writeHeaders(h http.Header, d Decision) {
+	if prev := h.Get("RateLimit-Remaining"); prev != "" && d.Allowed {
+		if n, err := strconv.Atoi(prev); err == nil && n <= d.Remaining {
+			return
+		}
+	}
+	h.Set("RateLimit-Limit", strconv.Itoa(rl.policy.Limit.Burst))
+	h.Set("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+	h.Set("RateLimit-Reset", strconv.FormatInt(ceilSeconds(d.Reset), 10))
+}
+
+// ceilSeconds округляет вверх до целых секунд: клиент, подождавший столько, гарантированно пройдёт.
+func ceilSeconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return int64((d + time.Second - 1) / time.Second)
+}
+
+// ForMethods применяет read к безопасным методам (GET, HEAD, OPTIONS), write — к остальным.
+func ForMethods(read, write Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		r, w := read(next), write(next)
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			switch req.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				r.ServeHTTP(rw, req)
+			default:
+				w.ServeHTTP(rw, req)
+			}
+		})
+	}
+}
+
 type visitor struct {
 	limiter  *rate.Limiter
 	lastSeen time.Time
 }
 
-type RateLimiter struct {
+// MemoryStore — лимиты в памяти процесса: у каждой реплики своя квота.
+type MemoryStore struct {
 	mu       sync.Mutex
 	visitors map[string]*visitor
 
-	rate  rate.Limit
-	burst int
-	ttl   time.Duration
-	key   Keyer
-
+	ttl  time.Duration
 	stop context.CancelFunc
+	now  func() time.Time
 }
 
-func NewRateLimiter(r rate.Limit, burst int, ttl time.Duration, key Keyer) (*RateLimiter, error) {
-	if key == nil {
-		return nil, errors.New("keyer is nil")
-	}
-	if r <= 0 {
-		return nil, errors.New("rate must be > 0")
-	}
-	if burst <= 0 {
-		return nil, errors.New("burst must be > 0")
-	}
+// NewMemoryStore запускает фоновую очистку клиентов, неактивных дольше ttl; остановить — Close.
+func NewMemoryStore(ttl time.Duration) (*MemoryStore, error) {
+	return newMemoryStore(ttl, time.Now)
+}
+
+// newMemoryStore — с подменяемыми часами для тестов.
+func newMemoryStore(ttl time.Duration, now func() time.Time) (*MemoryStore, error) {
 	if ttl <= 0 {
 		return nil, errors.New("ttl must be > 0")
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 
-	rl := &RateLimiter{
+	s := &MemoryStore{
 		visitors: make(map[string]*visitor),
-		rate:     r,
-		burst:    burst,
 		ttl:      ttl,
-		key:      key,
 		stop:     cancel,
+		now:      now,
 	}
 
-	go rl.cleanupLoop(ctx)
-	return rl, nil
+	go s.cleanupLoop(ctx)
+	return s, nil
 }
 
-func (rl *RateLimiter) Close() {
-	if rl.stop != nil {
-		rl.stop()
+func (s *MemoryStore) Close() {
+	if s.stop != nil {
+		s.stop()
 	}
 }
 
-func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		k := rl.key(r)
-		if k == "" {
-			http.Error(w, "bad client key", http.StatusBadRequest)
-			return
-		}
+// Allow резервирует токен; если его пришлось бы ждать, резерв отменяется и запрос отклоняется.
+func (s *MemoryStore) Allow(_ context.Context, key string, l Limit) (Decision, error) {
+	now := s.now()
+	lim := s.getLimiter(key, l, now)
 
-		lim := rl.getLimiter(k)
-		if !lim.Allow() {
-			http.Error(w, "Too many requests", http.StatusTooManyRequests)
-			return
-		}
+	res := lim.ReserveN(now, 1)
+	if delay := res.DelayFrom(now); delay > 0 {
+		res.CancelAt(now)
+		return Decision{RetryAfter: delay, Reset: refill(lim.TokensAt(now), l)}, nil
+	}
 
-		next.ServeHTTP(w, r)
-	})
+	tokens := lim.TokensAt(now)
+	return Decision{Allowed: true, Remaining: int(math.Floor(tokens)), Reset: refill(tokens, l)}, nil
 }
 
-func (rl *RateLimiter) getLimiter(k string) *rate.Limiter {
-	now := time.Now()
+// refill — за сколько корзина с tokens токенами наполнится до Burst.
+func refill(tokens float64, l Limit) time.Duration {
+	missing := float64(l.Burst) - tokens
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(missing / float64(l.Rate) * float64(time.Second))
+}
 
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
+func (s *MemoryStore) getLimiter(k string, l Limit, now time.Time) *rate.Limiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	if v, ok := rl.visitors[k]; ok {
+	if v, ok := s.visitors[k]; ok {
 		v.lastSeen = now
 		return v.limiter
 	}
 
-	lim := rate.NewLimiter(rl.rate, rl.burst)
-	rl.visitors[k] = &visitor{limiter: lim, lastSeen: now}
+	lim := rate.NewLimiter(l.Rate, l.Burst)
+	s.visitors[k] = &visitor{limiter: lim, lastSeen: now}
 	return lim
 }
 
-func (rl *RateLimiter) cleanupLoop(ctx context.Context) {
-	interval := rl.ttl / 2
+func (s *MemoryStore) cleanupLoop(ctx context.Context) {
+	interval := s.ttl / 2
 	if interval < time.Second {
 		interval = time.Second
 	}
@@ -113,24 +256,15 @@ func (rl *RateLimiter) cleanupLoop(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			cutoff := time.Now().Add(-rl.ttl)
+			cutoff := s.now().Add(-s.ttl)
 
-			rl.mu.Lock()
-			for k, v := range rl.visitors {
+			s.mu.Lock()
+			for k, v := range s.visitors {
 				if v.lastSeen.Before(cutoff) {
-					delete(rl.visitors, k)
+					delete(s.visitors, k)
 				}
 			}
-			rl.mu.Unlock()
+			s.mu.Unlock()
 		}
 	}
 }
-
-// KeyByRemoteIP — корректно "прямо сейчас" без nginx: берём IP из RemoteAddr (без порта).
-func KeyByRemoteIP(r *http.Request) string {
-	host, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err == nil {
-		return host
-	}
-	return r.RemoteAddr
-}
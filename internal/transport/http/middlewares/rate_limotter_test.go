@@ -0,0 +1,123 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubStore отдаёт заданные решения или ошибку.
+type stubStore struct {
+	mu   sync.Mutex
+	d    Decision
+	err  error
+	keys []string
+}
+
+func (s *stubStore) Allow(_ context.Context, key string, _ Limit) (Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = append(s.keys, key)
+	return s.d, s.err
+}
+
+func (s *stubStore) set(d Decision, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.d, s.err = d, err
+}
+
+func serveLimited(t *testing.T, rl *RateLimiter) (*httptest.ResponseRecorder, bool) {
+	t.Helper()
+	var called bool
+	h := rl.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "192.0.2.1:4000"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w, called
+}
+
+func TestRateLimiterStoreFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		failOpen   bool
+		wantStatus int
+		wantNext   bool
+	}{
+		{name: "fail open", failOpen: true, wantStatus: http.StatusOK, wantNext: true},
+		{name: "fail closed", failOpen: false, wantStatus: http.StatusServiceUnavailable, wantNext: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &stubStore{err: errors.New("connection refused")}
+			rl, err := NewRateLimiter(store, Policy{Name: "global", Limit: Limit{Rate: 1, Burst: 1}, Key: KeyByClientIP}, tt.failOpen)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < 3; i++ {
+				w, called := serveLimited(t, rl)
+				if w.Code != tt.wantStatus || called != tt.wantNext {
+					t.Fatalf("request %d: status %d, next %v; want %d, %v", i, w.Code, called, tt.wantStatus, tt.wantNext)
+				}
+				if h := w.Header().Get("RateLimit-Limit"); h != "" {
+					t.Errorf("RateLimit-Limit = %q without a decision", h)
+				}
+			}
+			if !rl.storeDown.Load() {
+				t.Error("store outage not recorded")
+			}
+
+			// Хранилище ожило — запросы снова считаются, состояние сбрасывается.
+			store.set(Decision{Allowed: true, Remaining: 0}, nil)
+			if w, called := serveLimited(t, rl); w.Code != http.StatusOK || !called {
+				t.Fatalf("after recovery: status %d, next %v", w.Code, called)
+			}
+			if rl.storeDown.Load() {
+				t.Error("store recovery not recorded")
+			}
+		})
+	}
+}
+
+func TestRateLimiterHeaders(t *testing.T) {
+	store := &stubStore{}
+	rl, err := NewRateLimiter(store, Policy{Name: "global", Limit: Limit{Rate: 1, Burst: 5}, Key: KeyByClientIP}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.set(Decision{Allowed: true, Remaining: 4, Reset: 1200 * time.Millisecond}, nil)
+	w, called := serveLimited(t, rl)
+	if w.Code != http.StatusOK || !called {
+		t.Fatalf("allowed: status %d, next %v", w.Code, called)
+	}
+	for k, want := range map[string]string{"RateLimit-Limit": "5", "RateLimit-Remaining": "4", "RateLimit-Reset": "2", "Retry-After": ""} {
+		if got := w.Header().Get(k); got != want {
+			t.Errorf("allowed: %s = %q, want %q", k, got, want)
+		}
+	}
+
+	store.set(Decision{RetryAfter: 300 * time.Millisecond, Reset: 5 * time.Second}, nil)
+	w, called = serveLimited(t, rl)
+	if w.Code != http.StatusTooManyRequests || called {
+		t.Fatalf("denied: status %d, next %v", w.Code, called)
+	}
+	for k, want := range map[string]string{"RateLimit-Remaining": "0", "RateLimit-Reset": "5", "Retry-After": "1"} {
+		if got := w.Header().Get(k); got != want {
+			t.Errorf("denied: %s = %q, want %q", k, got, want)
+		}
+	}
+
+	if got := store.keys[0]; got != "global:192.0.2.1" {
+		t.Errorf("store key = %q, want global:192.0.2.1", got)
+	}
+}
@@ -0,0 +1,32 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"restapi/internal/transport/http/requestid"
+)
+
+// maxRequestIDLen — длиннее пришедший ID не доверяем (защита логов от мусора).
+const maxRequestIDLen = 128
+
+// RequestID берёт X-Request-ID от клиента/прокси или генерирует новый, кладёт в контекст и в ответ.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestid.Header)
+		if id == "" || len(id) > maxRequestIDLen || !isPrintableASCII(id) {
+			id = requestid.New()
+		}
+
+		w.Header().Set(requestid.Header, id)
+		next.ServeHTTP(w, r.WithContext(requestid.NewContext(r.Context(), id)))
+	})
+}
+
+func isPrintableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -1,9 +1,11 @@
 package middlewares
 
 import (
-	"fmt"
 	"net/http"
 	"time"
+
+	log "restapi/internal/logger"
+	"restapi/internal/transport/http/requestid"
 )
 
 func ResponseTimeMiddleware(next http.Handler) http.Handler {
@@ -17,10 +19,14 @@ func ResponseTimeMiddleware(next http.Handler) http.Handler {
 
 		next.ServeHTTP(wrappedWriter, r)
 
-		// Calculate duration
-		duration := time.Since(start)
-
-		fmt.Printf("Request: %s %s - %d in %v\n", r.Method, r.URL.Path, wrappedWriter.statusCode, duration)
+		log.Info("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", wrappedWriter.statusCode,
+			"duration", time.Since(start),
+			"client_ip", ClientIPOf(r),
+			"request_id", requestid.FromContext(r.Context()),
+		)
 	})
 }
 
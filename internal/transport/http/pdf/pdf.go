@@ -0,0 +1,336 @@
+// Package pdf — минимальный генератор PDF 1.4 без внешних зависимостей: страницы A4, текст
+// стандартными шрифтами Helvetica, линии, прямоугольники и JPEG-изображения.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const ContentType = "application/pdf"
+
+// Размер страницы A4 в пунктах (1/72 дюйма).
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Font — один из стандартных шрифтов (не встраиваются в файл).
+type Font int
+
+const (
+	Regular Font = iota
+	Bold
+)
+
+func (f Font) resource() string {
+	if f == Bold {
+		return "F2"
+	}
+	return "F1"
+}
+
+// Color — цвет RGB.
+type Color struct{ R, G, B uint8 }
+
+var (
+	Black = Color{0, 0, 0}
+	Gray  = Color{110, 110, 110}
+	Light = Color{235, 235, 235}
+	White = Color{255, 255, 255}
+)
+
+// ParseColor разбирает цвет вида "#1F4E79" или "1F4E79".
+func ParseColor(s string) (Color, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) != 6 {
+		return Color{}, fmt.Errorf("pdf: color %q: want #RRGGBB", s)
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("pdf: color %q: want #RRGGBB", s)
+	}
+	return Color{uint8(v >> 16), uint8(v >> 8), uint8(v)}, nil
+}
+
+func (c Color) operands() string {
+	return num(float64(c.R)/255) + " " + num(float64(c.G)/255) + " " + num(float64(c.B)/255)
+}
+
+// Info — метаданные документа.
+type Info struct {
+	Title   string
+	Author  string
+	Subject string
+	Created time.Time
+}
+
+// Image — изображение, добавленное в документ; рисуется на любой странице через Page.Image.
+type Image struct {
+	name          string
+	Width, Height int
+}
+
+type imageObject struct {
+	Image
+	data       []byte
+	colorSpace string
+	inverted   bool // Adobe CMYK JPEG хранит инвертированные значения
+}
+
+// Document — PDF-документ. Страницы добавляются через AddPage и пишутся целиком в WriteTo.
+type Document struct {
+	info   Info
+	pages  []*Page
+	images []imageObject
+}
+
+func New(info Info) *Document {
+	return &Document{info: info}
+}
+
+// AddPage добавляет страницу A4.
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Pages — число страниц.
+func (d *Document) Pages() int { return len(d.pages) }
+
+// Page возвращает страницу по номеру с нуля (например, чтобы дописать колонтитулы).
+func (d *Document) Page(i int) *Page { return d.pages[i] }
+
+// AddJPEG добавляет JPEG-изображение; данные встраиваются без перекодирования (DCTDecode).
+func (d *Document) AddJPEG(data []byte) (Image, error) {
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Image{}, fmt.Errorf("pdf: decode jpeg: %w", err)
+	}
+
+	obj := imageObject{Image: Image{name: "Im" + strconv.Itoa(len(d.images)+1), Width: cfg.Width, Height: cfg.Height}, data: data}
+	switch cfg.ColorModel {
+	case color.GrayModel:
+		obj.colorSpace = "/DeviceGray"
+	case color.CMYKModel:
+		obj.colorSpace, obj.inverted = "/DeviceCMYK", true
+	default:
+		obj.colorSpace = "/DeviceRGB"
+	}
+	d.images = append(d.images, obj)
+	return obj.Image, nil
+}
+
+// ValidJPEG сообщает, можно ли встроить data как изображение.
+func ValidJPEG(data []byte) error {
+	_, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err == nil && format != "jpeg" {
+		err = errors.New("not a jpeg")
+	}
+	return err
+}
+
+// Page — страница. Координаты — в пунктах от левого верхнего угла, y растёт вниз.
+type Page struct {
+	buf    bytes.Buffer
+	images []string
+}
+
+// Text выводит строку s с базовой линией на высоте y.
+func (p *Page) Text(x, y float64, f Font, size float64, c Color, s string) {
+	fmt.Fprintf(&p.buf, "BT /%s %s Tf %s rg %s %s Td (%s) Tj ET\n",
+		f.resource(), num(size), c.operands(), num(x), num(PageHeight-y), escape(encode(s)))
+}
+
+// TextRight выводит строку, выровненную по правому краю x.
+func (p *Page) TextRight(x, y float64, f Font, size float64, c Color, s string) {
+	p.Text(x-Width(f, size, s), y, f, size, c, s)
+}
+
+// TextCenter выводит строку по центру относительно x.
+func (p *Page) TextCenter(x, y float64, f Font, size float64, c Color, s string) {
+	p.Text(x-Width(f, size, s)/2, y, f, size, c, s)
+}
+
+// Line рисует отрезок толщиной width.
+func (p *Page) Line(x1, y1, x2, y2, width float64, c Color) {
+	fmt.Fprintf(&p.buf, "%s w %s RG %s %s m %s %s l S\n",
+		num(width), c.operands(), num(x1), num(PageHeight-y1), num(x2), num(PageHeight-y2))
+}
+
+// Rect закрашивает прямоугольник с левым верхним углом (x, y).
+func (p *Page) Rect(x, y, w, h float64, fill Color) {
+	fmt.Fprintf(&p.buf, "%s rg %s %s %s %s re f\n",
+		fill.operands(), num(x), num(PageHeight-y-h), num(w), num(h))
+}
+
+// Image рисует изображение в прямоугольнике с левым верхним углом (x, y).
+func (p *Page) Image(img Image, x, y, w, h float64) {
+	p.images = append(p.images, img.name)
+	fmt.Fprintf(&p.buf, "q %s 0 0 %s %s %s cm /%s Do Q\n",
+		num(w), num(h), num(x), num(PageHeight-y-h), img.name)
+}
+
+// WriteTo пишет документ целиком.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	ow := &objectWriter{}
+	ow.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Номера объектов: 1 — каталог, 2 — дерево страниц, 3–4 — шрифты, 5 — метаданные,
+	// далее изображения, затем пары «страница, содержимое».
+	const catalog, pagesRoot, fontRegular, fontBold, infoObj = 1, 2, 3, 4, 5
+	imageNum := make(map[string]int, len(d.images))
+	next := 6
+	for _, img := range d.images {
+		imageNum[img.name] = next
+		next++
+	}
+	pageNum := make([]int, len(d.pages))
+	for i := range d.pages {
+		pageNum[i] = next
+		next += 2
+	}
+
+	ow.object(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesRoot))
+
+	kids := make([]string, len(pageNum))
+	for i, n := range pageNum {
+		kids[i] = fmt.Sprintf("%d 0 R", n)
+	}
+	ow.object(pagesRoot, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+
+	ow.object(fontRegular, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	ow.object(fontBold, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	ow.object(infoObj, d.infoDict())
+
+	for _, img := range d.images {
+		dict := fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace %s /BitsPerComponent 8 /Filter /DCTDecode",
+			img.Width, img.Height, img.colorSpace)
+		if img.inverted {
+			dict += " /Decode [1 0 1 0 1 0 1 0]"
+		}
+		ow.stream(imageNum[img.name], dict, img.data)
+	}
+
+	for i, p := range d.pages {
+		var xobjects strings.Builder
+		seen := make(map[string]bool)
+		for _, name := range p.images {
+			if !seen[name] {
+				seen[name] = true
+				fmt.Fprintf(&xobjects, " /%s %d 0 R", name, imageNum[name])
+			}
+		}
+		resources := fmt.Sprintf("<< /Font << /F1 %d 0 R /F2 %d 0 R >>", fontRegular, fontBold)
+		if xobjects.Len() > 0 {
+			resources += " /XObject <<" + xobjects.String() + " >>"
+		}
+		resources += " >>"
+
+		ow.object(pageNum[i], fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources %s /Contents %d 0 R >>",
+			pagesRoot, num(PageWidth), num(PageHeight), resources, pageNum[i]+1))
+
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		zw.Write(p.buf.Bytes())
+		zw.Close()
+		ow.stream(pageNum[i]+1, "<< /Filter /FlateDecode", z.Bytes())
+	}
+
+	xref := ow.buf.Len()
+	fmt.Fprintf(&ow.buf, "xref\n0 %d\n0000000000 65535 f \n", next)
+	for n := 1; n < next; n++ {
+		fmt.Fprintf(&ow.buf, "%010d 00000 n \n", ow.offsets[n])
+	}
+	fmt.Fprintf(&ow.buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", next, catalog, infoObj, xref)
+
+	return ow.buf.WriteTo(w)
+}
+
+func (d *Document) infoDict() string {
+	var b strings.Builder
+	b.WriteString("<< /Producer ")
+	b.WriteString(textString("school-api"))
+	for _, kv := range [...]struct{ key, value string }{
+		{"Title", d.info.Title}, {"Author", d.info.Author}, {"Subject", d.info.Subject},
+	} {
+		if kv.value != "" {
+			b.WriteString(" /" + kv.key + " " + textString(kv.value))
+		}
+	}
+	if !d.info.Created.IsZero() {
+		b.WriteString(" /CreationDate (D:" + d.info.Created.UTC().Format("20060102150405") + "Z)")
+	}
+	b.WriteString(" >>")
+	return b.String()
+}
+
+type objectWriter struct {
+	buf     bytes.Buffer
+	offsets map[int]int
+}
+
+func (ow *objectWriter) object(n int, body string) {
+	ow.begin(n)
+	ow.buf.WriteString(body)
+	ow.buf.WriteString("\nendobj\n")
+}
+
+// stream пишет объект-поток; dict — открытый словарь без /Length и закрывающих «>>».
+func (ow *objectWriter) stream(n int, dict string, data []byte) {
+	ow.begin(n)
+	fmt.Fprintf(&ow.buf, "%s /Length %d >>\nstream\n", dict, len(data))
+	ow.buf.Write(data)
+	ow.buf.WriteString("\nendstream\nendobj\n")
+}
+
+func (ow *objectWriter) begin(n int) {
+	if ow.offsets == nil {
+		ow.offsets = make(map[int]int)
+	}
+	ow.offsets[n] = ow.buf.Len()
+	fmt.Fprintf(&ow.buf, "%d 0 obj\n", n)
+}
+
+// num форматирует число для операторов PDF: не более двух знаков после точки.
+func num(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
+
+// escape экранирует байтовую строку для литерала (…) в потоке содержимого.
+func escape(b []byte) string {
+	var s strings.Builder
+	for _, c := range b {
+		switch c {
+		case '\\', '(', ')':
+			s.WriteByte('\\')
+			s.WriteByte(c)
+		default:
+			s.WriteByte(c)
+		}
+	}
+	return s.String()
+}
+
+// textString — строка метаданных в UTF-16BE с BOM (подходит для любого алфавита).
+func textString(s string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	b.WriteString(">")
+	return b.String()
+}
@@ -0,0 +1,287 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"restapi/internal/domain"
+)
+
+// Branding — оформление документов школы.
+type Branding struct {
+	SchoolName string
+	Address    string
+	Principal  string // ФИО директора в блоке подписей
+	Accent     Color
+	Logo       []byte // JPEG, необязательно
+	PublicURL  string // адрес API для ссылки проверки выписки, например https://school.example/api
+}
+
+const (
+	margin       = 42.0
+	contentWidth = PageWidth - 2*margin
+	footerTop    = PageHeight - margin + 12 // базовая линия колонтитула
+	bodyBottom   = PageHeight - margin - 16 // ниже основной текст не опускается
+)
+
+// layout — вёрстка сверху вниз с переносом на новую страницу.
+type layout struct {
+	doc  *Document
+	b    Branding
+	logo *Image
+	page *Page
+	y    float64
+}
+
+func newLayout(info Info, b Branding) (*layout, error) {
+	l := &layout{doc: New(info), b: b}
+	if len(b.Logo) > 0 {
+		img, err := l.doc.AddJPEG(b.Logo)
+		if err != nil {
+			return nil, err
+		}
+		l.logo = &img
+	}
+	l.newPage()
+	return l, nil
+}
+
+// newPage открывает страницу и рисует шапку школы.
+func (l *layout) newPage() {
+	l.page = l.doc.AddPage()
+	p := l.page
+
+	x := margin
+	if l.logo != nil && l.logo.Height > 0 {
+		h := 44.0
+		w := h * float64(l.logo.Width) / float64(l.logo.Height)
+		p.Image(*l.logo, margin, margin, w, h)
+		x += w + 12
+	}
+	p.Text(x, margin+18, Bold, 15, l.b.Accent, Truncate(Bold, 15, l.b.SchoolName, margin+contentWidth-x))
+	if l.b.Address != "" {
+		p.Text(x, margin+33, Regular, 9, Gray, Truncate(Regular, 9, l.b.Address, margin+contentWidth-x))
+	}
+	p.Rect(margin, margin+52, contentWidth, 2.5, l.b.Accent)
+
+	l.y = margin + 76
+}
+
+// ensure переносит вёрстку на новую страницу, если до низа осталось меньше h. true — был перенос.
+func (l *layout) ensure(h float64) bool {
+	if l.y+h <= bodyBottom {
+		return false
+	}
+	l.newPage()
+	return true
+}
+
+// footer дописывает колонтитулы на все страницы (число страниц известно только в конце).
+func (l *layout) footer(left string) {
+	n := l.doc.Pages()
+	for i := range n {
+		p := l.doc.Page(i)
+		p.Line(margin, footerTop-10, margin+contentWidth, footerTop-10, 0.5, Light)
+		p.Text(margin, footerTop, Regular, 8, Gray, left)
+		p.TextRight(margin+contentWidth, footerTop, Regular, 8, Gray, fmt.Sprintf("Page %d of %d", i+1, n))
+	}
+}
+
+// heading — заголовок раздела.
+func (l *layout) heading(s string) {
+	l.ensure(40)
+	l.y += 8
+	l.page.Text(margin, l.y, Bold, 11, l.b.Accent, s)
+	l.y += 14
+}
+
+// paragraph — текст с переносом по ширине страницы.
+func (l *layout) paragraph(s string, size float64) {
+	for _, line := range Wrap(Regular, size, s, contentWidth) {
+		l.ensure(size + 4)
+		l.page.Text(margin, l.y+size, Regular, size, Black, line)
+		l.y += size + 4
+	}
+}
+
+// field — подпись и значение, как в шапке документа.
+func (l *layout) field(x, y float64, label, value string, width float64) {
+	l.page.Text(x, y, Regular, 8, Gray, label)
+	l.page.Text(x, y+13, Bold, 11, Black, Truncate(Bold, 11, value, width))
+}
+
+// column — колонка таблицы.
+type column struct {
+	title string
+	width float64
+	right bool
+}
+
+// table рисует таблицу с повтором шапки на каждой странице. Текст последней колонки
+// переносится по строкам, остальные обрезаются.
+func (l *layout) table(cols []column, rows [][]string, bold func(i int) bool) {
+	const size, pad, lineH = 9.0, 5.0, 11.0
+
+	header := func() {
+		l.page.Rect(margin, l.y, contentWidth, lineH+2*pad, l.b.Accent)
+		x := margin
+		for _, c := range cols {
+			l.cell(c, x, l.y+pad+size-1, Bold, size, White, c.title)
+			x += c.width
+		}
+		l.y += lineH + 2*pad
+	}
+	l.ensure(2 * (lineH + 2*pad))
+	header()
+
+	last := len(cols) - 1
+	for i, row := range rows {
+		font := Regular
+		if bold != nil && bold(i) {
+			font = Bold
+		}
+		wrapped := Wrap(Regular, size, row[last], cols[last].width-2*pad)
+		h := float64(len(wrapped))*lineH + 2*pad
+
+		if l.ensure(h) {
+			header()
+		}
+		if i%2 == 1 {
+			l.page.Rect(margin, l.y, contentWidth, h, Light)
+		}
+		x := margin
+		for j, c := range cols[:last] {
+			l.cell(c, x, l.y+pad+size-1, font, size, Black, row[j])
+			x += c.width
+		}
+		for k, line := range wrapped {
+			l.page.Text(x+pad, l.y+pad+size-1+float64(k)*lineH, Regular, size, Black, line)
+		}
+		l.y += h
+	}
+	l.page.Line(margin, l.y, margin+contentWidth, l.y, 0.5, l.b.Accent)
+}
+
+func (l *layout) cell(c column, x, y float64, f Font, size float64, col Color, s string) {
+	const pad = 5.0
+	s = Truncate(f, size, s, c.width-2*pad)
+	if c.right {
+		l.page.TextRight(x+c.width-pad, y, f, size, col, s)
+		return
+	}
+	l.page.Text(x+pad, y, f, size, col, s)
+}
+
+// signatures — блок подписей: линия, под ней роль и имя.
+func (l *layout) signatures(sigs [][2]string) {
+	const colW, rowH = contentWidth / 2, 46.0
+	rowsNeeded := (len(sigs) + 1) / 2
+	l.ensure(float64(rowsNeeded)*rowH + 8)
+	l.y += 8
+
+	for i, s := range sigs {
+		x := margin + float64(i%2)*colW
+		y := l.y + float64(i/2)*rowH + 24
+		l.page.Line(x, y, x+colW-24, y, 0.7, Black)
+		l.page.Text(x, y+11, Regular, 8, Gray, s[0])
+		if s[1] != "" {
+			l.page.TextRight(x+colW-24, y+11, Regular, 8, Black, s[1])
+		}
+	}
+	l.y += float64(rowsNeeded) * rowH
+}
+
+// ReportCard пишет табель ученика в PDF.
+func ReportCard(w io.Writer, b Branding, card domain.ReportCard) error {
+	l, err := newLayout(Info{
+		Title:   "Report card — " + card.StudentName + ", " + card.Term.Name,
+		Author:  b.SchoolName,
+		Subject: "Report card",
+		Created: card.GeneratedAt,
+	}, b)
+	if err != nil {
+		return err
+	}
+	p := l.page
+
+	p.Text(margin, l.y, Bold, 18, Black, "Report card")
+	p.TextRight(margin+contentWidth, l.y, Regular, 10, Gray, card.Term.Name+", "+yearLabel(card.Term.AcademicYear))
+	l.y += 22
+
+	half := contentWidth / 2
+	l.field(margin, l.y, "Student", card.StudentName, half-12)
+	l.field(margin+half, l.y, "Class", orDash(card.ClassName), half)
+	l.y += 32
+	l.field(margin, l.y, "Homeroom teacher", orDash(card.HomeroomTeacher), half-12)
+	l.field(margin+half, l.y, "Period", card.Term.StartDate.Format("02.01.2006")+" – "+card.Term.EndDate.Format("02.01.2006"), half)
+	l.y += 34
+
+	l.heading("Academic performance")
+	cols := []column{
+		{title: "Subject", width: 128},
+		{title: "Teacher", width: 112},
+		{title: "Grades", width: 46, right: true},
+		{title: "Average", width: 56, right: true},
+		{title: "Comment", width: contentWidth - 342},
+	}
+	rows := make([][]string, 0, len(card.Subjects)+1)
+	for _, s := range card.Subjects {
+		rows = append(rows, []string{s.SubjectName, orDash(s.TeacherName), strconv.Itoa(s.GradesCount), percent(s.Average), s.Comment})
+	}
+	rows = append(rows, []string{"Overall average", "", "", percent(card.Average), ""})
+	l.table(cols, rows, func(i int) bool { return i == len(rows)-1 })
+	l.y += 6
+
+	l.heading("Attendance")
+	a := card.Attendance
+	rate := a.Rate
+	l.table([]column{
+		{title: "Present", width: contentWidth / 6, right: true},
+		{title: "Late", width: contentWidth / 6, right: true},
+		{title: "Absent", width: contentWidth / 6, right: true},
+		{title: "Excused", width: contentWidth / 6, right: true},
+		{title: "Total marks", width: contentWidth / 6, right: true},
+		{title: "Attendance", width: contentWidth / 6},
+	}, [][]string{{
+		strconv.Itoa(a.Present), strconv.Itoa(a.Late), strconv.Itoa(a.Absent),
+		strconv.Itoa(a.Excused), strconv.Itoa(a.Total), percent(&rate),
+	}}, nil)
+	l.y += 6
+
+	if card.Comment != "" {
+		l.heading("Homeroom teacher's comment")
+		l.paragraph(card.Comment, 10)
+	}
+
+	l.y += 10
+	l.signatures([][2]string{
+		{"Principal", b.Principal},
+		{"Homeroom teacher", card.HomeroomTeacher},
+		{"Parent / guardian", ""},
+		{"Date", ""},
+	})
+
+	l.footer(fmt.Sprintf("%s · generated %s", b.SchoolName, card.GeneratedAt.Format("02.01.2006")))
+	_, err = l.doc.WriteTo(w)
+	return err
+}
+
+// yearLabel — учебный год в виде 2025/26.
+func yearLabel(year int) string {
+	return fmt.Sprintf("%d/%02d", year, (year+1)%100)
+}
+
+func percent(v *float64) string {
+	if v == nil {
+		return "—"
+	}
+	return strconv.FormatFloat(*v, 'f', 1, 64) + "%"
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return s
+}
@@ -0,0 +1,138 @@
+package pdf
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Ширины глифов Helvetica и Helvetica-Bold для символов 32–126 (из AFM, единицы 1/1000 кегля).
+var (
+	regularWidths = [...]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	boldWidths = [...]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// Символы вне ASCII, которые есть в WinAnsiEncoding.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94,
+	'•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// Кириллицы в стандартных шрифтах нет: она транслитерируется (ГОСТ 7.79-2000, схема Б, упрощённо).
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh", 'з': "z",
+	'и': "i", 'й': "j", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "x", 'ц': "cz", 'ч': "ch", 'ш': "sh", 'щ': "shh",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", '№': "No.",
+}
+
+// encode переводит строку в байты WinAnsiEncoding. Кириллица транслитерируется,
+// прочие непредставимые символы заменяются на «?».
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x80 && unicode.IsPrint(r):
+			out = append(out, byte(r))
+		case r == '\t' || r == '\n' || r == '\r':
+			out = append(out, ' ')
+		case r >= 0xA0 && r <= 0xFF:
+			out = append(out, byte(r))
+		case winAnsi[r] != 0:
+			out = append(out, winAnsi[r])
+		default:
+			if t, ok := translit[unicode.ToLower(r)]; ok {
+				if unicode.IsUpper(r) && t != "" {
+					t = strings.ToUpper(t[:1]) + t[1:]
+				}
+				out = append(out, t...)
+			} else if unicode.IsPrint(r) {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+// Width — ширина строки в пунктах.
+func Width(f Font, size float64, s string) float64 {
+	widths := regularWidths[:]
+	if f == Bold {
+		widths = boldWidths[:]
+	}
+	total := 0
+	for _, c := range encode(s) {
+		if c >= 32 && c <= 126 {
+			total += widths[c-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// Wrap разбивает текст на строки не шире width. Слишком длинные слова режутся.
+func Wrap(f Font, size float64, s string, width float64) []string {
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if Width(f, size, candidate) <= width {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			for Width(f, size, word) > width {
+				cut := fitPrefix(f, size, word, width)
+				lines = append(lines, word[:cut])
+				word = word[cut:]
+			}
+			line = word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Truncate обрезает строку до width, добавляя многоточие.
+func Truncate(f Font, size float64, s string, width float64) string {
+	if Width(f, size, s) <= width {
+		return s
+	}
+	cut := fitPrefix(f, size, s, width-Width(f, size, "…"))
+	return strings.TrimSpace(s[:cut]) + "…"
+}
+
+// fitPrefix — длина в байтах самого длинного префикса s (по границе руны), который помещается
+// в width; не меньше одной руны.
+func fitPrefix(f Font, size float64, s string, width float64) int {
+	end := 0
+	for i, r := range s {
+		next := i + len(string(r))
+		if end > 0 && Width(f, size, s[:next]) > width {
+			break
+		}
+		end = next
+	}
+	return end
+}
@@ -0,0 +1,118 @@
+package pdf
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"restapi/internal/domain"
+)
+
+// Transcript пишет выданную выписку в PDF: курсы по годам, GPA и код проверки.
+func Transcript(w io.Writer, b Branding, t domain.Transcript) error {
+	l, err := newLayout(Info{
+		Title:   "Transcript — " + t.StudentName,
+		Author:  b.SchoolName,
+		Subject: "Official transcript " + t.Hash,
+		Created: t.IssuedAt,
+	}, b)
+	if err != nil {
+		return err
+	}
+	p := l.page
+
+	p.Text(margin, l.y, Bold, 18, Black, "Official transcript")
+	p.TextRight(margin+contentWidth, l.y, Regular, 10, Gray, "Issued "+t.IssuedAt.Format("02.01.2006"))
+	l.y += 22
+
+	birth := "—"
+	if t.BirthDate != nil {
+		birth = t.BirthDate.Format("02.01.2006")
+	}
+	half := contentWidth / 2
+	l.field(margin, l.y, "Student", t.StudentName, half-12)
+	l.field(margin+half, l.y, "Date of birth", birth, half)
+	l.y += 32
+	l.field(margin, l.y, "Status", string(t.Status), half-12)
+	l.field(margin+half, l.y, "Grading scale", t.Scale.Name, half)
+	l.y += 34
+
+	cols := []column{
+		{title: "Subject", width: contentWidth - 264},
+		{title: "Credits", width: 60, right: true},
+		{title: "Percent", width: 68, right: true},
+		{title: "Grade", width: 68, right: true},
+		{title: "Points", width: 68, right: true},
+	}
+	for _, y := range t.Years {
+		title := yearLabel(y.AcademicYear)
+		if y.ClassName != "" {
+			title += " · class " + y.ClassName
+		}
+		l.heading(title)
+
+		rows := make([][]string, 0, len(y.Courses)+1)
+		for _, c := range y.Courses {
+			rows = append(rows, []string{c.SubjectName, decimal(c.Credits, 1), decimal(c.Percent, 1) + "%", c.Grade, decimal(c.Points, 2)})
+		}
+		rows = append(rows, []string{"Year GPA", decimal(y.Credits, 1), "", "", decimal(y.GPA, 2)})
+		l.table(cols, rows, func(i int) bool { return i == len(rows)-1 })
+		l.y += 6
+	}
+	if len(t.Years) == 0 {
+		l.paragraph("No completed courses.", 10)
+	}
+
+	l.heading("Summary")
+	l.table([]column{
+		{title: "Total credits", width: contentWidth / 3, right: true},
+		{title: "Cumulative GPA", width: contentWidth / 3, right: true},
+		{title: "Out of", width: contentWidth / 3},
+	}, [][]string{{decimal(t.TotalCredits, 1), decimal(t.GPA, 2), decimal(t.Scale.MaxPoints, 0)}}, nil)
+	l.y += 4
+	if legend := scaleLegend(t.Scale); legend != "" {
+		l.paragraph(legend, 8)
+	}
+
+	l.heading("Verification")
+	verify := "/transcripts/verify/" + t.Hash
+	if b.PublicURL != "" {
+		verify = strings.TrimRight(b.PublicURL, "/") + verify
+	}
+	l.paragraph("This transcript is valid only if it can be verified at:", 9)
+	l.paragraph(verify, 8)
+
+	l.y += 10
+	l.signatures([][2]string{
+		{"Principal", b.Principal},
+		{"School seal", ""},
+	})
+
+	l.footer(fmt.Sprintf("%s · transcript %s", b.SchoolName, t.Hash[:min(len(t.Hash), 16)]))
+	_, err = l.doc.WriteTo(w)
+	return err
+}
+
+// scaleLegend — расшифровка интервальной шкалы: «5: 85–100%, 4: 70–85%, …».
+func scaleLegend(s domain.TranscriptScale) string {
+	if len(s.Bands) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(s.Bands))
+	upper := 100.0
+	for _, b := range s.Bands {
+		parts = append(parts, fmt.Sprintf("%s (%s pts): %s–%s%%", b.Label, decimal(b.Points, 2), decimal(b.MinPercent, 1), decimal(upper, 1)))
+		upper = b.MinPercent
+	}
+	return "Scale " + s.Name + ": " + strings.Join(parts, ", ") + "."
+}
+
+// decimal форматирует число с не более чем prec знаками после точки, без хвостовых нулей.
+func decimal(v float64, prec int) string {
+	s := strconv.FormatFloat(v, 'f', prec, 64)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
@@ -0,0 +1,127 @@
+// Package problem рендерит ошибки в формате RFC 9457 (application/problem+json).
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	domainerr "restapi/internal/domain/errors"
+	log "restapi/internal/logger"
+	"restapi/internal/transport/http/clientip"
+	"restapi/internal/transport/http/requestid"
+)
+
+const ContentType = "application/problem+json"
+
+// typeBase — префикс URI типов проблем. Типы не разыменовываются, это идентификаторы.
+const typeBase = "urn:school-api:problem:"
+
+// Problem — тело ответа по RFC 9457 с расширениями errors, conflict и request_id.
+type Problem struct {
+	Type      string                 `json:"type"`
+	Title     string                 `json:"title"`
+	Status    int                    `json:"status"`
+	Detail    string                 `json:"detail,omitempty"`
+	Instance  string                 `json:"instance,omitempty"`
+	Errors    []domainerr.FieldError `json:"errors,omitempty"`
+	Conflict  any                    `json:"conflict,omitempty"`
+	RequestID string                 `json:"request_id,omitempty"`
+}
+
+// kind — соответствие доменной ошибки типу проблемы, статусу и detail по умолчанию.
+type kind struct {
+	err    error
+	slug   string
+	status int
+	detail string
+}
+
+// Порядок важен: ErrSessionExpired/ErrInvalidAuth проверяются раньше общего ErrUnauthorized.
+var kinds = []kind{
+	{domainerr.ErrNotFound, "not-found", http.StatusNotFound, "resource not found"},
+	{domainerr.ErrConflict, "conflict", http.StatusConflict, "request conflicts with existing data"},
+	{domainerr.ErrBadInput, "validation", http.StatusBadRequest, "invalid request"},
+	{domainerr.ErrSessionExpired, "session-expired", http.StatusUnauthorized, "session expired"},
+	{domainerr.ErrInvalidAuth, "invalid-credentials", http.StatusUnauthorized, "invalid credentials"},
+	{domainerr.ErrUnauthorized, "unauthorized", http.StatusUnauthorized, "authentication required"},
+	{domainerr.ErrForbidden, "forbidden", http.StatusForbidden, "access denied"},
+}
+
+// FromError строит Problem по ошибке. Текст ошибки клиенту не уходит: в нём префиксы операций и
+// имена ограничений БД. В detail попадает сообщение, заданное сервисом (PublicError, ConflictError),
+// иначе — общее для вида ошибки; полная цепочка пишется в лог. Неизвестные ошибки — 500 без деталей.
+func FromError(r *http.Request, err error) Problem {
+	for _, k := range kinds {
+		if !errors.Is(err, k.err) {
+			continue
+		}
+
+		log.Debug("request rejected",
+			"method", r.Method, "path", r.URL.Path, "request_id", requestid.FromContext(r.Context()), "err", err)
+
+		p := New(r, k.status, k.detail)
+		p.Type = typeBase + k.slug
+
+		var perr *domainerr.PublicError
+		if errors.As(err, &perr) {
+			p.Detail = perr.Message
+		}
+
+		var verr *domainerr.ValidationError
+		if errors.As(err, &verr) {
+			p.Detail = "request validation failed"
+			p.Errors = verr.Fields
+		}
+		var cerr *domainerr.ConflictError
+		if errors.As(err, &cerr) {
+			p.Detail = cerr.Message
+			p.Conflict = cerr.With
+		}
+		return p
+	}
+
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return New(r, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body is larger than %d bytes", maxErr.Limit))
+	}
+
+	log.Error("request failed",
+		"method", r.Method, "path", r.URL.Path, "request_id", requestid.FromContext(r.Context()),
+		"client_ip", clientip.FromContext(r.Context()), "err", err)
+	return New(r, http.StatusInternalServerError, "")
+}
+
+// New строит Problem с типом about:blank (RFC 9457 §4.2.1): title равен тексту статуса.
+func New(r *http.Request, status int, detail string) Problem {
+	return Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  r.URL.Path,
+		RequestID: requestid.FromContext(r.Context()),
+	}
+}
+
+// Write отвечает problem+json по доменной ошибке.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	Render(w, FromError(r, err))
+}
+
+// WriteStatus отвечает problem+json с произвольным статусом (для middleware: 429, CORS и т.п.).
+func WriteStatus(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	Render(w, New(r, status, detail))
+}
+
+func Render(w http.ResponseWriter, p Problem) {
+	h := w.Header()
+	h.Set("Content-Type", ContentType)
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		log.Error("write problem", "err", err)
+	}
+}
@@ -0,0 +1,112 @@
+package problem
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domainerr "restapi/internal/domain/errors"
+)
+
+func TestFromError(t *testing.T) {
+	var verr domainerr.ValidationError
+	verr.Add("email", "is required")
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantType   string
+		wantDetail string
+	}{
+		{
+			name:       "constraint name from the repository stays in the log",
+			err:        fmt.Errorf("create student: %w", fmt.Errorf("insert student: %w: %s", domainerr.ErrConflict, "students_email_key")),
+			wantStatus: http.StatusConflict,
+			wantType:   typeBase + "conflict",
+			wantDetail: "request conflicts with existing data",
+		},
+		{
+			name:       "bare sentinel",
+			err:        fmt.Errorf("get class: %w", domainerr.ErrNotFound),
+			wantStatus: http.StatusNotFound,
+			wantType:   typeBase + "not-found",
+			wantDetail: "resource not found",
+		},
+		{
+			name:       "public message under op prefixes",
+			err:        fmt.Errorf("save submission: %w", domainerr.Newf(domainerr.ErrConflict, "submission is already graded")),
+			wantStatus: http.StatusConflict,
+			wantType:   typeBase + "conflict",
+			wantDetail: "submission is already graded",
+		},
+		{
+			name:       "public forbidden",
+			err:        domainerr.Newf(domainerr.ErrForbidden, "term %d is closed", 2),
+			wantStatus: http.StatusForbidden,
+			wantType:   typeBase + "forbidden",
+			wantDetail: "term 2 is closed",
+		},
+		{
+			name:       "conflict with record",
+			err:        fmt.Errorf("create slot: %w", &domainerr.ConflictError{Message: "teacher is busy", With: 7}),
+			wantStatus: http.StatusConflict,
+			wantType:   typeBase + "conflict",
+			wantDetail: "teacher is busy",
+		},
+		{
+			name:       "validation",
+			err:        fmt.Errorf("create exec: %w", verr.Err()),
+			wantStatus: http.StatusBadRequest,
+			wantType:   typeBase + "validation",
+			wantDetail: "request validation failed",
+		},
+		{
+			name:       "session expired before unauthorized",
+			err:        fmt.Errorf("auth: %w", domainerr.ErrSessionExpired),
+			wantStatus: http.StatusUnauthorized,
+			wantType:   typeBase + "session-expired",
+			wantDetail: "session expired",
+		},
+		{
+			name:       "body too large",
+			err:        fmt.Errorf("parse upload: %w", &http.MaxBytesError{Limit: 1024}),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantType:   "about:blank",
+			wantDetail: "request body is larger than 1024 bytes",
+		},
+		{
+			name:       "unknown error",
+			err:        fmt.Errorf("list students: %w", errors.New("pq: connection refused to 10.0.0.5")),
+			wantStatus: http.StatusInternalServerError,
+			wantType:   "about:blank",
+			wantDetail: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/students", nil)
+			p := FromError(r, tt.err)
+			if p.Status != tt.wantStatus || p.Type != tt.wantType || p.Detail != tt.wantDetail {
+				t.Errorf("got %d %s %q, want %d %s %q", p.Status, p.Type, p.Detail, tt.wantStatus, tt.wantType, tt.wantDetail)
+			}
+			if p.Instance != "/students" {
+				t.Errorf("instance = %q", p.Instance)
+			}
+		})
+	}
+}
+
+func TestFromErrorValidationFields(t *testing.T) {
+	var verr domainerr.ValidationError
+	verr.Add("email", "is required")
+	verr.Add("name", "is too long")
+
+	p := FromError(httptest.NewRequest(http.MethodPost, "/", nil), verr.Err())
+	if len(p.Errors) != 2 || p.Errors[0].Field != "email" || p.Errors[1].Field != "name" {
+		t.Errorf("errors = %+v", p.Errors)
+	}
+}
@@ -1,17 +1,422 @@
 package router
 
 import (
+	"crypto/rand"
+	"errors"
+	"fmt"
 	"net/http"
+
+	"restapi/internal/config"
+	"restapi/internal/domain"
+	"restapi/internal/infrastructure/postgres"
+	"restapi/internal/infrastructure/storage"
+	log "restapi/internal/logger"
+	"restapi/internal/service"
 	"restapi/internal/transport/http/handlers"
+	"restapi/internal/transport/http/middlewares"
+	"restapi/internal/transport/http/pdf"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/time/rate"
 )
 
-func NewRouter() http.Handler {
+// NewRouter собирает маршруты. closers останавливают фоновые задачи сервисов (генератор расписания),
+// их вызывает Server.Shutdown. authSvc создаёт приложение: тот же сервис чистит истёкшие сессии.
+// limitStore хранит счётчики политик лимитов маршрутов.
+func NewRouter(cfg *config.Config, pgPool *pgxpool.Pool, authSvc *service.AuthService, limitStore middlewares.LimitStore) (_ http.Handler, closers []func(), err error) {
+	if authSvc == nil {
+		return nil, nil, errors.New("auth service is nil")
+	}
 	mux := http.NewServeMux()
 
-	mux.HandleFunc("/", handlers.RootHandler)
-	mux.HandleFunc("/teachers/", handlers.TeachersHandler)
-	mux.HandleFunc("/students", handlers.StudentsHandler)
-	mux.HandleFunc("/execs", handlers.ExecsHandler)
+	execRepo := postgres.NewExecRepo(pgPool)
+	sessionRepo := postgres.NewSessionRepo(pgPool)
+
+	auth, err := middlewares.NewAuth(authSvc, cfg.Auth.CookieName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	teacherRepo := postgres.NewTeacherRepo(pgPool)
+	classRepo := postgres.NewClassRepo(pgPool)
+	subjectRepo := postgres.NewSubjectRepo(pgPool)
+	roomRepo := postgres.NewRoomRepo(pgPool)
+
+	studentRepo := postgres.NewStudentRepo(pgPool)
+	assignmentRepo := postgres.NewAssignmentRepo(pgPool)
+
+	fileStorage, signedFiles, err := newFileStorage(cfg.Storage, cfg.School.PublicURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	documents := service.UploadPolicy{MaxSize: cfg.Storage.MaxFileSize, Types: cfg.Storage.DocumentTypes}
+	photos := service.UploadPolicy{MaxSize: cfg.Storage.MaxPhotoSize, Types: cfg.Storage.PhotoTypes}
+	maxUpload := cfg.Storage.MaxRequestSize
+
+	studentSvc := service.NewStudentService(studentRepo, classRepo, fileStorage, photos)
+	classSvc := service.NewClassService(classRepo, assignmentRepo, teacherRepo, subjectRepo)
+	termRepo := postgres.NewTermRepo(pgPool)
+	gradeRepo := postgres.NewGradeRepo(pgPool)
+	attendanceRepo := postgres.NewAttendanceRepo(pgPool)
+	gradeSvc := service.NewGradeService(gradeRepo, classRepo, studentRepo, assignmentRepo, termRepo)
+	attendanceSvc := service.NewAttendanceService(attendanceRepo, classRepo, studentRepo, subjectRepo, termRepo, fileStorage, documents)
+	timetableRepo := postgres.NewTimetableRepo(pgPool)
+	timetableSvc := service.NewTimetableService(timetableRepo, classRepo, assignmentRepo, teacherRepo, roomRepo, studentRepo)
+	guardianRepo := postgres.NewGuardianRepo(pgPool)
+	guardianSvc := service.NewGuardianService(guardianRepo, studentRepo, cfg.Auth.GuardianInviteTTL)
+
+	teachers := handlers.NewTeachersHandler(service.NewTeacherService(teacherRepo))
+	students := handlers.NewStudentsHandler(studentSvc, maxUpload)
+	classes := handlers.NewClassesHandler(classSvc, studentSvc)
+	subjects := handlers.NewSubjectsHandler(service.NewSubjectService(subjectRepo))
+	grades := handlers.NewGradesHandler(gradeSvc)
+	attendance := handlers.NewAttendanceHandler(attendanceSvc, maxUpload)
+	rooms := handlers.NewRoomsHandler(service.NewRoomService(roomRepo))
+	timetable := handlers.NewTimetableHandler(timetableSvc)
+	guardians := handlers.NewGuardiansHandler(guardianSvc)
+	terms := handlers.NewTermsHandler(service.NewTermService(termRepo))
+	branding, err := newBranding(cfg.School)
+	if err != nil {
+		return nil, nil, err
+	}
+	reportCards := handlers.NewReportCardsHandler(service.NewReportCardService(postgres.NewReportCardRepo(pgPool),
+		gradeRepo, attendanceRepo, studentRepo, classRepo, assignmentRepo, teacherRepo, termRepo), branding)
+	scaleRepo := postgres.NewGradingScaleRepo(pgPool)
+	gradingScales := handlers.NewGradingScalesHandler(service.NewGradingScaleService(scaleRepo))
+	transcripts := handlers.NewTranscriptsHandler(service.NewTranscriptService(postgres.NewTranscriptRepo(pgPool), scaleRepo, studentRepo, termRepo), branding)
+	promotions := handlers.NewPromotionsHandler(service.NewPromotionService(postgres.NewPromotionRepo(pgPool), classRepo, studentRepo))
+
+	loc, err := cfg.Calendar.Location()
+	if err != nil {
+		return nil, nil, err
+	}
+	firstLesson, err := cfg.Calendar.FirstLessonOffset()
+	if err != nil {
+		return nil, nil, err
+	}
+	bells := domain.BellSchedule{FirstLesson: firstLesson, Lesson: cfg.Calendar.LessonDuration, Break: cfg.Calendar.BreakDuration}
+	holidayRepo := postgres.NewHolidayRepo(pgPool)
+	calendarSvc := service.NewCalendarService(timetableRepo, holidayRepo, classRepo, studentRepo, teacherRepo, bells, loc)
+	calendar := handlers.NewCalendarHandler(calendarSvc, authSvc, guardianSvc)
+	homework := handlers.NewHomeworkHandler(service.NewHomeworkService(postgres.NewHomeworkRepo(pgPool), gradeRepo, classRepo,
+		studentRepo, assignmentRepo, guardianRepo, termRepo, fileStorage, documents, loc), maxUpload)
+	holidays := handlers.NewHolidaysHandler(service.NewHolidayService(holidayRepo))
+
+	generator := service.NewTimetableGenerator(postgres.NewTimetableDraftRepo(pgPool), timetableRepo, classRepo, assignmentRepo, roomRepo)
+	closers = append(closers, generator.Close)
+	drafts := handlers.NewTimetableDraftsHandler(generator)
+	limits, err := newRateLimits(cfg.RateLimit, limitStore)
+	if err != nil {
+		return nil, nil, err
+	}
+	execs := handlers.NewExecsHandler(service.NewExecService(execRepo, sessionRepo), authSvc, cfg.Auth, limits.loginUser)
+
+	// Стеки групп маршрутов поверх глобальной цепочки (её собирает httptransport.NewServer).
+	// С сессией действуют квоты на учётку: отдельно на чтение и на изменения.
+	public := middlewares.NewStack()
+	perExec := middlewares.ForMethods(limits.read.Middleware, limits.write.Middleware)
+	authed := public.Append(auth.Middleware, perExec)
+	feed := public.Append(auth.FeedMiddleware, perExec)
+
+	// handle регистрирует маршрут, доступный только с сессией и при выполнении хотя бы одного правила.
+	// Политики доступа объявляются здесь, рядом с маршрутами, а не в хендлерах.
+	handle := func(pattern string, h http.HandlerFunc, rules ...middlewares.Rule) {
+		mux.Handle(pattern, authed.Append(middlewares.Authorize(rules...)).ThenFunc(h))
+	}
+	// handleFeed — то же для iCal-лент: календарные клиенты не умеют cookie/Bearer и передают ?token=.
+	handleFeed := func(pattern string, h http.HandlerFunc, rules ...middlewares.Rule) {
+		mux.Handle(pattern, feed.Append(middlewares.Authorize(rules...)).ThenFunc(h))
+	}
+
+	var (
+		anyone      = middlewares.AllowAuthenticated()
+		staff       = middlewares.AllowRoles(domain.RolePrincipal, domain.RoleRegistrar)
+		principal   = middlewares.AllowRoles(domain.RolePrincipal)
+		teacher     = middlewares.AllowRoles(domain.RoleTeacher)
+		student     = middlewares.AllowRoles(domain.RoleStudent)
+		ownTeacher  = middlewares.AllowOwnTeacher("id")
+		ownStudent  = middlewares.AllowOwnStudent("id")
+		ownClass    = middlewares.AllowClassTeacher("id", classSvc)
+		myStudent   = middlewares.AllowStudentTeacher("id", classSvc)
+		ownWard     = middlewares.AllowGuardianOf("id", guardianSvc)
+		ownGuardian = middlewares.AllowOwnGuardian("id")
+	)
+
+	mux.Handle("/", public.ThenFunc(handlers.NotFoundHandler))
+	mux.Handle("/{$}", public.ThenFunc(handlers.RootHandler))
+	// Локальное хранилище отдаёт файлы само; подпись ссылки заменяет сессию.
+	if signedFiles != nil {
+		mux.Handle("GET "+storage.LocalPathPrefix+"{key...}", public.ThenFunc(handlers.NewFilesHandler(signedFiles).Download))
+	}
+
+	handle("GET /teachers", teachers.List, anyone)
+	handle("GET /teachers/{$}", teachers.List, anyone)
+	handle("POST /teachers", teachers.Create, principal)
+	handle("POST /teachers/{$}", teachers.Create, principal)
+	handle("GET /teachers/{id}", teachers.Get, anyone)
+	handle("PUT /teachers/{id}", teachers.Update, principal)
+	handle("PATCH /teachers/{id}", teachers.Patch, principal, ownTeacher)
+	handle("DELETE /teachers/{id}", teachers.Delete, principal)
+	handle("GET /teachers/{id}/assignments", classes.TeacherLoad, staff, ownTeacher)
+	handle("GET /teachers/{id}/timetable", timetable.TeacherTimetable, anyone)
+	handle("GET /teachers/{id}/availability", timetable.Availability, staff, ownTeacher)
+	handle("PUT /teachers/{id}/availability", timetable.SetAvailability, staff)
+	handleFeed("GET /teachers/{id}/calendar.ics", calendar.TeacherCalendar, anyone)
+
+	handle("GET /students", students.List, staff, teacher)
+	handle("GET /students/{$}", students.List, staff, teacher)
+	handle("POST /students", students.Create, staff)
+	handle("POST /students/{$}", students.Create, staff)
+	handle("GET /students/{id}", students.Get, staff, teacher, ownStudent, ownWard)
+	handle("PUT /students/{id}", students.Update, staff)
+	handle("PATCH /students/{id}", students.Patch, staff)
+	handle("POST /students/{id}/status", students.Transition, staff)
+	handle("DELETE /students/{id}", students.Delete, principal)
+	handle("GET /students/{id}/photo", students.Photo, staff, teacher, ownStudent, ownWard)
+	handle("PUT /students/{id}/photo", students.SetPhoto, staff)
+	handle("DELETE /students/{id}/photo", students.DeletePhoto, staff)
+	handle("GET /students/{id}/grades", grades.StudentGrades, staff, ownStudent, myStudent, ownWard)
+	handle("GET /students/{id}/attendance", attendance.StudentAttendance, staff, ownStudent, myStudent, ownWard)
+	handle("GET /students/{id}/excuses", attendance.Excuses, staff, ownStudent, myStudent, ownWard)
+	handle("POST /students/{id}/excuses", attendance.SubmitExcuse, staff, ownWard)
+	handle("POST /students/{id}/excuses/{excuseId}/files", attendance.AddExcuseFiles, staff, ownWard)
+	handle("GET /students/{id}/excuses/{excuseId}/files/{fileId}", attendance.ExcuseFile, staff, ownStudent, myStudent, ownWard)
+	handle("GET /students/{id}/report-cards/{term}", reportCards.StudentCard, staff, ownStudent, myStudent, ownWard)
+	handle("PUT /students/{id}/report-cards/{term}/comment", reportCards.SaveComment, staff, myStudent)
+	handle("DELETE /students/{id}/report-cards/{term}/comment", reportCards.DeleteComment, staff, myStudent)
+	handle("GET /students/{id}/transcript", transcripts.Preview, staff, ownStudent, myStudent, ownWard)
+	handle("GET /students/{id}/transcripts", transcripts.Issued, staff, ownStudent, ownWard)
+	handle("POST /students/{id}/transcripts", transcripts.Issue, staff)
+	handle("GET /students/{id}/transcripts/{hash}", transcripts.Get, staff, ownStudent, ownWard)
+	mux.Handle("GET /transcripts/verify/{hash}", public.ThenFunc(transcripts.Verify))
+	handle("GET /students/{id}/homework", homework.StudentHomework, staff, ownStudent, myStudent, ownWard)
+	handle("GET /students/{id}/timetable", timetable.StudentTimetable, staff, ownStudent, myStudent, ownWard)
+	handleFeed("GET /students/{id}/calendar.ics", calendar.StudentCalendar, staff, ownStudent, myStudent, ownWard)
+	handle("GET /students/{id}/guardians", guardians.StudentGuardians, staff, myStudent)
+	handle("PUT /students/{id}/guardians/{guardianId}", guardians.Link, staff)
+	handle("DELETE /students/{id}/guardians/{guardianId}", guardians.Unlink, staff)
+
+	// Представители (родители): карточки ведёт администрация, учётку представитель заводит сам по коду приглашения.
+	handle("GET /guardians", guardians.List, staff)
+	handle("GET /guardians/{$}", guardians.List, staff)
+	handle("POST /guardians", guardians.Create, staff)
+	handle("POST /guardians/{$}", guardians.Create, staff)
+	handle("GET /guardians/{id}", guardians.Get, staff, ownGuardian)
+	handle("PUT /guardians/{id}", guardians.Update, staff)
+	handle("DELETE /guardians/{id}", guardians.Delete, staff)
+	handle("GET /guardians/{id}/students", guardians.Wards, staff, ownGuardian)
+	handle("POST /guardians/{id}/invites", guardians.Invite, staff)
+	mux.Handle("POST /guardian-invites/redeem", public.ThenFunc(guardians.Redeem))
+
+	handle("GET /classes", classes.List, anyone)
+	handle("GET /classes/{$}", classes.List, anyone)
+	handle("POST /classes", classes.Create, staff)
+	handle("POST /classes/{$}", classes.Create, staff)
+	handle("GET /classes/{id}", classes.Get, anyone)
+	handle("PUT /classes/{id}", classes.Update, staff)
+	handle("DELETE /classes/{id}", classes.Delete, principal)
+	handle("GET /classes/{id}/students", classes.Students, staff, ownClass)
+	handle("GET /classes/{id}/subjects", classes.Subjects, anyone)
+	handle("POST /classes/{id}/subjects", classes.Assign, principal)
+	handle("PUT /classes/{id}/subjects/{subjectId}", classes.Reassign, principal)
+	handle("DELETE /classes/{id}/subjects/{subjectId}", classes.Unassign, principal)
+
+	// Учитель допускается к журналу своих классов; право на конкретный предмет проверяет GradeService.
+	handle("GET /classes/{id}/grades", grades.ClassGrades, staff, ownClass)
+	handle("POST /classes/{id}/grades", grades.RecordBatch, principal, ownClass)
+	handle("GET /classes/{id}/assessments", grades.Assessments, staff, ownClass)
+	handle("GET /classes/{id}/rankings", grades.Rankings, staff, ownClass)
+	handle("GET /classes/{id}/report-cards/{term}", reportCards.ClassCards, staff, ownClass)
+	handle("DELETE /assessments/{id}", grades.DeleteAssessment, principal, teacher)
+
+	// Домашние задания: публикует и проверяет учитель предмета (право проверяет HomeworkService),
+	// сдаёт ученик класса. Оценка за сдачу попадает в журнал работой задания.
+	handle("GET /classes/{id}/homework", homework.ClassHomework, staff, ownClass)
+	handle("POST /classes/{id}/homework", homework.Create, principal, ownClass)
+	handle("GET /homework/{id}", homework.Get, anyone)
+	handle("PUT /homework/{id}", homework.Update, principal, teacher)
+	handle("DELETE /homework/{id}", homework.Delete, principal, teacher)
+	handle("POST /homework/{id}/attachments", homework.AddAttachments, principal, teacher)
+	handle("GET /homework/{id}/attachments/{fileId}", homework.Attachment, anyone)
+	handle("DELETE /homework/{id}/attachments/{fileId}", homework.DeleteAttachment, principal, teacher)
+	handle("GET /homework/{id}/submissions", homework.Submissions, staff, teacher)
+	handle("GET /homework/{id}/submission", homework.MySubmission, student)
+	handle("PUT /homework/{id}/submission", homework.Submit, student)
+	handle("GET /submissions/{id}", homework.Submission, anyone)
+	handle("GET /submissions/{id}/files/{fileId}", homework.SubmissionFile, anyone)
+	handle("DELETE /submissions/{id}/files/{fileId}", homework.DeleteSubmissionFile, student)
+	handle("POST /submissions/{id}/grade", homework.Grade, principal, teacher)
+
+	handle("GET /grade-categories", grades.Categories, anyone)
+	handle("POST /grade-categories", grades.CreateCategory, principal)
+	handle("PUT /grade-categories/{id}", grades.UpdateCategory, principal)
+	handle("GET /grading-scales", gradingScales.List, anyone)
+	handle("GET /grading-scales/{$}", gradingScales.List, anyone)
+	handle("POST /grading-scales", gradingScales.Create, principal)
+	handle("POST /grading-scales/{$}", gradingScales.Create, principal)
+	handle("GET /grading-scales/convert", gradingScales.Convert, anyone)
+	handle("GET /grading-scales/{id}", gradingScales.Get, anyone)
+	handle("PUT /grading-scales/{id}", gradingScales.Update, principal)
+	handle("DELETE /grading-scales/{id}", gradingScales.Delete, principal)
+
+	handle("GET /classes/{id}/attendance", attendance.ClassDay, staff, ownClass)
+	handle("POST /classes/{id}/attendance", attendance.RecordRollCall, staff, ownClass)
+	handle("GET /classes/{id}/attendance/summary", attendance.ClassSummary, staff, ownClass)
+	handle("POST /excuses/{id}/review", attendance.ReviewExcuse, staff)
+	handle("GET /absence-reasons", attendance.Reasons, anyone)
+
+	// Расписание открыто всем вошедшим; составляет его администрация.
+	handle("GET /classes/{id}/timetable", timetable.ClassTimetable, anyone)
+	handleFeed("GET /classes/{id}/calendar.ics", calendar.ClassCalendar, anyone)
+	handle("POST /timetable/slots", timetable.Create, staff)
+	handle("GET /timetable/slots/{id}", timetable.Get, anyone)
+	handle("PUT /timetable/slots/{id}", timetable.Update, staff)
+	handle("DELETE /timetable/slots/{id}", timetable.Delete, staff)
+	handle("POST /timetables/generate", drafts.Generate, staff)
+	handle("GET /timetables/drafts", drafts.List, staff)
+	handle("GET /timetables/drafts/{$}", drafts.List, staff)
+	handle("GET /timetables/drafts/{id}", drafts.Get, staff)
+	handle("GET /timetables/drafts/{id}/slots", drafts.Slots, staff)
+	handle("POST /timetables/drafts/{id}/publish", drafts.Publish, staff)
+	handle("DELETE /timetables/drafts/{id}", drafts.Delete, staff)
+
+	// Учебный календарь. Закрытый период замораживает оценки; открыть его снова может администрация.
+	handle("GET /academic-years", terms.Years, anyone)
+	handle("GET /academic-years/{$}", terms.Years, anyone)
+	handle("POST /academic-years", terms.CreateYear, staff)
+	handle("POST /academic-years/{$}", terms.CreateYear, staff)
+	handle("GET /academic-years/{year}", terms.Year, anyone)
+	handle("PUT /academic-years/{year}", terms.UpdateYear, staff)
+	handle("DELETE /academic-years/{year}", terms.DeleteYear, staff)
+	handle("GET /academic-years/{year}/terms", terms.Terms, anyone)
+	handle("POST /terms", terms.Create, staff)
+	handle("POST /terms/{$}", terms.Create, staff)
+	handle("GET /terms/current", terms.Current, anyone)
+	handle("GET /terms/{id}", terms.Get, anyone)
+	handle("PUT /terms/{id}", terms.Update, staff)
+	handle("DELETE /terms/{id}", terms.Delete, staff)
+	handle("POST /terms/{id}/close", terms.Close, staff)
+	handle("POST /terms/{id}/reopen", terms.Reopen, staff)
+
+	handle("GET /promotions", promotions.List, staff)
+	handle("GET /promotions/{$}", promotions.List, staff)
+	handle("POST /promotions", promotions.Promote, staff)
+	handle("POST /promotions/{$}", promotions.Promote, staff)
+	handle("GET /promotions/{id}", promotions.Get, staff)
+
+	handle("GET /holidays", holidays.List, anyone)
+	handle("GET /holidays/{$}", holidays.List, anyone)
+	handle("POST /holidays", holidays.Create, staff)
+	handle("POST /holidays/{$}", holidays.Create, staff)
+	handle("GET /holidays/{id}", holidays.Get, anyone)
+	handle("PUT /holidays/{id}", holidays.Update, staff)
+	handle("DELETE /holidays/{id}", holidays.Delete, staff)
+
+	handle("GET /rooms", rooms.List, anyone)
+	handle("GET /rooms/{$}", rooms.List, anyone)
+	handle("POST /rooms", rooms.Create, staff)
+	handle("POST /rooms/{$}", rooms.Create, staff)
+	handle("GET /rooms/{id}", rooms.Get, anyone)
+	handle("PUT /rooms/{id}", rooms.Update, staff)
+	handle("DELETE /rooms/{id}", rooms.Delete, staff)
+	handle("GET /rooms/{id}/timetable", timetable.RoomTimetable, anyone)
+
+	handle("GET /subjects", subjects.List, anyone)
+	handle("GET /subjects/{$}", subjects.List, anyone)
+	handle("POST /subjects", subjects.Create, principal)
+	handle("POST /subjects/{$}", subjects.Create, principal)
+	handle("GET /subjects/{id}", subjects.Get, anyone)
+	handle("PUT /subjects/{id}", subjects.Update, principal)
+	handle("DELETE /subjects/{id}", subjects.Delete, principal)
+
+	// Управление учётками — только superadmin (проходит Authorize всегда); директор может смотреть.
+	mux.Handle("POST /execs/login", public.Append(limits.loginIP.Middleware).ThenFunc(execs.Login))
+	mux.Handle("POST /execs/logout", public.ThenFunc(execs.Logout))
+	handle("GET /execs/me", execs.Me, anyone)
+	handle("POST /execs/me/calendar-token", calendar.IssueToken, anyone)
+	handle("DELETE /execs/me/calendar-token", calendar.RevokeToken, anyone)
+	handle("GET /execs", execs.List, principal)
+	handle("GET /execs/{$}", execs.List, principal)
+	handle("POST /execs", execs.Create)
+	handle("POST /execs/{$}", execs.Create)
+	handle("GET /execs/{id}", execs.Get, principal)
+	handle("PATCH /execs/{id}", execs.Patch)
+	handle("DELETE /execs/{id}", execs.Delete)
+
+	return mux, closers, nil
+}
+
+// newFileStorage создаёт хранилище файлов по STORAGE_BACKEND. signed — локальное хранилище, файлы
+// которого раздаёт сам API; для S3 nil: ссылки ведут прямо в бакет.
+func newFileStorage(c config.Storage, publicURL string) (_ service.FileStorage, signed handlers.SignedFiles, err error) {
+	if c.Backend == "s3" {
+		s3, err := storage.NewS3(storage.S3Config{
+			Endpoint: c.S3.Endpoint, Region: c.S3.Region, Bucket: c.S3.Bucket,
+			AccessKey: c.S3.AccessKey, SecretKey: c.S3.SecretKey, PathStyle: c.S3.PathStyle, URLTTL: c.URLTTL,
+		})
+		return s3, nil, err
+	}
+
+	key := []byte(c.SigningKey)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, nil, fmt.Errorf("storage: generate signing key: %w", err)
+		}
+		log.Warn("STORAGE_SIGNING_KEY is not set: using a random key, file links will break on restart")
+	}
+	local, err := storage.NewLocal(storage.LocalConfig{Root: c.LocalDir, SigningKey: key, BaseURL: publicURL, URLTTL: c.URLTTL})
+	if err != nil {
+		return nil, nil, err
+	}
+	return local, local, nil
+}
+
+// rateLimits — политики лимитов маршрутов; общий лимит на IP висит в глобальной цепочке.
+type rateLimits struct {
+	read      *middlewares.RateLimiter // чтение с сессией — на учётку
+	write     *middlewares.RateLimiter // изменения — на учётку и маршрут
+	loginIP   *middlewares.RateLimiter // попытки входа с одного IP
+	loginUser *middlewares.RateLimiter // попытки входа в одну учётку с любых IP (проверяет хендлер)
+}
+
+func newRateLimits(c config.RateLimit, store middlewares.LimitStore) (rateLimits, error) {
+	var (
+		l   rateLimits
+		err error
+	)
+	newLimiter := func(name string, rps float64, burst int, key middlewares.Keyer) *middlewares.RateLimiter {
+		if err != nil {
+			return nil
+		}
+		var rl *middlewares.RateLimiter
+		rl, err = middlewares.NewRateLimiter(store, middlewares.Policy{
+			Name: name, Limit: middlewares.Limit{Rate: rate.Limit(rps), Burst: burst}, Key: key,
+		}, c.FailOpen)
+		return rl
+	}
+
+	l.read = newLimiter("read", c.ReadRPS, c.ReadBurst, middlewares.KeyByPrincipal)
+	l.write = newLimiter("write", c.WriteRPS, c.WriteBurst, middlewares.CombineKeys(middlewares.KeyByPrincipal, middlewares.KeyByRoute))
+	l.loginIP = newLimiter("login-ip", c.LoginRPS, c.LoginBurst, middlewares.KeyByClientIP)
+	l.loginUser = newLimiter("login-user", c.LoginRPS, c.LoginBurst, nil)
+	return l, err
+}
 
-	return mux
+// newBranding — оформление печатных документов из конфигурации; логотип проверяется при старте.
+func newBranding(c config.School) (pdf.Branding, error) {
+	accent, err := pdf.ParseColor(c.AccentColor)
+	if err != nil {
+		return pdf.Branding{}, err
+	}
+	logo, err := c.Logo()
+	if err != nil {
+		return pdf.Branding{}, err
+	}
+	if logo != nil {
+		if err := pdf.ValidJPEG(logo); err != nil {
+			return pdf.Branding{}, fmt.Errorf("SCHOOL_LOGO_PATH: %w", err)
+		}
+	}
+	return pdf.Branding{SchoolName: c.Name, Address: c.Address, Principal: c.Principal, Accent: accent, Logo: logo, PublicURL: c.PublicURL}, nil
 }
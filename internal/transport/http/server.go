@@ -2,30 +2,56 @@ package http
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"restapi/internal/config"
 	log "restapi/internal/logger"
+	"restapi/internal/service"
 	"restapi/internal/transport/http/router"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	goredis "github.com/redis/go-redis/v9"
 )
 
 type Server struct {
-	srv *http.Server
+	srv     *http.Server
+	closers []func()
 }
 
-func NewServer(cfg *config.Config) *Server {
-	handler := router.NewRouter()
+// NewServer собирает сервер. rdb нужен при RATE_LIMIT_BACKEND=redis, иначе nil.
+// Хранилище лимитов одно на глобальную цепочку и политики маршрутов.
+func NewServer(cfg *config.Config, pgPool *pgxpool.Pool, rdb *goredis.Client, authSvc *service.AuthService) (*Server, error) {
+	limits, closers, err := newLimitStore(cfg, rdb)
+	if err != nil {
+		return nil, fmt.Errorf("rate limit store: %w", err)
+	}
+
+	routes, routeClosers, err := router.NewRouter(cfg, pgPool, authSvc, limits)
+	if err != nil {
+		runClosers(closers)
+		return nil, err
+	}
+	closers = append(closers, routeClosers...)
+
+	global, err := buildGlobalStack(cfg, limits)
+	if err != nil {
+		runClosers(closers)
+		return nil, err
+	}
+
 	h := cfg.App.HTTP
 
 	return &Server{
 		srv: &http.Server{
 			Addr:              h.Addr,
-			Handler:           handler,
+			Handler:           global.Then(routes),
 			ReadHeaderTimeout: h.ReadHeaderTimeout,
 			ReadTimeout:       h.ReadTimeout,
 			WriteTimeout:      h.WriteTimeout,
 			IdleTimeout:       h.IdleTimeout,
 		},
-	}
+		closers: closers,
+	}, nil
 }
 
 // Run запускает HTTP-сервер (блокирующий вызов). При Shutdown возвращает http.ErrServerClosed.
@@ -34,7 +60,11 @@ func (s *Server) Run() error {
 	return s.srv.ListenAndServe()
 }
 
-// Shutdown останавливает сервер с учётом таймаута. Дожидается завершения активных запросов.
+// Shutdown останавливает сервер с учётом таймаута. Дожидается завершения активных запросов,
+// затем останавливает фоновые горутины middleware и сервисов (даже если Shutdown вернул ошибку).
 func (s *Server) Shutdown(ctx context.Context) error {
-	return s.srv.Shutdown(ctx)
+	err := s.srv.Shutdown(ctx)
+	runClosers(s.closers)
+	s.closers = nil
+	return err
 }
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS teachers (
+    id          BIGSERIAL PRIMARY KEY,
+    first_name  TEXT        NOT NULL,
+    last_name   TEXT        NOT NULL,
+    email       TEXT        NOT NULL,
+    phone       TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS teachers_email_uniq ON teachers (lower(email));
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS students (
+    id          BIGSERIAL PRIMARY KEY,
+    first_name  TEXT        NOT NULL,
+    last_name   TEXT        NOT NULL,
+    email       TEXT,
+    birth_date  DATE,
+    grade       SMALLINT    NOT NULL CHECK (grade BETWEEN 1 AND 12),
+    class_name  TEXT        NOT NULL,
+    status      TEXT        NOT NULL DEFAULT 'applicant'
+                CHECK (status IN ('applicant', 'enrolled', 'suspended', 'graduated', 'withdrawn')),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS students_email_uniq ON students (lower(email)) WHERE email IS NOT NULL;
+CREATE INDEX IF NOT EXISTS students_class_idx ON students (grade, class_name);
+CREATE INDEX IF NOT EXISTS students_status_idx ON students (status);
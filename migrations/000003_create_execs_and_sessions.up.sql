@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS execs (
+    id             BIGSERIAL PRIMARY KEY,
+    first_name     TEXT        NOT NULL,
+    last_name      TEXT        NOT NULL,
+    email          TEXT        NOT NULL,
+    username       TEXT        NOT NULL,
+    password_hash  TEXT        NOT NULL,
+    active         BOOLEAN     NOT NULL DEFAULT TRUE,
+    last_login_at  TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS execs_username_uniq ON execs (lower(username));
+CREATE UNIQUE INDEX IF NOT EXISTS execs_email_uniq ON execs (lower(email));
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id            BIGSERIAL PRIMARY KEY,
+    token_hash    BYTEA       NOT NULL UNIQUE,
+    exec_id       BIGINT      NOT NULL REFERENCES execs (id) ON DELETE CASCADE,
+    user_agent    TEXT        NOT NULL DEFAULT '',
+    ip            TEXT        NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_seen_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at    TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS sessions_exec_idx ON sessions (exec_id);
+CREATE INDEX IF NOT EXISTS sessions_expires_idx ON sessions (expires_at);
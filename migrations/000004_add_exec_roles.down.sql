@@ -0,0 +1,9 @@
+DELETE FROM execs WHERE role IN ('teacher', 'student', 'guardian');
+
+ALTER TABLE execs
+    DROP CONSTRAINT IF EXISTS execs_student_link_check,
+    DROP CONSTRAINT IF EXISTS execs_teacher_link_check,
+    DROP CONSTRAINT IF EXISTS execs_role_check,
+    DROP COLUMN IF EXISTS student_id,
+    DROP COLUMN IF EXISTS teacher_id,
+    DROP COLUMN IF EXISTS role;
@@ -0,0 +1,16 @@
+ALTER TABLE execs
+    ADD COLUMN role       TEXT   NOT NULL DEFAULT 'principal',
+    ADD COLUMN teacher_id BIGINT REFERENCES teachers (id) ON DELETE CASCADE,
+    ADD COLUMN student_id BIGINT REFERENCES students (id) ON DELETE CASCADE;
+
+-- До RBAC все execs имели полный доступ — сохраняем его.
+UPDATE execs SET role = 'superadmin';
+
+ALTER TABLE execs
+    ADD CONSTRAINT execs_role_check
+        CHECK (role IN ('superadmin', 'principal', 'registrar', 'teacher', 'student', 'guardian')),
+    ADD CONSTRAINT execs_teacher_link_check CHECK ((role = 'teacher') = (teacher_id IS NOT NULL)),
+    ADD CONSTRAINT execs_student_link_check CHECK ((role = 'student') = (student_id IS NOT NULL));
+
+CREATE UNIQUE INDEX IF NOT EXISTS execs_teacher_uniq ON execs (teacher_id) WHERE teacher_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS execs_student_uniq ON execs (student_id) WHERE student_id IS NOT NULL;
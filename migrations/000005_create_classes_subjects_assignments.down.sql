@@ -0,0 +1,15 @@
+ALTER TABLE students ADD COLUMN class_name TEXT NOT NULL DEFAULT '';
+
+UPDATE students s
+SET class_name = c.name
+FROM classes c
+WHERE c.id = s.class_id;
+
+ALTER TABLE students ALTER COLUMN class_name DROP DEFAULT;
+DROP INDEX IF EXISTS students_class_id_idx;
+ALTER TABLE students DROP COLUMN class_id;
+CREATE INDEX IF NOT EXISTS students_class_idx ON students (grade, class_name);
+
+DROP TABLE IF EXISTS class_subject_teacher;
+DROP TABLE IF EXISTS subjects;
+DROP TABLE IF EXISTS classes;
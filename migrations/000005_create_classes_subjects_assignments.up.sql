@@ -0,0 +1,51 @@
+CREATE TABLE IF NOT EXISTS classes (
+    id                   BIGSERIAL PRIMARY KEY,
+    name                 TEXT        NOT NULL,
+    grade                SMALLINT    NOT NULL CHECK (grade BETWEEN 1 AND 12),
+    academic_year        INT         NOT NULL CHECK (academic_year BETWEEN 2000 AND 2100),
+    homeroom_teacher_id  BIGINT      REFERENCES teachers (id) ON DELETE SET NULL,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT classes_year_name_uniq UNIQUE (academic_year, name)
+);
+
+CREATE TABLE IF NOT EXISTS subjects (
+    id          BIGSERIAL PRIMARY KEY,
+    code        TEXT        NOT NULL,
+    name        TEXT        NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS subjects_code_uniq ON subjects (lower(code));
+
+CREATE TABLE IF NOT EXISTS class_subject_teacher (
+    id              BIGSERIAL PRIMARY KEY,
+    class_id        BIGINT      NOT NULL REFERENCES classes (id) ON DELETE CASCADE,
+    subject_id      BIGINT      NOT NULL REFERENCES subjects (id) ON DELETE RESTRICT,
+    teacher_id      BIGINT      NOT NULL REFERENCES teachers (id) ON DELETE RESTRICT,
+    hours_per_week  SMALLINT    NOT NULL DEFAULT 1 CHECK (hours_per_week BETWEEN 1 AND 40),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT class_subject_uniq UNIQUE (class_id, subject_id)
+);
+
+CREATE INDEX IF NOT EXISTS class_subject_teacher_teacher_idx ON class_subject_teacher (teacher_id);
+
+-- Ученики теперь ссылаются на класс. Существующие пары (grade, class_name) переносим
+-- в классы текущего учебного года (учебный год начинается в сентябре).
+ALTER TABLE students ADD COLUMN class_id BIGINT REFERENCES classes (id) ON DELETE SET NULL;
+
+INSERT INTO classes (name, grade, academic_year)
+SELECT DISTINCT class_name, grade, EXTRACT(YEAR FROM now() - INTERVAL '8 months')::INT
+FROM students
+ON CONFLICT (academic_year, name) DO NOTHING;
+
+UPDATE students s
+SET class_id = c.id
+FROM classes c
+WHERE c.name = s.class_name
+  AND c.academic_year = EXTRACT(YEAR FROM now() - INTERVAL '8 months')::INT;
+
+DROP INDEX IF EXISTS students_class_idx;
+ALTER TABLE students DROP COLUMN class_name;
+CREATE INDEX IF NOT EXISTS students_class_id_idx ON students (class_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS grades;
+DROP TABLE IF EXISTS assessments;
+DROP TABLE IF EXISTS grade_categories;
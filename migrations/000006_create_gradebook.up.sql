@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS grade_categories (
+    id      BIGSERIAL PRIMARY KEY,
+    code    TEXT          NOT NULL UNIQUE,
+    name    TEXT          NOT NULL,
+    weight  NUMERIC(5, 2) NOT NULL CHECK (weight > 0)
+);
+
+INSERT INTO grade_categories (code, name, weight) VALUES
+    ('homework', 'Homework', 1),
+    ('quiz',     'Quiz',     2),
+    ('exam',     'Exam',     3)
+ON CONFLICT (code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS assessments (
+    id           BIGSERIAL PRIMARY KEY,
+    class_id     BIGINT        NOT NULL REFERENCES classes (id) ON DELETE CASCADE,
+    subject_id   BIGINT        NOT NULL REFERENCES subjects (id) ON DELETE RESTRICT,
+    category_id  BIGINT        NOT NULL REFERENCES grade_categories (id) ON DELETE RESTRICT,
+    term         SMALLINT      NOT NULL CHECK (term BETWEEN 1 AND 4),
+    title        TEXT          NOT NULL,
+    date         DATE          NOT NULL,
+    max_score    NUMERIC(6, 2) NOT NULL CHECK (max_score > 0),
+    teacher_id   BIGINT        REFERENCES teachers (id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ   NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS assessments_class_term_idx ON assessments (class_id, term, subject_id);
+
+CREATE TABLE IF NOT EXISTS grades (
+    id             BIGSERIAL PRIMARY KEY,
+    assessment_id  BIGINT        NOT NULL REFERENCES assessments (id) ON DELETE CASCADE,
+    student_id     BIGINT        NOT NULL REFERENCES students (id) ON DELETE CASCADE,
+    score          NUMERIC(6, 2) NOT NULL CHECK (score >= 0),
+    comment        TEXT          NOT NULL DEFAULT '',
+    updated_at     TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    CONSTRAINT grades_assessment_student_uniq UNIQUE (assessment_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS grades_student_idx ON grades (student_id);
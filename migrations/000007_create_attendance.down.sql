@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS excuse_notes;
+DROP TABLE IF EXISTS attendance_marks;
+DROP TABLE IF EXISTS roll_calls;
+DROP TABLE IF EXISTS absence_reasons;
@@ -0,0 +1,59 @@
+CREATE TABLE IF NOT EXISTS absence_reasons (
+    id       BIGSERIAL PRIMARY KEY,
+    code     TEXT    NOT NULL UNIQUE,
+    name     TEXT    NOT NULL,
+    excused  BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+INSERT INTO absence_reasons (code, name, excused) VALUES
+    ('illness',    'Illness',               TRUE),
+    ('medical',    'Medical appointment',   TRUE),
+    ('family',     'Family circumstances',  TRUE),
+    ('school',     'School event',          TRUE),
+    ('transport',  'Transport problems',    FALSE),
+    ('unexcused',  'No valid reason',       FALSE)
+ON CONFLICT (code) DO NOTHING;
+
+-- Перекличка: lesson = 0 — отметка за весь день, 1..N — номер урока.
+CREATE TABLE IF NOT EXISTS roll_calls (
+    id          BIGSERIAL PRIMARY KEY,
+    class_id    BIGINT      NOT NULL REFERENCES classes (id) ON DELETE CASCADE,
+    date        DATE        NOT NULL,
+    lesson      SMALLINT    NOT NULL DEFAULT 0 CHECK (lesson BETWEEN 0 AND 12),
+    subject_id  BIGINT      REFERENCES subjects (id) ON DELETE SET NULL,
+    taken_by    BIGINT      REFERENCES execs (id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT roll_calls_class_date_lesson_uniq UNIQUE (class_id, date, lesson)
+);
+
+CREATE TABLE IF NOT EXISTS attendance_marks (
+    id            BIGSERIAL PRIMARY KEY,
+    roll_call_id  BIGINT   NOT NULL REFERENCES roll_calls (id) ON DELETE CASCADE,
+    student_id    BIGINT   NOT NULL REFERENCES students (id) ON DELETE CASCADE,
+    status        TEXT     NOT NULL CHECK (status IN ('present', 'absent', 'late', 'excused')),
+    reason_id     BIGINT   REFERENCES absence_reasons (id) ON DELETE RESTRICT,
+    minutes_late  SMALLINT NOT NULL DEFAULT 0 CHECK (minutes_late >= 0),
+    note          TEXT     NOT NULL DEFAULT '',
+    CONSTRAINT attendance_marks_roll_call_student_uniq UNIQUE (roll_call_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS attendance_marks_student_idx ON attendance_marks (student_id);
+
+-- Объяснительные (записки от родителей) за период; одобрение переводит пропуски в excused.
+CREATE TABLE IF NOT EXISTS excuse_notes (
+    id            BIGSERIAL PRIMARY KEY,
+    student_id    BIGINT      NOT NULL REFERENCES students (id) ON DELETE CASCADE,
+    date_from     DATE        NOT NULL,
+    date_to       DATE        NOT NULL,
+    reason_id     BIGINT      NOT NULL REFERENCES absence_reasons (id) ON DELETE RESTRICT,
+    text          TEXT        NOT NULL DEFAULT '',
+    status        TEXT        NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    submitted_by  BIGINT      REFERENCES execs (id) ON DELETE SET NULL,
+    reviewed_by   BIGINT      REFERENCES execs (id) ON DELETE SET NULL,
+    reviewed_at   TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT excuse_notes_period_check CHECK (date_from <= date_to)
+);
+
+CREATE INDEX IF NOT EXISTS excuse_notes_student_idx ON excuse_notes (student_id, date_from);
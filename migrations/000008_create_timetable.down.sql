@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS timetable_slots;
+DROP TABLE IF EXISTS rooms;
@@ -0,0 +1,41 @@
+-- btree_gist нужен для EXCLUDE-ограничений по (id, день, урок) с пересечением периодов действия.
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE IF NOT EXISTS rooms (
+    id          BIGSERIAL PRIMARY KEY,
+    code        TEXT        NOT NULL,
+    name        TEXT        NOT NULL DEFAULT '',
+    capacity    INT         NOT NULL DEFAULT 0 CHECK (capacity >= 0),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS rooms_code_uniq ON rooms (lower(code));
+
+-- Урок в недельном расписании. effective_to = NULL — действует бессрочно.
+CREATE TABLE IF NOT EXISTS timetable_slots (
+    id              BIGSERIAL PRIMARY KEY,
+    class_id        BIGINT      NOT NULL REFERENCES classes (id) ON DELETE CASCADE,
+    subject_id      BIGINT      NOT NULL REFERENCES subjects (id) ON DELETE RESTRICT,
+    teacher_id      BIGINT      NOT NULL REFERENCES teachers (id) ON DELETE RESTRICT,
+    room_id         BIGINT      REFERENCES rooms (id) ON DELETE RESTRICT,
+    day             SMALLINT    NOT NULL CHECK (day BETWEEN 1 AND 7),
+    period          SMALLINT    NOT NULL CHECK (period BETWEEN 1 AND 12),
+    effective_from  DATE        NOT NULL,
+    effective_to    DATE,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT timetable_slots_period_check CHECK (effective_to IS NULL OR effective_from <= effective_to),
+    CONSTRAINT timetable_slots_class_excl EXCLUDE USING gist (
+        class_id WITH =, day WITH =, period WITH =,
+        daterange(effective_from, effective_to, '[]') WITH &&),
+    CONSTRAINT timetable_slots_teacher_excl EXCLUDE USING gist (
+        teacher_id WITH =, day WITH =, period WITH =,
+        daterange(effective_from, effective_to, '[]') WITH &&),
+    CONSTRAINT timetable_slots_room_excl EXCLUDE USING gist (
+        room_id WITH =, day WITH =, period WITH =,
+        daterange(effective_from, effective_to, '[]') WITH &&)
+);
+
+CREATE INDEX IF NOT EXISTS timetable_slots_teacher_idx ON timetable_slots (teacher_id, day, period);
+CREATE INDEX IF NOT EXISTS timetable_slots_room_idx ON timetable_slots (room_id, day, period);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS timetable_draft_slots;
+DROP TABLE IF EXISTS timetable_drafts;
+DROP TABLE IF EXISTS teacher_unavailability;
@@ -0,0 +1,40 @@
+-- Часы, в которые учитель не может вести уроки (учитывает генератор расписания).
+CREATE TABLE IF NOT EXISTS teacher_unavailability (
+    teacher_id  BIGINT   NOT NULL REFERENCES teachers (id) ON DELETE CASCADE,
+    day         SMALLINT NOT NULL CHECK (day BETWEEN 1 AND 7),
+    period      SMALLINT NOT NULL CHECK (period BETWEEN 1 AND 12),
+    PRIMARY KEY (teacher_id, day, period)
+);
+
+-- Черновик расписания, построенный генератором; после проверки публикуется в timetable_slots.
+CREATE TABLE IF NOT EXISTS timetable_drafts (
+    id              BIGSERIAL PRIMARY KEY,
+    academic_year   INT         NOT NULL,
+    class_ids       BIGINT[]    NOT NULL,
+    effective_from  DATE        NOT NULL,
+    days            SMALLINT    NOT NULL CHECK (days BETWEEN 1 AND 7),
+    periods         SMALLINT    NOT NULL CHECK (periods BETWEEN 1 AND 12),
+    time_limit      INT         NOT NULL CHECK (time_limit > 0), -- секунды
+    status          TEXT        NOT NULL DEFAULT 'queued'
+        CHECK (status IN ('queued', 'running', 'ready', 'failed', 'published')),
+    progress        SMALLINT    NOT NULL DEFAULT 0 CHECK (progress BETWEEN 0 AND 100),
+    message         TEXT        NOT NULL DEFAULT '',
+    created_by      BIGINT      REFERENCES execs (id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    started_at      TIMESTAMPTZ,
+    finished_at     TIMESTAMPTZ,
+    published_at    TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS timetable_draft_slots (
+    id          BIGSERIAL PRIMARY KEY,
+    draft_id    BIGINT   NOT NULL REFERENCES timetable_drafts (id) ON DELETE CASCADE,
+    class_id    BIGINT   NOT NULL REFERENCES classes (id) ON DELETE CASCADE,
+    subject_id  BIGINT   NOT NULL REFERENCES subjects (id) ON DELETE CASCADE,
+    teacher_id  BIGINT   NOT NULL REFERENCES teachers (id) ON DELETE CASCADE,
+    room_id     BIGINT   REFERENCES rooms (id) ON DELETE SET NULL,
+    day         SMALLINT NOT NULL,
+    period      SMALLINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS timetable_draft_slots_draft_idx ON timetable_draft_slots (draft_id, class_id);
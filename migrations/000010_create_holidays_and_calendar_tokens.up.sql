@@ -0,0 +1,20 @@
+-- Каникулы и нерабочие дни школы (включительно по обе даты); уроки в эти дни не проводятся.
+CREATE TABLE IF NOT EXISTS holidays (
+    id          BIGSERIAL PRIMARY KEY,
+    name        TEXT        NOT NULL,
+    start_date  DATE        NOT NULL,
+    end_date    DATE        NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT holidays_period_check CHECK (start_date <= end_date)
+);
+
+CREATE INDEX IF NOT EXISTS holidays_start_idx ON holidays (start_date);
+
+-- Секрет персональной ссылки на календарь: календарные клиенты не умеют слать cookie.
+-- Храним только sha256 токена, как и для сессий.
+CREATE TABLE IF NOT EXISTS calendar_tokens (
+    exec_id     BIGINT      PRIMARY KEY REFERENCES execs (id) ON DELETE CASCADE,
+    token_hash  BYTEA       NOT NULL UNIQUE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
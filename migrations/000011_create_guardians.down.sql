@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS guardian_invites;
+
+DELETE FROM execs WHERE role = 'guardian';
+
+ALTER TABLE execs
+    DROP CONSTRAINT IF EXISTS execs_guardian_link_check,
+    DROP COLUMN IF EXISTS guardian_id;
+
+DROP TABLE IF EXISTS student_guardians;
+DROP TABLE IF EXISTS guardians;
@@ -0,0 +1,50 @@
+-- Родители и законные представители учеников.
+CREATE TABLE IF NOT EXISTS guardians (
+    id          BIGSERIAL PRIMARY KEY,
+    first_name  TEXT        NOT NULL,
+    last_name   TEXT        NOT NULL,
+    email       TEXT,
+    phone       TEXT,
+    address     TEXT        NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT guardians_contact_check CHECK (email IS NOT NULL OR phone IS NOT NULL)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS guardians_email_uniq ON guardians (lower(email)) WHERE email IS NOT NULL;
+
+-- Связь многие-ко-многим: у ученика несколько представителей, у представителя несколько детей.
+CREATE TABLE IF NOT EXISTS student_guardians (
+    student_id    BIGINT      NOT NULL REFERENCES students (id) ON DELETE CASCADE,
+    guardian_id   BIGINT      NOT NULL REFERENCES guardians (id) ON DELETE CASCADE,
+    relationship  TEXT        NOT NULL
+                  CHECK (relationship IN ('mother', 'father', 'stepparent', 'grandparent', 'sibling', 'legal_guardian', 'other')),
+    is_primary    BOOLEAN     NOT NULL DEFAULT false,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (student_id, guardian_id)
+);
+
+CREATE INDEX IF NOT EXISTS student_guardians_guardian_idx ON student_guardians (guardian_id);
+
+-- Учётки с ролью guardian до сих пор ни к кому не были привязаны и доступа к данным детей не имели.
+DELETE FROM execs WHERE role = 'guardian';
+
+ALTER TABLE execs
+    ADD COLUMN guardian_id BIGINT REFERENCES guardians (id) ON DELETE CASCADE,
+    ADD CONSTRAINT execs_guardian_link_check CHECK ((role = 'guardian') = (guardian_id IS NOT NULL));
+
+CREATE UNIQUE INDEX IF NOT EXISTS execs_guardian_uniq ON execs (guardian_id) WHERE guardian_id IS NOT NULL;
+
+-- Одноразовые коды приглашения: по коду представитель сам заводит учётку.
+-- Храним только sha256 кода.
+CREATE TABLE IF NOT EXISTS guardian_invites (
+    id           BIGSERIAL PRIMARY KEY,
+    guardian_id  BIGINT      NOT NULL REFERENCES guardians (id) ON DELETE CASCADE,
+    code_hash    BYTEA       NOT NULL UNIQUE,
+    created_by   BIGINT      REFERENCES execs (id) ON DELETE SET NULL,
+    expires_at   TIMESTAMPTZ NOT NULL,
+    used_at      TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS guardian_invites_guardian_idx ON guardian_invites (guardian_id);
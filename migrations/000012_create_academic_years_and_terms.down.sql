@@ -0,0 +1,4 @@
+ALTER TABLE holidays DROP COLUMN IF EXISTS kind;
+
+DROP TABLE IF EXISTS terms;
+DROP TABLE IF EXISTS academic_years;
@@ -0,0 +1,33 @@
+-- Учебный год: year — год начала, как classes.academic_year (2025 — это 2025/2026).
+CREATE TABLE IF NOT EXISTS academic_years (
+    year        INT         PRIMARY KEY CHECK (year BETWEEN 2000 AND 2100),
+    name        TEXT        NOT NULL,
+    start_date  DATE        NOT NULL,
+    end_date    DATE        NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT academic_years_period_check CHECK (start_date < end_date),
+    CONSTRAINT academic_years_no_overlap EXCLUDE USING gist (daterange(start_date, end_date, '[]') WITH &&)
+);
+
+-- Учебный период (четверть/триместр) внутри года. number совпадает с assessments.term.
+-- Закрытый период (closed_at IS NOT NULL) замораживает оценки.
+CREATE TABLE IF NOT EXISTS terms (
+    id             BIGSERIAL PRIMARY KEY,
+    academic_year  INT         NOT NULL REFERENCES academic_years (year) ON DELETE CASCADE,
+    number         SMALLINT    NOT NULL CHECK (number BETWEEN 1 AND 4),
+    name           TEXT        NOT NULL,
+    start_date     DATE        NOT NULL,
+    end_date       DATE        NOT NULL,
+    closed_at      TIMESTAMPTZ,
+    closed_by      BIGINT      REFERENCES execs (id) ON DELETE SET NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT terms_year_number_uniq UNIQUE (academic_year, number),
+    CONSTRAINT terms_period_check CHECK (start_date <= end_date),
+    CONSTRAINT terms_no_overlap EXCLUDE USING gist (daterange(start_date, end_date, '[]') WITH &&)
+);
+
+-- holiday — каникулы по календарю, closure — внеплановое закрытие школы (карантин, погода).
+ALTER TABLE holidays
+    ADD COLUMN kind TEXT NOT NULL DEFAULT 'holiday' CHECK (kind IN ('holiday', 'closure'));
@@ -0,0 +1,16 @@
+-- Журнал переводов на следующий год: кто, когда и что именно сделал (план целиком).
+-- Перевод из учебного года выполняется один раз.
+CREATE TABLE IF NOT EXISTS promotion_runs (
+    id           BIGSERIAL PRIMARY KEY,
+    from_year    INT         NOT NULL UNIQUE,
+    to_year      INT         NOT NULL,
+    final_grade  SMALLINT    NOT NULL,
+    promoted     INT         NOT NULL,
+    held_back    INT         NOT NULL,
+    graduated    INT         NOT NULL,
+    skipped      INT         NOT NULL,
+    plan         JSONB       NOT NULL,
+    executed_by  BIGINT      REFERENCES execs (id) ON DELETE SET NULL,
+    executed_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT promotion_runs_years_check CHECK (to_year > from_year)
+);
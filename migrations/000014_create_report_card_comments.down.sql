@@ -0,0 +1 @@
+DROP TABLE IF EXISTS report_card_comments;
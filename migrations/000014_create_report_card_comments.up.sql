@@ -0,0 +1,15 @@
+-- Комментарий в табеле ученика за период: по предмету (subject_id) или общий от классного
+-- руководителя (subject_id IS NULL). На ученика, период и предмет — один комментарий.
+CREATE TABLE IF NOT EXISTS report_card_comments (
+    id          BIGSERIAL   PRIMARY KEY,
+    student_id  BIGINT      NOT NULL REFERENCES students (id) ON DELETE CASCADE,
+    term_id     BIGINT      NOT NULL REFERENCES terms (id) ON DELETE CASCADE,
+    subject_id  BIGINT      REFERENCES subjects (id) ON DELETE CASCADE,
+    author_id   BIGINT      REFERENCES execs (id) ON DELETE SET NULL,
+    body        TEXT        NOT NULL CHECK (length(body) BETWEEN 1 AND 1000),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS report_card_comments_uniq
+    ON report_card_comments (student_id, term_id, COALESCE(subject_id, 0));
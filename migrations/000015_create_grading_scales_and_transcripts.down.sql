@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS transcripts;
+DROP TABLE IF EXISTS grading_scale_bands;
+DROP TABLE IF EXISTS grading_scales;
+ALTER TABLE subjects DROP COLUMN IF EXISTS credits;
@@ -0,0 +1,57 @@
+-- Зачётные единицы предмета: с ними предмет входит в GPA выписки.
+ALTER TABLE subjects
+    ADD COLUMN IF NOT EXISTS credits NUMERIC(4, 1) NOT NULL DEFAULT 1 CHECK (credits > 0);
+
+-- Шкала оценивания: banded — интервалы процентов (bands), linear — процент пересчитывается
+-- пропорционально в 0..max_points. Шкала по умолчанию (is_default) — одна.
+CREATE TABLE IF NOT EXISTS grading_scales (
+    id          BIGSERIAL     PRIMARY KEY,
+    code        TEXT          NOT NULL UNIQUE,
+    name        TEXT          NOT NULL,
+    kind        TEXT          NOT NULL CHECK (kind IN ('banded', 'linear')),
+    max_points  NUMERIC(6, 2) NOT NULL CHECK (max_points > 0),
+    is_default  BOOLEAN       NOT NULL DEFAULT false,
+    created_at  TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    updated_at  TIMESTAMPTZ   NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS grading_scales_default_uniq ON grading_scales (is_default) WHERE is_default;
+
+-- Интервал шкалы: от min_percent включительно до min_percent следующего интервала.
+CREATE TABLE IF NOT EXISTS grading_scale_bands (
+    scale_id     BIGINT        NOT NULL REFERENCES grading_scales (id) ON DELETE CASCADE,
+    min_percent  NUMERIC(5, 2) NOT NULL CHECK (min_percent BETWEEN 0 AND 100),
+    label        TEXT          NOT NULL,
+    points       NUMERIC(6, 2) NOT NULL CHECK (points >= 0),
+    PRIMARY KEY (scale_id, min_percent),
+    CONSTRAINT grading_scale_bands_label_uniq UNIQUE (scale_id, label)
+);
+
+INSERT INTO grading_scales (code, name, kind, max_points, is_default) VALUES
+    ('five_point', '5-point',        'banded', 5,   true),
+    ('letter',     'Letter A–F',     'banded', 4,   false),
+    ('hundred',    '100-point',      'linear', 100, false)
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO grading_scale_bands (scale_id, min_percent, label, points)
+SELECT s.id, b.min_percent, b.label, b.points
+FROM grading_scales s
+JOIN (VALUES
+    ('five_point', 85, '5', 5), ('five_point', 70, '4', 4), ('five_point', 50, '3', 3), ('five_point', 0, '2', 2),
+    ('letter', 90, 'A', 4), ('letter', 80, 'B', 3), ('letter', 70, 'C', 2), ('letter', 60, 'D', 1), ('letter', 0, 'F', 0)
+) AS b (code, min_percent, label, points) ON b.code = s.code
+ON CONFLICT DO NOTHING;
+
+-- Выданная выписка: документ хранится как выдан, hash — SHA-256 документа, по нему выписку проверяют.
+CREATE TABLE IF NOT EXISTS transcripts (
+    hash           TEXT          PRIMARY KEY,
+    student_id     BIGINT        NOT NULL REFERENCES students (id) ON DELETE CASCADE,
+    scale_code     TEXT          NOT NULL,
+    gpa            NUMERIC(6, 2),
+    total_credits  NUMERIC(6, 1) NOT NULL,
+    document       JSONB         NOT NULL,
+    issued_by      BIGINT        REFERENCES execs (id) ON DELETE SET NULL,
+    issued_at      TIMESTAMPTZ   NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS transcripts_student_idx ON transcripts (student_id, issued_at DESC);
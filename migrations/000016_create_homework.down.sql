@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS homework_files;
+DROP TABLE IF EXISTS homework_submissions;
+DROP TABLE IF EXISTS homework;
@@ -0,0 +1,50 @@
+-- Домашнее задание классу по предмету. Оценки за него идут в журнал через работу (assessment_id),
+-- которая создаётся и удаляется вместе с заданием.
+CREATE TABLE IF NOT EXISTS homework (
+    id             BIGSERIAL     PRIMARY KEY,
+    class_id       BIGINT        NOT NULL REFERENCES classes (id) ON DELETE CASCADE,
+    subject_id     BIGINT        NOT NULL REFERENCES subjects (id) ON DELETE RESTRICT,
+    teacher_id     BIGINT        REFERENCES teachers (id) ON DELETE SET NULL,
+    assessment_id  BIGINT        NOT NULL UNIQUE REFERENCES assessments (id) ON DELETE RESTRICT,
+    title          TEXT          NOT NULL,
+    description    TEXT          NOT NULL DEFAULT '',
+    due_at         TIMESTAMPTZ   NOT NULL,
+    allow_late     BOOLEAN       NOT NULL DEFAULT true,
+    created_by     BIGINT        REFERENCES execs (id) ON DELETE SET NULL,
+    created_at     TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    updated_at     TIMESTAMPTZ   NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS homework_class_due_idx ON homework (class_id, due_at);
+
+-- Сдача задания учеником: одна на ученика, пересдаётся до проверки. late — сдано после срока.
+CREATE TABLE IF NOT EXISTS homework_submissions (
+    id            BIGSERIAL     PRIMARY KEY,
+    homework_id   BIGINT        NOT NULL REFERENCES homework (id) ON DELETE CASCADE,
+    student_id    BIGINT        NOT NULL REFERENCES students (id) ON DELETE CASCADE,
+    body          TEXT          NOT NULL DEFAULT '',
+    late          BOOLEAN       NOT NULL DEFAULT false,
+    submitted_at  TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    score         NUMERIC(6, 2) CHECK (score >= 0),
+    feedback      TEXT          NOT NULL DEFAULT '',
+    graded_by     BIGINT        REFERENCES execs (id) ON DELETE SET NULL,
+    graded_at     TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ   NOT NULL DEFAULT now(),
+    CONSTRAINT homework_submissions_uniq UNIQUE (homework_id, student_id)
+);
+
+-- Файлы: вложения задания (submission_id IS NULL) и файлы сдачи. Содержимое — в файловом
+-- хранилище по storage_key.
+CREATE TABLE IF NOT EXISTS homework_files (
+    id             BIGSERIAL   PRIMARY KEY,
+    homework_id    BIGINT      NOT NULL REFERENCES homework (id) ON DELETE CASCADE,
+    submission_id  BIGINT      REFERENCES homework_submissions (id) ON DELETE CASCADE,
+    storage_key    TEXT        NOT NULL UNIQUE,
+    file_name      TEXT        NOT NULL,
+    content_type   TEXT        NOT NULL,
+    size           BIGINT      NOT NULL CHECK (size >= 0),
+    uploaded_by    BIGINT      REFERENCES execs (id) ON DELETE SET NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS homework_files_homework_idx ON homework_files (homework_id, submission_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS excuse_files;
+DROP TABLE IF EXISTS student_photos;
@@ -0,0 +1,23 @@
+-- Фотография ученика: одна на ученика, содержимое — в файловом хранилище по storage_key.
+CREATE TABLE IF NOT EXISTS student_photos (
+    student_id    BIGINT      PRIMARY KEY REFERENCES students (id) ON DELETE CASCADE,
+    storage_key   TEXT        NOT NULL UNIQUE,
+    content_type  TEXT        NOT NULL,
+    size          BIGINT      NOT NULL CHECK (size >= 0),
+    uploaded_by   BIGINT      REFERENCES execs (id) ON DELETE SET NULL,
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Файлы к объяснительным (справки, заявления).
+CREATE TABLE IF NOT EXISTS excuse_files (
+    id            BIGSERIAL   PRIMARY KEY,
+    excuse_id     BIGINT      NOT NULL REFERENCES excuse_notes (id) ON DELETE CASCADE,
+    storage_key   TEXT        NOT NULL UNIQUE,
+    file_name     TEXT        NOT NULL,
+    content_type  TEXT        NOT NULL,
+    size          BIGINT      NOT NULL CHECK (size >= 0),
+    uploaded_by   BIGINT      REFERENCES execs (id) ON DELETE SET NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS excuse_files_excuse_idx ON excuse_files (excuse_id);